@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/iceisfun/goeip/internal"
+	"github.com/iceisfun/goeip/pkg/client"
+)
+
+// clock_sync compares the clocks of one or more controllers with the host
+// clock and, with -set, corrects those that drifted beyond -tolerance.
+//
+// The drift is measured against the midpoint of the request, so it includes
+// no more than half the round trip time.
+func main() {
+	addrs := flag.String("addr", "192.168.1.10:44818", "Comma separated PLC addresses (IP:Port)")
+	route := flag.String("route", "", "Route path to the controller, e.g. 1,0")
+	tolerance := flag.Duration("tolerance", time.Second, "Drift allowed before a clock is set")
+	set := flag.Bool("set", false, "Set clocks that drifted beyond the tolerance")
+	flag.Parse()
+
+	logger := internal.NewConsoleLogger()
+
+	failed := false
+	for _, addr := range strings.Split(*addrs, ",") {
+		addr = strings.TrimSpace(addr)
+		if addr == "" {
+			continue
+		}
+		if err := check(logger, addr, *route, *tolerance, *set); err != nil {
+			logger.Errorf("%s: %v", addr, err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func check(logger internal.Logger, addr, route string, tolerance time.Duration, set bool) error {
+	var opts []client.ClientOption
+	if route != "" {
+		opts = append(opts, client.WithRoutePath(route))
+	}
+	c, err := client.NewClient(addr, logger, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer c.Close()
+
+	start := time.Now()
+	clock, err := c.ReadWallClock()
+	if err != nil {
+		return err
+	}
+	rtt := time.Since(start)
+	host := start.Add(rtt / 2)
+	drift := clock.UTC.Sub(host)
+
+	fmt.Printf("%s\n", addr)
+	fmt.Printf("  Controller UTC:   %s\n", clock.UTC.Format(time.RFC3339Nano))
+	fmt.Printf("  Controller local: %s (DST %v)\n", clock.Local().Format(time.RFC3339), clock.ApplyDST)
+	fmt.Printf("  Host UTC:         %s\n", host.UTC().Format(time.RFC3339Nano))
+	fmt.Printf("  Drift:            %v (round trip %v)\n", drift.Round(time.Millisecond), rtt.Round(time.Millisecond))
+
+	if drift.Abs() <= tolerance {
+		fmt.Printf("  Within tolerance of %v\n", tolerance)
+		return nil
+	}
+	if !set {
+		fmt.Printf("  Beyond tolerance of %v, use -set to correct\n", tolerance)
+		return nil
+	}
+
+	// Aim for the host time at which the controller applies the request
+	start = time.Now()
+	if err := c.SetWallClock(start.Add(rtt / 2)); err != nil {
+		return err
+	}
+	fmt.Printf("  Clock set (took %v)\n", time.Since(start).Round(time.Millisecond))
+	return nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+
+	"github.com/iceisfun/goeip/internal"
+	"github.com/iceisfun/goeip/pkg/cip"
+	"github.com/iceisfun/goeip/pkg/client"
+	"github.com/iceisfun/goeip/pkg/codegen"
+)
+
+func main() {
+	address := flag.String("addr", "192.168.1.100:44818", "PLC Address (IP:Port)")
+	dump := flag.String("dump", "", "Read templates from a JSON dump instead of the PLC")
+	save := flag.String("save", "", "Write the fetched templates to a JSON dump")
+	pkg := flag.String("pkg", "plc", "Package name of the generated file")
+	out := flag.String("out", "types_gen.go", "Output file")
+	flag.Parse()
+
+	logger := internal.NewConsoleLogger()
+
+	var templates []*cip.Template
+	if *dump != "" {
+		data, err := os.ReadFile(*dump)
+		if err != nil {
+			logger.Errorf("Failed to read dump: %v", err)
+			os.Exit(1)
+		}
+		if err := json.Unmarshal(data, &templates); err != nil {
+			logger.Errorf("Failed to parse dump: %v", err)
+			os.Exit(1)
+		}
+	} else {
+		var err error
+		templates, err = fetchTemplates(*address, logger)
+		if err != nil {
+			logger.Errorf("%v", err)
+			os.Exit(1)
+		}
+	}
+
+	if *save != "" {
+		data, err := json.MarshalIndent(templates, "", "  ")
+		if err != nil {
+			logger.Errorf("Failed to encode dump: %v", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(*save, data, 0644); err != nil {
+			logger.Errorf("Failed to write dump: %v", err)
+			os.Exit(1)
+		}
+		logger.Infof("Saved %d templates to %s", len(templates), *save)
+	}
+
+	src, err := codegen.Generate(templates, codegen.Options{Package: *pkg})
+	if err != nil {
+		logger.Errorf("Failed to generate code: %v", err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(*out, src, 0644); err != nil {
+		logger.Errorf("Failed to write %s: %v", *out, err)
+		os.Exit(1)
+	}
+	logger.Infof("Generated %d types in %s", len(templates), *out)
+}
+
+// fetchTemplates connects to the PLC and reads the template of every
+// structure tag, including the templates of nested structures.
+func fetchTemplates(address string, logger internal.Logger) ([]*cip.Template, error) {
+	logger.Infof("Connecting to %s...", address)
+	c, err := client.NewClient(address, logger)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	tags, err := c.ListTags(client.IncludeProgramTags())
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[uint16]*cip.Template)
+	failed := make(map[uint16]bool)
+
+	// collect reads a template and its dependencies, reporting whether all
+	// of them could be read.
+	var collect func(id uint16) bool
+	collect = func(id uint16) bool {
+		if _, ok := found[id]; ok {
+			return true
+		}
+		if failed[id] {
+			return false
+		}
+
+		t, err := c.ReadTemplate(id)
+		if err != nil {
+			logger.Warnf("Skipping template 0x%04X: %v", id, err)
+			failed[id] = true
+			return false
+		}
+
+		// Mark as found before recursing so self references terminate
+		found[id] = t
+		for _, m := range t.Members {
+			if m.IsStructure() && !m.Hidden() && !collect(m.TemplateID()) {
+				logger.Warnf("Skipping %s: member %s has no template", t.Name, m.Name)
+				delete(found, id)
+				failed[id] = true
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, tag := range tags {
+		if tag.IsStructure() {
+			collect(tag.TemplateID())
+		}
+	}
+
+	templates := make([]*cip.Template, 0, len(found))
+	for _, t := range found {
+		templates = append(templates, t)
+	}
+	logger.Infof("Read %d templates", len(templates))
+	return templates, nil
+}
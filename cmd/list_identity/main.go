@@ -60,4 +60,23 @@ func main() {
 			fmt.Printf("  Name: %s\n", s.Name)
 		}
 	}
+
+	logger.Infof("Reading Identity Object...")
+	id, err := c.Identity()
+	if err != nil {
+		logger.Errorf("Failed to read identity: %v", err)
+		return
+	}
+	fmt.Printf("Identity Object:\n")
+	fmt.Printf("  Vendor: %s (%d)\n", id.VendorName(), id.VendorID)
+	fmt.Printf("  Device Type: %s (0x%02X)\n", id.DeviceTypeName(), id.DeviceType)
+	fmt.Printf("  Product Code: %d\n", id.ProductCode)
+	fmt.Printf("  Revision: %s\n", id.Revision)
+	fmt.Printf("  Serial Number: 0x%08X\n", id.SerialNumber)
+	fmt.Printf("  Product Name: %s\n", id.ProductName)
+	fmt.Printf("  Status: 0x%04X (%s, owned %v, configured %v)\n", uint16(id.Status), id.Status.Extended(), id.Status.Owned(), id.Status.Configured())
+	fmt.Printf("  Faults: minor %v, major %v\n", id.Status.MinorFault(), id.Status.MajorFault())
+	if id.VendorID == 1 && id.DeviceType == 0x0E {
+		fmt.Printf("  Key Switch: %s\n", id.Status.KeySwitch())
+	}
 }
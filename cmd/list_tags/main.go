@@ -11,8 +11,22 @@ import (
 
 func main() {
 	address := flag.String("addr", "192.168.1.100:44818", "PLC Address (IP:Port)")
+	programs := flag.Bool("programs", false, "Also list program-scoped tags")
+	system := flag.Bool("system", false, "Include system tags (__, Map:, Task:, modules)")
+	program := flag.String("program", "", "List the tags of this program only")
 	flag.Parse()
 
+	var opts []client.ListTagsOption
+	if *programs {
+		opts = append(opts, client.IncludeProgramTags())
+	}
+	if *system {
+		opts = append(opts, client.IncludeSystemTags())
+	}
+	if *program != "" {
+		opts = append(opts, client.InProgram(*program))
+	}
+
 	logger := internal.NewConsoleLogger()
 	logger.Infof("Connecting to %s...", *address)
 
@@ -24,7 +38,7 @@ func main() {
 	defer c.Close()
 
 	logger.Infof("Listing tags...")
-	tags, err := c.ListTags()
+	tags, err := c.ListTags(opts...)
 	if err != nil {
 		logger.Errorf("Failed to list tags: %v", err)
 		os.Exit(1)
@@ -32,6 +46,10 @@ func main() {
 
 	logger.Infof("Found %d tags:", len(tags))
 	for _, t := range tags {
-		fmt.Printf("ID: 0x%08X, Name: %-40s, Type: %s\n", t.InstanceID, t.Name, t.Type)
+		name := t.FullName()
+		if n := t.Dimensions(); n > 0 {
+			name += fmt.Sprintf("%v", t.Dims[:n])
+		}
+		fmt.Printf("ID: 0x%08X, Name: %-40s, Type: %-20s, Access: %s\n", t.InstanceID, name, t.Type, t.ExternalAccess)
 	}
 }
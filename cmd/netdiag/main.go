@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/iceisfun/goeip/internal"
+	"github.com/iceisfun/goeip/pkg/cip"
+	"github.com/iceisfun/goeip/pkg/client"
+	"github.com/iceisfun/goeip/pkg/objects/ethlink"
+)
+
+// netdiag prints the IP configuration and the state of each Ethernet port
+// of a device, and flags the usual suspects of a bad network: half duplex,
+// failed auto-negotiation, CRC errors and late collisions.
+func main() {
+	address := flag.String("addr", "192.168.1.10:44818", "Device Address (IP:Port)")
+	maxPorts := flag.Int("ports", 8, "Maximum number of Ethernet ports to query")
+	flag.Parse()
+
+	logger := internal.NewConsoleLogger()
+	c, err := client.NewClient(*address, logger)
+	if err != nil {
+		logger.Errorf("Failed to connect: %v", err)
+		os.Exit(1)
+	}
+	defer c.Close()
+
+	iface, err := c.ReadTCPIPInterface()
+	if err != nil {
+		logger.Errorf("Failed to read TCP/IP interface: %v", err)
+	} else {
+		cfg := iface.Config
+		fmt.Printf("TCP/IP Interface:\n")
+		fmt.Printf("  Host Name: %s\n", iface.HostName)
+		fmt.Printf("  IP Address: %s/%s (%s)\n", cfg.IP, cfg.Mask, iface.ConfigControl)
+		fmt.Printf("  Gateway: %s\n", cfg.Gateway)
+		fmt.Printf("  DNS: %s, %s\n", cfg.NameServer, cfg.NameServer2)
+		if cfg.DomainName != "" {
+			fmt.Printf("  Domain: %s\n", cfg.DomainName)
+		}
+		if iface.HasACD {
+			fmt.Printf("  Address Conflict Detection: enabled %v, conflict %v, fault %v\n",
+				iface.SelectACD, iface.Status.ACDConflict(), iface.Status.ACDFault())
+		}
+		if iface.Status.ACDConflict() {
+			fmt.Printf("  WARNING: another device uses this IP address\n")
+		}
+	}
+
+	for port := 1; port <= *maxPorts; port++ {
+		link, err := c.ReadEthernetLink(uint16(port))
+		if errors.Is(err, cip.ErrObjectDoesNotExist) || errors.Is(err, cip.ErrPathDestinationUnknown) {
+			break
+		}
+		if err != nil {
+			logger.Errorf("Failed to read Ethernet port %d: %v", port, err)
+			break
+		}
+		printLink(link)
+	}
+}
+
+func printLink(link *ethlink.Link) {
+	label := link.Label
+	if label == "" {
+		label = fmt.Sprintf("Port %d", link.Instance)
+	}
+	fmt.Printf("%s (%s):\n", label, link.MAC)
+
+	f := link.Flags
+	if !f.LinkActive() {
+		fmt.Printf("  Link: down\n")
+		return
+	}
+	duplex := "half"
+	if f.FullDuplex() {
+		duplex = "full"
+	}
+	fmt.Printf("  Link: %d Mbit/s, %s duplex\n", link.Speed, duplex)
+	fmt.Printf("  Auto-negotiation: %s\n", f.Negotiation())
+
+	var warnings []string
+	if f.HardwareFault() {
+		warnings = append(warnings, "hardware fault")
+	}
+	if !f.FullDuplex() {
+		warnings = append(warnings, "half duplex, check for a duplex mismatch with the switch port")
+	}
+	if n := f.Negotiation(); n == ethlink.NegotiationFailed || n == ethlink.NegotiationDuplexFail {
+		warnings = append(warnings, "auto-negotiation failed, set both ends to auto or force both ends")
+	}
+
+	if ic := link.Interface; ic != nil {
+		fmt.Printf("  In:  %d octets, %d unicast, %d non-unicast, %d discards, %d errors\n",
+			ic.InOctets, ic.InUcastPackets, ic.InNUcastPackets, ic.InDiscards, ic.InErrors)
+		fmt.Printf("  Out: %d octets, %d unicast, %d non-unicast, %d discards, %d errors\n",
+			ic.OutOctets, ic.OutUcastPackets, ic.OutNUcastPackets, ic.OutDiscards, ic.OutErrors)
+	}
+	if mc := link.Media; mc != nil {
+		fmt.Printf("  Media: %d CRC, %d alignment, %d late collisions, %d collisions, %d too long\n",
+			mc.FCSErrors, mc.AlignmentErrors, mc.LateCollisions, mc.SingleCollisions+mc.MultipleCollisions, mc.FrameTooLong)
+		if mc.FCSErrors > 0 || mc.AlignmentErrors > 0 {
+			warnings = append(warnings, "CRC or alignment errors, check cabling, connectors and grounding")
+		}
+		if mc.LateCollisions > 0 {
+			warnings = append(warnings, "late collisions, typical of a duplex mismatch")
+		}
+	}
+
+	for _, w := range warnings {
+		fmt.Printf("  WARNING: %s\n", w)
+	}
+}
@@ -1,8 +1,6 @@
 package main
 
 import (
-	"bytes"
-	"encoding/binary"
 	"flag"
 	"log"
 	"net"
@@ -22,6 +20,7 @@ func main() {
 		inputAssembly  = flag.Int("input-assembly", 100, "Input Assembly ID (Target -> Originator)")
 		outputAssembly = flag.Int("output-assembly", 150, "Output Assembly ID (Originator -> Target)")
 		rpi            = flag.Duration("rpi", 100*time.Millisecond, "RPI (Requested Packet Interval)")
+		size           = flag.Int("size", 36, "Connection size in bytes, up to 4002 (32 data + 4 header)")
 	)
 	flag.Parse()
 
@@ -81,33 +80,20 @@ func main() {
 	// But we can verify O->T (Scanner -> Adapter) works.
 
 	// 3. Send Forward_Open
-	// We need to construct the request manually or use a helper.
-	// `pkg/objects/connmgr` has the structs but they are internal to the package?
-	// No, I exported them in `types.go`.
-
-	// We need to encode ForwardOpenRequest.
-	// And wrap it in a MessageRouterRequest (Service 0x54, Class 0x06, Instance 1).
-
-	// Let's create a helper in `cmd/scanner` or just do it inline.
 
 	// Connection IDs
 	otConnID := uint32(0x10000001)
 	// toConnID := uint32(0) // Target will allocate
 
-	// Network Connection Params
-	// O->T: Point-to-Point, Scheduled, Low Priority?
-	// Size: 32 bytes (Assembly size) + Header?
-	// Let's use 500 bytes size limit.
-	// otParams := uint16(0x4000) | 500 // Owner, Fixed? No.
-	// 0x4200 ?
-	// Let's use 0 (default) for now or check spec.
-	// Bit 15: Owner (1)
-	// Bit 14: Fixed/Variable (0=Fixed)
-	// Bit 13: Priority (0=Low)
-	// Bit 9: Type (2=Point-to-Point)
-	// 0x4200 = Owner, P2P.
-
-	req := connmgr.ForwardOpenRequest{
+	// Network Connection Params: Point-to-Point, Low Priority, Variable size.
+	// The connection size covers the 32 bytes of assembly data plus the
+	// 4-byte Run/Idle header.
+	network := connmgr.NetworkParams{
+		Size:     *size,
+		Variable: true,
+		Type:     connmgr.ConnectionTypePointToPoint,
+	}
+	params := connmgr.OpenParams{
 		PriorityTimeTick:            0x0A, // 2^10 ms? No, Priority/Tick. 0x03 = 1ms?
 		TimeoutTicks:                249,
 		OTConnectionID:              cip.UDINT(otConnID),
@@ -116,13 +102,11 @@ func main() {
 		VendorID:                    0x1337,
 		OriginatorSerialNumber:      5678,
 		ConnectionTimeoutMultiplier: 1, // x4
-		Reserved:                    [3]cip.BYTE{0, 0, 0},
 		OTRPI:                       cip.UDINT(*rpi / time.Microsecond),
-		OTNetworkConnectionParams:   cip.WORD(0x4200 | 36), // 32 data + 4 header
 		TORPI:                       cip.UDINT(*rpi / time.Microsecond),
-		TONetworkConnectionParams:   cip.WORD(0x4200 | 36),
+		OT:                          network,
+		TO:                          network,
 		TransportTypeTrigger:        0x01, // Cyclic, Direction=Server?
-		ConnectionPathSize:          0,    // Calculated later
 	}
 
 	// Path:
@@ -152,43 +136,21 @@ func main() {
 	// Wait, standard path is:
 	// 20 04 24 96 2C 64
 
-	path := []byte{
+	params.ConnectionPath = cip.Path([]byte{
 		0x20, 0x04,
 		0x24, byte(*outputAssembly),
 		0x2C, byte(*inputAssembly),
-	}
-	req.ConnectionPathSize = cip.USINT(len(path) / 2)
-	req.ConnectionPath = path
-
-	// Encode Request
-	// We need a helper to encode ForwardOpenRequest since we only implemented Decode in connmgr.
-	// But we can just manually write it here.
-
-	// ... (Encoding logic omitted for brevity, assuming we can do it)
-	// Actually, I should have added Encode to connmgr/types.go.
-	// But I can't modify it now easily without context switch.
-	// I'll just write it manually.
-
-	// Send Request via Session
-	// Service 0x54, Path: Class 6, Instance 1
-	mrReq := &cip.MessageRouterRequest{
-		Service:     connmgr.ServiceForwardOpen,
-		RequestPath: cip.Path([]byte{0x20, 0x06, 0x24, 0x01}),
-		RequestData: encodeForwardOpen(&req),
-	}
+	})
 
-	resp, err := sess.SendCIPRequest(mrReq)
+	// Sizes above 511 bytes need Large_Forward_Open; targets without it
+	// get a plain Forward_Open with the sizes reduced
+	fo, params, err := connmgr.Open(sess.SendCIPRequest, params)
 	if err != nil {
 		log.Fatalf("Forward_Open failed: %v", err)
 	}
-	if resp.GeneralStatus != cip.StatusSuccess {
-		log.Fatalf("Forward_Open error: 0x%02X", resp.GeneralStatus)
-	}
 
-	log.Println("Forward_Open Successful!")
-
-	// Parse Response to get T->O Connection ID
-	// ...
+	log.Printf("Forward_Open Successful! (O->T 0x%08X, T->O 0x%08X, %d bytes)",
+		fo.OTConnectionID, fo.TOConnectionID, params.OT.Size)
 
 	// Start Producing
 	// Add Connection to Runtime
@@ -209,26 +171,11 @@ func main() {
 	time.Sleep(10 * time.Second)
 
 	// Forward_Close
-	// ...
-}
-
-func encodeForwardOpen(req *connmgr.ForwardOpenRequest) []byte {
-	buf := new(bytes.Buffer)
-	binary.Write(buf, binary.LittleEndian, req.PriorityTimeTick)
-	binary.Write(buf, binary.LittleEndian, req.TimeoutTicks)
-	binary.Write(buf, binary.LittleEndian, req.OTConnectionID)
-	binary.Write(buf, binary.LittleEndian, req.TOConnectionID)
-	binary.Write(buf, binary.LittleEndian, req.ConnectionSerialNumber)
-	binary.Write(buf, binary.LittleEndian, req.VendorID)
-	binary.Write(buf, binary.LittleEndian, req.OriginatorSerialNumber)
-	binary.Write(buf, binary.LittleEndian, req.ConnectionTimeoutMultiplier)
-	binary.Write(buf, binary.LittleEndian, req.Reserved)
-	binary.Write(buf, binary.LittleEndian, req.OTRPI)
-	binary.Write(buf, binary.LittleEndian, req.OTNetworkConnectionParams)
-	binary.Write(buf, binary.LittleEndian, req.TORPI)
-	binary.Write(buf, binary.LittleEndian, req.TONetworkConnectionParams)
-	binary.Write(buf, binary.LittleEndian, req.TransportTypeTrigger)
-	binary.Write(buf, binary.LittleEndian, req.ConnectionPathSize)
-	buf.Write(req.ConnectionPath)
-	return buf.Bytes()
+	resp, err := sess.SendCIPRequest(params.CloseRequest())
+	if err != nil {
+		log.Fatalf("Forward_Close failed: %v", err)
+	}
+	if err := resp.Error(); err != nil {
+		log.Fatalf("Forward_Close error: %v", err)
+	}
 }
@@ -0,0 +1,281 @@
+package cip
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Logix does not publish the memory layout of the alarm and message
+// structures, and it differs between firmware revisions. AlarmDigital,
+// AlarmAnalog and Message are therefore filled by member name from the
+// structure decoded with its template (see Template.Decode and
+// DecodeMembers); members missing from the template are left zero. They
+// are written back the same way, with EncodeMembers and Template.Encode.
+
+// AlarmDigital represents a Logix ALARM_DIGITAL structure, the tag of an
+// ALMD instruction.
+type AlarmDigital struct {
+	In           bool  // Input
+	InFault      bool  // Input quality is bad
+	Condition    bool  // Alarm when In is set (cleared: when clear)
+	AckRequired  bool  // Alarm requires acknowledgement
+	Latched      bool  // Alarm stays active until reset
+	Severity     int32 // 1 to 1000
+	EnableOut    bool  // Rung state
+	InAlarm      bool  // Alarm is active
+	Acked        bool  // Alarm is acknowledged
+	InAlarmUnack bool  // Alarm is active and not acknowledged
+	Suppressed   bool  // Alarm is suppressed
+	Disabled     bool  // Alarm is disabled
+
+	MinDurationPRE int32 // Minimum time before the alarm is active (ms)
+	MinDurationACC int32 // Time the condition has been true (ms)
+	AlarmCount     int32 // Number of times the alarm became active
+
+	// Timestamps, in microseconds since 1970-01-01 UTC
+	InAlarmTime         int64
+	AckTime             int64
+	RetToNormalTime     int64
+	AlarmCountResetTime int64
+
+	Status        AlarmStatus // Combined status flags
+	InstructFault bool        // Instruction parameters are invalid
+}
+
+// AlarmAnalog represents a Logix ALARM_ANALOG structure, the tag of an
+// ALMA instruction.
+type AlarmAnalog struct {
+	In      float32 // Input
+	InFault bool    // Input quality is bad
+
+	HHEnabled     bool
+	HEnabled      bool
+	LEnabled      bool
+	LLEnabled     bool
+	ROCPosEnabled bool
+	ROCNegEnabled bool
+
+	AckRequired bool
+	HHLimit     float32
+	HLimit      float32
+	LLimit      float32
+	LLLimit     float32
+	HHSeverity  int32
+	HSeverity   int32
+	LSeverity   int32
+	LLSeverity  int32
+	Deadband    float32
+	ROCPosLimit float32
+	ROCNegLimit float32
+	ROCPeriod   float32 // Rate of change period (s)
+
+	MinDurationPRE int32 // Minimum time before an alarm is active (ms)
+	MinDurationACC int32
+
+	EnableOut       bool
+	InAlarm         bool // Any alarm condition is active
+	AnyInAlarmUnack bool
+	HHInAlarm       bool
+	HInAlarm        bool
+	LInAlarm        bool
+	LLInAlarm       bool
+	ROCPosInAlarm   bool
+	ROCNegInAlarm   bool
+	ROC             float32 // Rate of change of In, per second
+
+	HHAcked     bool
+	HAcked      bool
+	LAcked      bool
+	LLAcked     bool
+	ROCPosAcked bool
+	ROCNegAcked bool
+
+	HHInAlarmUnack     bool
+	HInAlarmUnack      bool
+	LInAlarmUnack      bool
+	LLInAlarmUnack     bool
+	ROCPosInAlarmUnack bool
+	ROCNegInAlarmUnack bool
+
+	Suppressed bool
+	Disabled   bool
+
+	Status        AlarmStatus // Combined status flags
+	InstructFault bool        // Instruction parameters are invalid
+}
+
+// AlarmStatus is the combined status word of ALARM_DIGITAL and
+// ALARM_ANALOG. The limit, deadband and rate of change bits are only used
+// by ALARM_ANALOG.
+type AlarmStatus int32
+
+// Alarm status bits
+const (
+	AlarmStatusInstructFault  AlarmStatus = 1 << 0
+	AlarmStatusInFaulted      AlarmStatus = 1 << 1
+	AlarmStatusSeverityInv    AlarmStatus = 1 << 2
+	AlarmStatusAlarmLimitsInv AlarmStatus = 1 << 3
+	AlarmStatusDeadbandInv    AlarmStatus = 1 << 4
+	AlarmStatusROCPosLimitInv AlarmStatus = 1 << 5
+	AlarmStatusROCNegLimitInv AlarmStatus = 1 << 6
+	AlarmStatusROCPeriodInv   AlarmStatus = 1 << 7
+)
+
+// InstructFault reports whether the instruction parameters are invalid;
+// the other bits tell which one.
+func (s AlarmStatus) InstructFault() bool {
+	return s&AlarmStatusInstructFault != 0
+}
+
+// InFaulted reports whether the program flagged the input as bad.
+func (s AlarmStatus) InFaulted() bool {
+	return s&AlarmStatusInFaulted != 0
+}
+
+// SeverityInv reports whether a severity is outside 1 to 1000.
+func (s AlarmStatus) SeverityInv() bool {
+	return s&AlarmStatusSeverityInv != 0
+}
+
+// AlarmLimitsInv reports whether the alarm limits are out of order.
+func (s AlarmStatus) AlarmLimitsInv() bool {
+	return s&AlarmStatusAlarmLimitsInv != 0
+}
+
+// DeadbandInv reports whether the deadband is negative or wider than the
+// limits allow.
+func (s AlarmStatus) DeadbandInv() bool {
+	return s&AlarmStatusDeadbandInv != 0
+}
+
+// ROCPosLimitInv reports whether the positive rate of change limit is
+// invalid.
+func (s AlarmStatus) ROCPosLimitInv() bool {
+	return s&AlarmStatusROCPosLimitInv != 0
+}
+
+// ROCNegLimitInv reports whether the negative rate of change limit is
+// invalid.
+func (s AlarmStatus) ROCNegLimitInv() bool {
+	return s&AlarmStatusROCNegLimitInv != 0
+}
+
+// ROCPeriodInv reports whether the rate of change period is invalid.
+func (s AlarmStatus) ROCPeriodInv() bool {
+	return s&AlarmStatusROCPeriodInv != 0
+}
+
+// Message represents a Logix MESSAGE structure, the tag of an MSG
+// instruction. Members whose Logix names are not Go names map to their
+// field with a `cip:"name=..."` tag.
+type Message struct {
+	EN   bool // Enable
+	EW   bool // Enabled and waiting
+	ST   bool // Started
+	DN   bool // Done
+	ER   bool // Error
+	TO   bool // Timeout
+	ENCC bool `cip:"name=EN_CC"` // Enable cache
+
+	ERR    int16 // Error code
+	EXERR  int32 // Extended error code
+	ErrSrc int8  `cip:"name=ERR_SRC"` // Error source
+	DNLen  int16 `cip:"name=DN_LEN"`  // Number of elements transferred
+	ReqLen int16 `cip:"name=REQ_LEN"` // Number of elements to transfer
+
+	DestinationLink    int16
+	DestinationNode    int16
+	SourceLink         int16
+	Class              int16
+	Attribute          int16
+	Instance           int32
+	LocalIndex         int32
+	RemoteIndex        int32
+	UnconnectedTimeout int32 // µs
+	ConnectionRate     int32
+	TimeoutMultiplier  int8
+}
+
+// DecodeMembers fills the exported fields of the struct pointed to by dst
+// from members, a structure decoded with Template.Decode. Fields are
+// matched to members by name, or by the name given in a `cip:"name=..."`
+// tag; numbers are converted to the field type and members without a field
+// are ignored.
+func DecodeMembers(members map[string]any, dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cip: DecodeMembers(non-struct pointer %T)", dst)
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		name, ok, err := memberName(sf)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		member, ok := members[name]
+		if !ok {
+			continue
+		}
+
+		mv := reflect.ValueOf(member)
+		fv := rv.Field(i)
+		sameKind := (mv.Kind() == reflect.Bool) == (fv.Kind() == reflect.Bool) &&
+			(mv.Kind() == reflect.String) == (fv.Kind() == reflect.String)
+		if !sameKind || !mv.Type().ConvertibleTo(fv.Type()) {
+			return fmt.Errorf("cip: member %s of type %T does not fit %s", name, member, fv.Type())
+		}
+		fv.Set(mv.Convert(fv.Type()))
+	}
+	return nil
+}
+
+// EncodeMembers is the reverse of DecodeMembers: it returns the exported
+// fields of src, a struct or a pointer to one, keyed by member name, ready
+// for Template.Encode.
+func EncodeMembers(src any) (map[string]any, error) {
+	rv := reflect.ValueOf(src)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cip: EncodeMembers(non-struct %T)", src)
+	}
+	rt := rv.Type()
+
+	members := make(map[string]any, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		name, ok, err := memberName(rt.Field(i))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			members[name] = rv.Field(i).Interface()
+		}
+	}
+	return members, nil
+}
+
+// memberName returns the structure member held by an exported field, or
+// false if the field is unexported or tagged "-".
+func memberName(sf reflect.StructField) (string, bool, error) {
+	if !sf.IsExported() {
+		return "", false, nil
+	}
+	opts, err := parseTagOptions(sf.Tag.Get("cip"))
+	if err != nil {
+		return "", false, fmt.Errorf("cip: field %s: %w", sf.Name, err)
+	}
+	if opts.skip {
+		return "", false, nil
+	}
+	if opts.name != "" {
+		return opts.name, true, nil
+	}
+	return sf.Name, true, nil
+}
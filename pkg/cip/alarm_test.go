@@ -0,0 +1,64 @@
+package cip
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeMembers(t *testing.T) {
+	members := map[string]any{
+		"InAlarm":     true,
+		"Severity":    int32(500),
+		"InAlarmTime": int64(1700000000000000),
+		"Unknown":     int16(1),
+	}
+
+	var a AlarmDigital
+	if err := DecodeMembers(members, &a); err != nil {
+		t.Fatalf("DecodeMembers() error = %v", err)
+	}
+	if !a.InAlarm || a.Severity != 500 || a.InAlarmTime != 1700000000000000 {
+		t.Errorf("DecodeMembers() = %+v", a)
+	}
+
+	// A DINT member fits a SINT field by conversion, a BOOL does not
+	var m Message
+	if err := DecodeMembers(map[string]any{"ERR_SRC": int32(2)}, &m); err != nil || m.ErrSrc != 2 {
+		t.Errorf("ErrSrc = %d, %v", m.ErrSrc, err)
+	}
+	if err := DecodeMembers(map[string]any{"ERR": true}, &m); err == nil {
+		t.Error("expected error for BOOL member in INT field")
+	}
+}
+
+func TestEncodeMembers(t *testing.T) {
+	m := Message{EN: true, ENCC: true, ErrSrc: 3, DNLen: 10, ReqLen: 12}
+	members, err := EncodeMembers(&m)
+	if err != nil {
+		t.Fatalf("EncodeMembers() error = %v", err)
+	}
+	for name, want := range map[string]any{"EN": true, "EN_CC": true, "ERR_SRC": int8(3), "DN_LEN": int16(10), "REQ_LEN": int16(12)} {
+		if got := members[name]; !reflect.DeepEqual(got, want) {
+			t.Errorf("members[%s] = %#v, want %#v", name, got, want)
+		}
+	}
+	if _, ok := members["ENCC"]; ok {
+		t.Error("field name used instead of member name")
+	}
+
+	var back Message
+	if err := DecodeMembers(members, &back); err != nil || back != m {
+		t.Errorf("DecodeMembers(EncodeMembers()) = %+v, %v", back, err)
+	}
+
+	if _, err := EncodeMembers(42); err == nil {
+		t.Error("expected error for non-struct")
+	}
+}
+
+func TestAlarmStatus(t *testing.T) {
+	s := AlarmStatus(0x0D) // InstructFault, SeverityInv, AlarmLimitsInv
+	if !s.InstructFault() || s.InFaulted() || !s.SeverityInv() || !s.AlarmLimitsInv() || s.DeadbandInv() {
+		t.Errorf("AlarmStatus(0x%02X) bits wrong", int32(s))
+	}
+}
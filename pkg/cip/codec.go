@@ -0,0 +1,633 @@
+package cip
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Marshal and Unmarshal lay out Go values the way Logix lays out tags and
+// UDT members:
+//
+//   - Atomic members are aligned to their own size (INT 2, DINT/REAL 4,
+//     LINT/LREAL 8).
+//   - Consecutive bool fields share a host byte, up to 8 per byte.
+//   - Arrays and strings are aligned to 4 bytes; BOOL arrays are packed into
+//     32-bit words.
+//   - Structures are aligned to 4 bytes (8 when they hold an 8-byte member)
+//     and padded to a multiple of their alignment.
+//   - string is a Logix STRING: a DINT length followed by the characters.
+//   - time.Time is a DATE_AND_TIME and time.Duration an LTIME.
+//
+// Struct fields can adjust the layout with a `cip` tag holding a comma
+// separated list of options:
+//
+//	offset=N  byte offset of the field within the structure
+//	bit=N     bit of a bool field, counted from offset (default: the offset
+//	          of the previous field, so a bool can select a bit of a DINT)
+//	type=T    CIP type of a number, e.g. SINT or DWORD, or of a time.Time
+//	          or time.Duration (see datetime.go)
+//	len=N     length of a slice, or character capacity of a string (82)
+//	size=N    encoded size of a field implementing Marshaler/Unmarshaler
+//	name=S    name of the structure member the field holds, for
+//	          DecodeMembers and EncodeMembers (default: the field name)
+//
+// A tag of "-" skips the field. Unexported fields are always skipped.
+
+// defaultStringLength is the character capacity of the Logix STRING type.
+const defaultStringLength = 82
+
+type codecKind int
+
+const (
+	kindAtomic codecKind = iota
+	kindBool
+	kindBoolArray
+	kindString
+	kindArray
+	kindStruct
+	kindCustom
+	kindTime
+	kindDuration
+)
+
+// codecType is the layout of a Go type in CIP encoding.
+type codecType struct {
+	kind     codecKind
+	dataType DataType // kindAtomic, kindTime, kindDuration
+	size     int      // Encoded size in bytes, including padding
+	align    int
+	count    int // Elements of an array, or capacity of a string; -1 if taken from the value
+	elem     *codecType
+	fields   []codecField
+	used     int // Bytes actually covered by struct fields
+}
+
+// codecField is one struct field placed in a structure.
+type codecField struct {
+	index  int
+	offset int
+	bit    int // kindBool only
+	typ    *codecType
+}
+
+// tagOptions holds the parsed `cip` struct tag of a field.
+type tagOptions struct {
+	skip     bool
+	offset   int
+	bit      int
+	dataType DataType
+	length   int
+	size     int
+	name     string
+}
+
+var noOptions = tagOptions{offset: -1, bit: -1, length: -1, size: -1}
+
+var structLayouts sync.Map // reflect.Type -> *codecType
+
+var (
+	marshalerType   = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+)
+
+func parseTagOptions(tag string) (tagOptions, error) {
+	opts := noOptions
+	if tag == "" {
+		return opts, nil
+	}
+	if tag == "-" {
+		opts.skip = true
+		return opts, nil
+	}
+
+	for _, part := range strings.Split(tag, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return opts, fmt.Errorf("cip: invalid tag option %q", part)
+		}
+
+		if key == "name" {
+			if value == "" {
+				return opts, fmt.Errorf("cip: empty member name")
+			}
+			opts.name = value
+			continue
+		}
+		if key == "type" {
+			dt, ok := parseTypeName(value)
+			if !ok {
+				return opts, fmt.Errorf("cip: unknown type %q", value)
+			}
+			opts.dataType = dt
+			continue
+		}
+
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return opts, fmt.Errorf("cip: invalid value for %s: %q", key, value)
+		}
+		switch key {
+		case "offset":
+			opts.offset = n
+		case "bit":
+			opts.bit = n
+		case "len":
+			opts.length = n
+		case "size":
+			opts.size = n
+		default:
+			return opts, fmt.Errorf("cip: unknown tag option %q", key)
+		}
+	}
+	return opts, nil
+}
+
+func parseTypeName(name string) (DataType, bool) {
+	for dt, n := range typeNames {
+		if strings.EqualFold(n, name) {
+			return dt, true
+		}
+	}
+	return 0, false
+}
+
+// defaultAtomicType maps a Go numeric kind to its CIP type.
+func defaultAtomicType(k reflect.Kind) DataType {
+	switch k {
+	case reflect.Int8:
+		return TypeSINT
+	case reflect.Int16:
+		return TypeINT
+	case reflect.Int32, reflect.Int:
+		return TypeDINT
+	case reflect.Int64:
+		return TypeLINT
+	case reflect.Uint8:
+		return TypeUSINT
+	case reflect.Uint16:
+		return TypeUINT
+	case reflect.Uint32, reflect.Uint:
+		return TypeUDINT
+	case reflect.Uint64:
+		return TypeULINT
+	case reflect.Float32:
+		return TypeREAL
+	case reflect.Float64:
+		return TypeLREAL
+	}
+	return 0
+}
+
+func alignUp(n, align int) int {
+	return (n + align - 1) / align * align
+}
+
+// layoutOf returns the layout of rt with the given field options.
+func layoutOf(rt reflect.Type, opts tagOptions) (*codecType, error) {
+	ptr := reflect.PointerTo(rt)
+	if ptr.Implements(marshalerType) || ptr.Implements(unmarshalerType) {
+		return customLayout(rt, opts)
+	}
+	if rt == timeType || rt == durationType {
+		return timeLayout(rt, opts.dataType)
+	}
+
+	switch rt.Kind() {
+	case reflect.Bool:
+		return &codecType{kind: kindBool, dataType: TypeBOOL, size: 1, align: 1}, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		dt := opts.dataType
+		if dt == 0 {
+			dt = defaultAtomicType(rt.Kind())
+		}
+		size := AtomicSize(dt)
+		if size == 0 || dt == TypeBOOL {
+			return nil, fmt.Errorf("cip: cannot encode %s as %s", rt, dt)
+		}
+		return &codecType{kind: kindAtomic, dataType: dt, size: size, align: size}, nil
+
+	case reflect.String:
+		capacity := opts.length
+		if capacity < 0 {
+			capacity = defaultStringLength
+		}
+		return &codecType{kind: kindString, dataType: TypeSTRING, size: alignUp(4+capacity, 4), align: 4, count: capacity}, nil
+
+	case reflect.Array:
+		return arrayLayout(rt.Elem(), rt.Len(), opts)
+
+	case reflect.Slice:
+		count := opts.length
+		opts.length = -1
+		return arrayLayout(rt.Elem(), count, opts)
+
+	case reflect.Struct:
+		if cached, ok := structLayouts.Load(rt); ok {
+			return cached.(*codecType), nil
+		}
+		ct, err := structLayout(rt)
+		if err != nil {
+			return nil, err
+		}
+		structLayouts.Store(rt, ct)
+		return ct, nil
+	}
+
+	return nil, fmt.Errorf("cip: unsupported type %s", rt)
+}
+
+// arrayLayout returns the layout of count elements of elem. A count of -1
+// means the length is taken from the value being encoded.
+func arrayLayout(elem reflect.Type, count int, opts tagOptions) (*codecType, error) {
+	if elem.Kind() == reflect.Bool {
+		ct := &codecType{kind: kindBoolArray, dataType: TypeDWORD, align: 4, count: count}
+		if count >= 0 {
+			ct.size = (count + 31) / 32 * 4
+		}
+		return ct, nil
+	}
+
+	et, err := layoutOf(elem, opts)
+	if err != nil {
+		return nil, err
+	}
+	if et.kind == kindArray || et.kind == kindBoolArray {
+		return nil, fmt.Errorf("cip: multi-dimensional arrays are not supported")
+	}
+
+	ct := &codecType{kind: kindArray, elem: et, align: max(4, et.align), count: count}
+	if count >= 0 {
+		ct.size = count * et.size
+	}
+	return ct, nil
+}
+
+// customLayout returns the layout of a type implementing Marshaler or
+// Unmarshaler. Its size comes from the size option or from marshaling the
+// zero value.
+func customLayout(rt reflect.Type, opts tagOptions) (*codecType, error) {
+	size := opts.size
+	if size < 0 {
+		m, ok := reflect.New(rt).Interface().(Marshaler)
+		if !ok {
+			return nil, fmt.Errorf("cip: %s needs a size option", rt)
+		}
+		b, err := m.MarshalCIP()
+		if err != nil {
+			return nil, fmt.Errorf("cip: sizing %s: %w", rt, err)
+		}
+		size = len(b)
+	}
+	return &codecType{kind: kindCustom, size: alignUp(size, 4), align: 4, used: size}, nil
+}
+
+func structLayout(rt reflect.Type) (*codecType, error) {
+	ct := &codecType{kind: kindStruct, align: 4}
+
+	cursor := 0
+	hostOffset, hostBits := -1, 0
+	prevOffset := 0
+
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		opts, err := parseTagOptions(sf.Tag.Get("cip"))
+		if err != nil {
+			return nil, fmt.Errorf("%w (field %s.%s)", err, rt.Name(), sf.Name)
+		}
+		if opts.skip {
+			continue
+		}
+
+		ft, err := layoutOf(sf.Type, opts)
+		if err != nil {
+			return nil, fmt.Errorf("%w (field %s.%s)", err, rt.Name(), sf.Name)
+		}
+		if ft.count < 0 {
+			return nil, fmt.Errorf("cip: slice field %s.%s needs a len option", rt.Name(), sf.Name)
+		}
+
+		f := codecField{index: i, typ: ft}
+		switch {
+		case ft.kind == kindBool && opts.bit >= 0:
+			// An explicit bit overlays earlier data and does not move the cursor
+			base := opts.offset
+			if base < 0 {
+				base = prevOffset
+			}
+			f.offset = base + opts.bit/8
+			f.bit = opts.bit % 8
+			hostOffset = -1
+
+		case ft.kind == kindBool && opts.offset < 0:
+			// Pack into the current host byte, or start a new one
+			if hostOffset < 0 || hostBits == 8 {
+				hostOffset, hostBits = cursor, 0
+				cursor++
+			}
+			f.offset = hostOffset
+			f.bit = hostBits
+			hostBits++
+			prevOffset = f.offset
+
+		default:
+			if opts.offset >= 0 {
+				f.offset = opts.offset
+			} else {
+				f.offset = alignUp(cursor, ft.align)
+			}
+			cursor = f.offset + ft.size
+			hostOffset = -1
+			prevOffset = f.offset
+			ct.align = max(ct.align, ft.align)
+		}
+
+		ct.used = max(ct.used, f.offset+ft.size)
+		ct.fields = append(ct.fields, f)
+	}
+
+	ct.size = alignUp(ct.used, ct.align)
+	return ct, nil
+}
+
+// topLevelLayout returns the layout of v, resolving slice lengths from the
+// value or, when decoding into an empty slice, from the data length.
+func topLevelLayout(v reflect.Value, dataLen int) (*codecType, error) {
+	ct, err := layoutOf(v.Type(), noOptions)
+	if err != nil {
+		return nil, err
+	}
+	if ct.count >= 0 || (ct.kind != kindArray && ct.kind != kindBoolArray) {
+		return ct, nil
+	}
+
+	count := v.Len()
+	if count == 0 && dataLen >= 0 {
+		if ct.kind == kindBoolArray {
+			count = dataLen / 4 * 32
+		} else {
+			count = dataLen / ct.elem.size
+		}
+	}
+	return arrayLayout(v.Type().Elem(), count, noOptions)
+}
+
+// addressable returns v itself when it can be addressed, or an addressable
+// copy so that methods with pointer receivers can be called.
+func addressable(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v
+	}
+	p := reflect.New(v.Type())
+	p.Elem().Set(v)
+	return p.Elem()
+}
+
+func marshalValue(v any) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("cip: Marshal(nil %T)", v)
+		}
+		rv = rv.Elem()
+	}
+	rv = addressable(rv)
+
+	ct, err := topLevelLayout(rv, -1)
+	if err != nil {
+		return nil, err
+	}
+	if ct.kind == kindCustom {
+		// No structure around it, so no padding either
+		return rv.Addr().Interface().(Marshaler).MarshalCIP()
+	}
+
+	data := make([]byte, ct.size)
+	if err := ct.encode(data, rv); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func unmarshalValue(data []byte, rv reflect.Value) error {
+	ct, err := topLevelLayout(rv, len(data))
+	if err != nil {
+		return err
+	}
+
+	need := ct.size
+	if ct.kind == kindStruct || ct.kind == kindCustom {
+		need = ct.used
+	}
+	if len(data) < need {
+		return fmt.Errorf("cip: %s needs %d bytes, got %d", rv.Type(), need, len(data))
+	}
+	if len(data) < ct.size {
+		// Structures may be sent without trailing padding
+		data = append(data[:len(data):len(data)], make([]byte, ct.size-len(data))...)
+	}
+	return ct.decode(data, rv)
+}
+
+// encode writes v into data, which holds at least ct.size zeroed bytes.
+func (ct *codecType) encode(data []byte, v reflect.Value) error {
+	switch ct.kind {
+	case kindAtomic:
+		encodeAtomic(ct.dataType, data, v)
+
+	case kindBool:
+		if v.Bool() {
+			data[0] = 1
+		}
+
+	case kindBoolArray:
+		if v.Len() > ct.count {
+			return fmt.Errorf("cip: %d BOOLs do not fit in %d", v.Len(), ct.count)
+		}
+		for i := 0; i < v.Len(); i++ {
+			if v.Index(i).Bool() {
+				data[i/8] |= 1 << (i % 8)
+			}
+		}
+
+	case kindString:
+		b, err := StringType{Capacity: ct.count}.Encode(v.String())
+		if err != nil {
+			return err
+		}
+		copy(data, b)
+
+	case kindArray:
+		if v.Len() > ct.count {
+			return fmt.Errorf("cip: %d elements do not fit in %d", v.Len(), ct.count)
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := ct.elem.encode(data[i*ct.elem.size:], v.Index(i)); err != nil {
+				return err
+			}
+		}
+
+	case kindStruct:
+		for _, f := range ct.fields {
+			fv := v.Field(f.index)
+			if f.typ.kind == kindBool {
+				if fv.Bool() {
+					data[f.offset] |= 1 << f.bit
+				}
+				continue
+			}
+			if err := f.typ.encode(data[f.offset:], fv); err != nil {
+				return err
+			}
+		}
+
+	case kindCustom:
+		m, ok := addressable(v).Addr().Interface().(Marshaler)
+		if !ok {
+			return fmt.Errorf("cip: %s does not implement Marshaler", v.Type())
+		}
+		b, err := m.MarshalCIP()
+		if err != nil {
+			return err
+		}
+		if len(b) > ct.used {
+			return fmt.Errorf("cip: %s encoded to %d bytes, expected at most %d", v.Type(), len(b), ct.used)
+		}
+		copy(data, b)
+
+	case kindTime:
+		b, err := EncodeTime(ct.dataType, v.Interface().(time.Time))
+		if err != nil {
+			return err
+		}
+		copy(data, b)
+
+	case kindDuration:
+		b, err := EncodeDuration(ct.dataType, time.Duration(v.Int()))
+		if err != nil {
+			return err
+		}
+		copy(data, b)
+	}
+	return nil
+}
+
+// decode reads v from data, which holds at least ct.size bytes.
+func (ct *codecType) decode(data []byte, v reflect.Value) error {
+	switch ct.kind {
+	case kindAtomic:
+		val, err := DecodeAtomic(ct.dataType, data)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(val).Convert(v.Type()))
+
+	case kindBool:
+		v.SetBool(data[0] != 0)
+
+	case kindBoolArray:
+		if v.Kind() == reflect.Slice && v.Len() != ct.count {
+			v.Set(reflect.MakeSlice(v.Type(), ct.count, ct.count))
+		}
+		for i := 0; i < v.Len(); i++ {
+			v.Index(i).SetBool(data[i/8]&(1<<(i%8)) != 0)
+		}
+
+	case kindString:
+		s, err := StringType{Capacity: ct.count}.Decode(data[:ct.size])
+		if err != nil {
+			return err
+		}
+		v.SetString(s)
+
+	case kindArray:
+		if v.Kind() == reflect.Slice && v.Len() != ct.count {
+			v.Set(reflect.MakeSlice(v.Type(), ct.count, ct.count))
+		}
+		for i := 0; i < ct.count; i++ {
+			if err := ct.elem.decode(data[i*ct.elem.size:], v.Index(i)); err != nil {
+				return err
+			}
+		}
+
+	case kindStruct:
+		for _, f := range ct.fields {
+			fv := v.Field(f.index)
+			if f.typ.kind == kindBool {
+				fv.SetBool(data[f.offset]&(1<<f.bit) != 0)
+				continue
+			}
+			if err := f.typ.decode(data[f.offset:], fv); err != nil {
+				return err
+			}
+		}
+
+	case kindCustom:
+		u, ok := v.Addr().Interface().(Unmarshaler)
+		if !ok {
+			return fmt.Errorf("cip: %s does not implement Unmarshaler", v.Type())
+		}
+		return u.UnmarshalCIP(data[:ct.used])
+
+	case kindTime:
+		t, err := DecodeTime(ct.dataType, data)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+
+	case kindDuration:
+		d, err := DecodeDuration(ct.dataType, data)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+	}
+	return nil
+}
+
+// encodeAtomic writes the number held by v as data type t.
+func encodeAtomic(t DataType, data []byte, v reflect.Value) {
+	var i int64
+	var f float64
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i = v.Int()
+		f = float64(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i = int64(v.Uint())
+		f = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		f = v.Float()
+		i = int64(f)
+	}
+
+	switch t {
+	case TypeREAL:
+		binary.LittleEndian.PutUint32(data, math.Float32bits(float32(f)))
+	case TypeLREAL:
+		binary.LittleEndian.PutUint64(data, math.Float64bits(f))
+	default:
+		switch AtomicSize(t) {
+		case 1:
+			data[0] = byte(i)
+		case 2:
+			binary.LittleEndian.PutUint16(data, uint16(i))
+		case 4:
+			binary.LittleEndian.PutUint32(data, uint32(i))
+		case 8:
+			binary.LittleEndian.PutUint64(data, uint64(i))
+		}
+	}
+}
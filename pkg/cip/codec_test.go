@@ -0,0 +1,225 @@
+package cip
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type codecPoint struct {
+	X int16
+	Y int32
+}
+
+type codecUDT struct {
+	Run    bool
+	Fault  bool
+	Count  int8
+	Speed  float32
+	Total  float64
+	Pos    codecPoint
+	Path   [2]codecPoint
+	Flags  [40]bool
+	Name   string  `cip:"len=6"`
+	Scales []int16 `cip:"len=3"`
+	Code   int     `cip:"type=SINT"`
+	Status uint32
+	EN     bool   `cip:"bit=31"`
+	DN     bool   `cip:"bit=29"`
+	Note   string `cip:"-"`
+	hidden int32
+}
+
+var codecUDTValue = codecUDT{
+	Run:    true,
+	Count:  -2,
+	Speed:  1.5,
+	Total:  -1,
+	Pos:    codecPoint{X: 1, Y: 2},
+	Path:   [2]codecPoint{{X: 3, Y: 4}, {X: 5, Y: 6}},
+	Name:   "ABC",
+	Scales: []int16{7, 8, 9},
+	Code:   -3,
+	Status: 0x80000001,
+	EN:     true,
+}
+
+func init() {
+	codecUDTValue.Flags[0] = true
+	codecUDTValue.Flags[33] = true
+}
+
+var codecUDTBytes = []byte{
+	0x01, 0xFE, 0x00, 0x00, // Run|Fault host, Count, pad
+	0x00, 0x00, 0xC0, 0x3F, // Speed
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xF0, 0xBF, // Total
+	0x01, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, // Pos
+	0x03, 0x00, 0x00, 0x00, 0x04, 0x00, 0x00, 0x00, // Path[0]
+	0x05, 0x00, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, // Path[1]
+	0x01, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, // Flags
+	0x03, 0x00, 0x00, 0x00, 'A', 'B', 'C', 0x00, 0x00, 0x00, 0x00, 0x00, // Name
+	0x07, 0x00, 0x08, 0x00, 0x09, 0x00, // Scales
+	0xFD, 0x00, // Code, pad
+	0x01, 0x00, 0x00, 0x80, // Status, EN is bit 31
+}
+
+func TestMarshal_LogixLayout(t *testing.T) {
+	got, err := Marshal(codecUDTValue)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !bytes.Equal(got, codecUDTBytes) {
+		t.Errorf("Marshal() =\n% X\nwant\n% X", got, codecUDTBytes)
+	}
+}
+
+func TestUnmarshal_LogixLayout(t *testing.T) {
+	var got codecUDT
+	if err := Unmarshal(codecUDTBytes, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, codecUDTValue) {
+		t.Errorf("Unmarshal() =\n%+v\nwant\n%+v", got, codecUDTValue)
+	}
+}
+
+func TestMarshal_RoundTrip(t *testing.T) {
+	// Bits set in the data but not in the value must survive the round trip
+	data := append([]byte(nil), codecUDTBytes...)
+	data[len(data)-1] |= 0x20 // DN
+
+	var decoded codecUDT
+	if err := Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	encoded, err := Marshal(&decoded)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !decoded.DN {
+		t.Error("DN not decoded from bit 29")
+	}
+	if !bytes.Equal(encoded, data) {
+		t.Errorf("round trip =\n% X\nwant\n% X", encoded, data)
+	}
+}
+
+func TestCodec_Layout(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		size  int
+	}{
+		{"bools share a byte", struct{ A, B, C bool }{}, 4},
+		{"ninth bool starts a new byte", struct{ A, B, C, D, E, F, G, H, I bool }{}, 4},
+		{"int after sint is aligned", struct {
+			A int8
+			B int16
+		}{}, 4},
+		{"lint aligns structure to 8", struct {
+			A int8
+			B int64
+		}{}, 16},
+		{"array aligned to 4", struct {
+			A int8
+			B [2]int8
+		}{}, 8},
+		{"default string", struct{ S string }{}, 88},
+		{"explicit offset", struct {
+			A int32 `cip:"offset=8"`
+		}{}, 12},
+		{"embedded timer", struct {
+			A int8
+			T Timer
+		}{}, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if len(got) != tt.size {
+				t.Errorf("Marshal() size = %d, want %d", len(got), tt.size)
+			}
+		})
+	}
+}
+
+func TestCodec_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{"slice field without len", struct{ A []int32 }{}},
+		{"unknown type", struct {
+			A int32 `cip:"type=FOO"`
+		}{}},
+		{"unknown option", struct {
+			A int32 `cip:"foo=1"`
+		}{}},
+		{"string too long", struct {
+			S string `cip:"len=2"`
+		}{S: "abc"}},
+		{"too many elements", struct {
+			A []int8 `cip:"len=1"`
+		}{A: []int8{1, 2}}},
+		{"bool as number type", struct {
+			A int32 `cip:"type=BOOL"`
+		}{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Marshal(tt.value); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+// codecBlob is a Marshaler whose encoding grows with n, so it can outgrow
+// the size measured from its zero value.
+type codecBlob struct{ n int }
+
+func (b *codecBlob) MarshalCIP() ([]byte, error) { return make([]byte, 2+b.n), nil }
+
+func TestMarshal_CustomTooLong(t *testing.T) {
+	// The layout reserves 2 bytes padded to 4; 3 bytes fit the padding but
+	// would not be read back, so they are rejected too
+	for _, n := range []int{1, 4} {
+		_, err := Marshal(struct{ B codecBlob }{codecBlob{n}})
+		if err == nil {
+			t.Errorf("n = %d: expected error", n)
+			continue
+		}
+		if want := fmt.Sprintf("encoded to %d bytes, expected at most 2", 2+n); !strings.Contains(err.Error(), want) {
+			t.Errorf("n = %d: error = %q, want it to contain %q", n, err, want)
+		}
+	}
+
+	if _, err := Marshal(struct{ B codecBlob }{}); err != nil {
+		t.Errorf("Marshal() error = %v", err)
+	}
+}
+
+func TestUnmarshal_Slice(t *testing.T) {
+	var got []int16
+	if err := Unmarshal([]byte{0x01, 0x00, 0x02, 0x00, 0x03, 0x00}, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, []int16{1, 2, 3}) {
+		t.Errorf("Unmarshal() = %v, want [1 2 3]", got)
+	}
+}
+
+func TestUnmarshal_ShortData(t *testing.T) {
+	var got codecUDT
+	if err := Unmarshal(codecUDTBytes[:20], &got); err == nil {
+		t.Error("expected error for short data")
+	}
+}
@@ -0,0 +1,182 @@
+package cip
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// statusBit ties a bit of a status word to a bool field.
+type statusBit struct {
+	bit   uint
+	field *bool
+}
+
+func unpackStatus(word uint32, bits []statusBit) {
+	for _, b := range bits {
+		*b.field = word&(1<<b.bit) != 0
+	}
+}
+
+func packStatus(bits []statusBit) uint32 {
+	var word uint32
+	for _, b := range bits {
+		if *b.field {
+			word |= 1 << b.bit
+		}
+	}
+	return word
+}
+
+// Control represents a Logix CONTROL structure, used by file, sequencer,
+// shift and ASCII instructions.
+//
+// Memory Layout (12 bytes):
+// Offset 0-3: Status Bits (DINT)
+// Offset 4-7: LEN (DINT)
+// Offset 8-11: POS (DINT)
+type Control struct {
+	LEN int32 // Length
+	POS int32 // Position
+	EN  bool  // Enable
+	EU  bool  // Enable Unload
+	DN  bool  // Done
+	EM  bool  // Empty
+	ER  bool  // Error
+	UL  bool  // Unload
+	IN  bool  // Inhibit
+	FD  bool  // Found
+}
+
+// Bit positions of the CONTROL status bits
+const (
+	ControlStatusEN = 31
+	ControlStatusEU = 30
+	ControlStatusDN = 29
+	ControlStatusEM = 28
+	ControlStatusER = 27
+	ControlStatusUL = 26
+	ControlStatusIN = 25
+	ControlStatusFD = 24
+)
+
+func (c *Control) bits() []statusBit {
+	return []statusBit{
+		{ControlStatusEN, &c.EN},
+		{ControlStatusEU, &c.EU},
+		{ControlStatusDN, &c.DN},
+		{ControlStatusEM, &c.EM},
+		{ControlStatusER, &c.ER},
+		{ControlStatusUL, &c.UL},
+		{ControlStatusIN, &c.IN},
+		{ControlStatusFD, &c.FD},
+	}
+}
+
+// DecodeControl decodes a byte slice into a Control struct.
+func DecodeControl(data []byte) (*Control, error) {
+	if len(data) < 12 {
+		return nil, fmt.Errorf("insufficient data for Control: expected at least 12 bytes, got %d", len(data))
+	}
+
+	c := &Control{
+		LEN: int32(binary.LittleEndian.Uint32(data[4:8])),
+		POS: int32(binary.LittleEndian.Uint32(data[8:12])),
+	}
+	unpackStatus(binary.LittleEndian.Uint32(data[0:4]), c.bits())
+	return c, nil
+}
+
+// UnmarshalCIP implements the Unmarshaler interface for Control.
+func (c *Control) UnmarshalCIP(data []byte) error {
+	decoded, err := DecodeControl(data)
+	if err != nil {
+		return err
+	}
+	*c = *decoded
+	return nil
+}
+
+// MarshalCIP implements the Marshaler interface for Control.
+func (c *Control) MarshalCIP() ([]byte, error) {
+	data := make([]byte, 12)
+	binary.LittleEndian.PutUint32(data[0:4], packStatus(c.bits()))
+	binary.LittleEndian.PutUint32(data[4:8], uint32(c.LEN))
+	binary.LittleEndian.PutUint32(data[8:12], uint32(c.POS))
+	return data, nil
+}
+
+// SerialPortControl represents a Logix SERIAL_PORT_CONTROL structure, used
+// by the ASCII serial port instructions (ARD, ARL, AWA, AWT, ...).
+//
+// Memory Layout (12 bytes), like CONTROL:
+// Offset 0-3: Status Bits (DINT)
+// Offset 4-7: LEN (DINT)
+// Offset 8-11: POS (DINT)
+type SerialPortControl struct {
+	LEN int32 // Number of characters to send or receive
+	POS int32 // Number of characters sent or received
+	EN  bool  // Enable
+	EU  bool  // Queue
+	DN  bool  // Done
+	RN  bool  // Run
+	EM  bool  // Synchronous mode
+	ER  bool  // Error
+	FD  bool  // Found
+}
+
+// Bit positions of the SERIAL_PORT_CONTROL status bits
+const (
+	SerialPortStatusEN = 31
+	SerialPortStatusEU = 30
+	SerialPortStatusDN = 29
+	SerialPortStatusRN = 28
+	SerialPortStatusEM = 27
+	SerialPortStatusER = 26
+	SerialPortStatusFD = 25
+)
+
+func (s *SerialPortControl) bits() []statusBit {
+	return []statusBit{
+		{SerialPortStatusEN, &s.EN},
+		{SerialPortStatusEU, &s.EU},
+		{SerialPortStatusDN, &s.DN},
+		{SerialPortStatusRN, &s.RN},
+		{SerialPortStatusEM, &s.EM},
+		{SerialPortStatusER, &s.ER},
+		{SerialPortStatusFD, &s.FD},
+	}
+}
+
+// DecodeSerialPortControl decodes a byte slice into a SerialPortControl
+// struct.
+func DecodeSerialPortControl(data []byte) (*SerialPortControl, error) {
+	if len(data) < 12 {
+		return nil, fmt.Errorf("insufficient data for SerialPortControl: expected at least 12 bytes, got %d", len(data))
+	}
+
+	s := &SerialPortControl{
+		LEN: int32(binary.LittleEndian.Uint32(data[4:8])),
+		POS: int32(binary.LittleEndian.Uint32(data[8:12])),
+	}
+	unpackStatus(binary.LittleEndian.Uint32(data[0:4]), s.bits())
+	return s, nil
+}
+
+// UnmarshalCIP implements the Unmarshaler interface for SerialPortControl.
+func (s *SerialPortControl) UnmarshalCIP(data []byte) error {
+	decoded, err := DecodeSerialPortControl(data)
+	if err != nil {
+		return err
+	}
+	*s = *decoded
+	return nil
+}
+
+// MarshalCIP implements the Marshaler interface for SerialPortControl.
+func (s *SerialPortControl) MarshalCIP() ([]byte, error) {
+	data := make([]byte, 12)
+	binary.LittleEndian.PutUint32(data[0:4], packStatus(s.bits()))
+	binary.LittleEndian.PutUint32(data[4:8], uint32(s.LEN))
+	binary.LittleEndian.PutUint32(data[8:12], uint32(s.POS))
+	return data, nil
+}
@@ -0,0 +1,45 @@
+package cip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestDecodeControl(t *testing.T) {
+	data := make([]byte, 12)
+	binary.LittleEndian.PutUint32(data[0:4], 1<<ControlStatusEN|1<<ControlStatusDN|1<<ControlStatusFD)
+	binary.LittleEndian.PutUint32(data[4:8], 10)
+	binary.LittleEndian.PutUint32(data[8:12], 4)
+
+	c, err := DecodeControl(data)
+	if err != nil {
+		t.Fatalf("DecodeControl() error = %v", err)
+	}
+	want := Control{LEN: 10, POS: 4, EN: true, DN: true, FD: true}
+	if *c != want {
+		t.Errorf("DecodeControl() = %+v, want %+v", *c, want)
+	}
+
+	encoded, _ := c.MarshalCIP()
+	if !bytes.Equal(encoded, data) {
+		t.Errorf("MarshalCIP() = % X, want % X", encoded, data)
+	}
+
+	if _, err := DecodeControl(data[:8]); err == nil {
+		t.Error("expected error for short data")
+	}
+}
+
+func TestSerialPortControl_RoundTrip(t *testing.T) {
+	s := SerialPortControl{LEN: 82, POS: 12, EN: true, RN: true, ER: true}
+	data, _ := s.MarshalCIP()
+
+	var got SerialPortControl
+	if err := got.UnmarshalCIP(data); err != nil {
+		t.Fatalf("UnmarshalCIP() error = %v", err)
+	}
+	if got != s {
+		t.Errorf("round trip = %+v, want %+v", got, s)
+	}
+}
@@ -0,0 +1,190 @@
+package cip
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Date and time types map to time.Time and duration types to
+// time.Duration:
+//
+//	DATE           UINT, days since 1972-01-01
+//	TIME_OF_DAY    UDINT, milliseconds since midnight
+//	DATE_AND_TIME  TIME_OF_DAY followed by DATE (6 bytes)
+//	LINT           microseconds since 1970-01-01, the Logix timestamp format
+//	               (wall clock, alarm and event times)
+//	ITIME          INT, milliseconds
+//	TIME           DINT, milliseconds
+//	FTIME          DINT, microseconds
+//	LTIME          LINT, microseconds
+//
+// Times are decoded in UTC. TIME_OF_DAY decodes to that time on
+// 1972-01-01 and encodes only the time of day.
+
+// DateEpoch is day 0 of the CIP DATE type.
+var DateEpoch = time.Date(1972, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// timeSize returns the encoded size of a date and time type for
+// time.Time, or 0.
+func timeSize(t DataType) int {
+	switch t {
+	case TypeDATE:
+		return 2
+	case TypeTIME_OF_DAY:
+		return 4
+	case TypeDATE_AND_TIME:
+		return 6
+	case TypeLINT:
+		return 8
+	}
+	return 0
+}
+
+// durationSize returns the encoded size of a duration type for
+// time.Duration, or 0.
+func durationSize(t DataType) int {
+	switch t {
+	case TypeITIME:
+		return 2
+	case TypeTIME, TypeFTIME:
+		return 4
+	case TypeLTIME, TypeLINT:
+		return 8
+	}
+	return 0
+}
+
+// EncodeTime encodes v as data type t: DATE, TIME_OF_DAY, DATE_AND_TIME
+// or LINT microseconds since 1970.
+func EncodeTime(t DataType, v time.Time) ([]byte, error) {
+	v = v.UTC()
+	midnight := time.Date(v.Year(), v.Month(), v.Day(), 0, 0, 0, 0, time.UTC)
+	ms := uint32(v.Sub(midnight) / time.Millisecond)
+	days := int64(midnight.Sub(DateEpoch) / (24 * time.Hour))
+
+	if (t == TypeDATE || t == TypeDATE_AND_TIME) && (v.Before(DateEpoch) || days > 0xFFFF) {
+		return nil, fmt.Errorf("cip: %s out of range for %s", v.Format(time.RFC3339), t)
+	}
+
+	data := make([]byte, timeSize(t))
+	switch t {
+	case TypeDATE:
+		binary.LittleEndian.PutUint16(data, uint16(days))
+	case TypeTIME_OF_DAY:
+		binary.LittleEndian.PutUint32(data, ms)
+	case TypeDATE_AND_TIME:
+		binary.LittleEndian.PutUint32(data, ms)
+		binary.LittleEndian.PutUint16(data[4:], uint16(days))
+	case TypeLINT:
+		binary.LittleEndian.PutUint64(data, uint64(v.UnixMicro()))
+	default:
+		return nil, fmt.Errorf("cip: %s is not a date and time type", t)
+	}
+	return data, nil
+}
+
+// DecodeTime decodes a value of data type t as encoded by EncodeTime.
+func DecodeTime(t DataType, data []byte) (time.Time, error) {
+	size := timeSize(t)
+	if size == 0 {
+		return time.Time{}, fmt.Errorf("cip: %s is not a date and time type", t)
+	}
+	if len(data) < size {
+		return time.Time{}, fmt.Errorf("cip: %s needs %d bytes, got %d", t, size, len(data))
+	}
+
+	switch t {
+	case TypeDATE:
+		return DateEpoch.AddDate(0, 0, int(binary.LittleEndian.Uint16(data))), nil
+	case TypeTIME_OF_DAY:
+		return DateEpoch.Add(time.Duration(binary.LittleEndian.Uint32(data)) * time.Millisecond), nil
+	case TypeDATE_AND_TIME:
+		day := DateEpoch.AddDate(0, 0, int(binary.LittleEndian.Uint16(data[4:])))
+		return day.Add(time.Duration(binary.LittleEndian.Uint32(data)) * time.Millisecond), nil
+	default:
+		return time.UnixMicro(int64(binary.LittleEndian.Uint64(data))).UTC(), nil
+	}
+}
+
+// EncodeDuration encodes d as data type t: ITIME or TIME milliseconds,
+// FTIME microseconds, or LTIME or LINT microseconds.
+func EncodeDuration(t DataType, d time.Duration) ([]byte, error) {
+	data := make([]byte, durationSize(t))
+	switch t {
+	case TypeITIME:
+		ms := d.Milliseconds()
+		if ms < -1<<15 || ms >= 1<<15 {
+			return nil, fmt.Errorf("cip: %v out of range for %s", d, t)
+		}
+		binary.LittleEndian.PutUint16(data, uint16(ms))
+	case TypeTIME, TypeFTIME:
+		n := d.Milliseconds()
+		if t == TypeFTIME {
+			n = d.Microseconds()
+		}
+		if n < -1<<31 || n >= 1<<31 {
+			return nil, fmt.Errorf("cip: %v out of range for %s", d, t)
+		}
+		binary.LittleEndian.PutUint32(data, uint32(n))
+	case TypeLTIME, TypeLINT:
+		binary.LittleEndian.PutUint64(data, uint64(d.Microseconds()))
+	default:
+		return nil, fmt.Errorf("cip: %s is not a duration type", t)
+	}
+	return data, nil
+}
+
+// DecodeDuration decodes a value of data type t as encoded by
+// EncodeDuration.
+func DecodeDuration(t DataType, data []byte) (time.Duration, error) {
+	size := durationSize(t)
+	if size == 0 {
+		return 0, fmt.Errorf("cip: %s is not a duration type", t)
+	}
+	if len(data) < size {
+		return 0, fmt.Errorf("cip: %s needs %d bytes, got %d", t, size, len(data))
+	}
+
+	switch t {
+	case TypeITIME:
+		return time.Duration(int16(binary.LittleEndian.Uint16(data))) * time.Millisecond, nil
+	case TypeTIME:
+		return time.Duration(int32(binary.LittleEndian.Uint32(data))) * time.Millisecond, nil
+	case TypeFTIME:
+		return time.Duration(int32(binary.LittleEndian.Uint32(data))) * time.Microsecond, nil
+	default:
+		return time.Duration(int64(binary.LittleEndian.Uint64(data))) * time.Microsecond, nil
+	}
+}
+
+// timeLayout returns the layout of time.Time or time.Duration encoded as
+// data type t (0 for the default), or an error if t does not fit.
+func timeLayout(rt reflect.Type, t DataType) (*codecType, error) {
+	if rt == timeType {
+		if t == 0 {
+			t = TypeDATE_AND_TIME
+		}
+		if size := timeSize(t); size > 0 {
+			align := size
+			if t == TypeDATE_AND_TIME {
+				align = 4 // Aligned like its TIME_OF_DAY
+			}
+			return &codecType{kind: kindTime, dataType: t, size: size, align: align}, nil
+		}
+	} else {
+		if t == 0 {
+			t = TypeLTIME
+		}
+		if size := durationSize(t); size > 0 {
+			return &codecType{kind: kindDuration, dataType: t, size: size, align: size}, nil
+		}
+	}
+	return nil, fmt.Errorf("cip: cannot encode %s as %s", rt, t)
+}
@@ -0,0 +1,197 @@
+package cip
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEncodeDecodeTime(t *testing.T) {
+	v := time.Date(2024, 3, 15, 13, 45, 30, 250*int(time.Millisecond), time.UTC)
+	days := uint16(v.Sub(DateEpoch) / (24 * time.Hour)) // 19067
+	ms := uint32((13*3600+45*60+30)*1000 + 250)
+
+	tests := []struct {
+		dataType DataType
+		want     []byte
+		decoded  time.Time
+	}{
+		{TypeDATE, []byte{byte(days), byte(days >> 8)}, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{TypeTIME_OF_DAY, []byte{byte(ms), byte(ms >> 8), byte(ms >> 16), byte(ms >> 24)}, DateEpoch.Add(time.Duration(ms) * time.Millisecond)},
+		{TypeDATE_AND_TIME, []byte{byte(ms), byte(ms >> 8), byte(ms >> 16), byte(ms >> 24), byte(days), byte(days >> 8)}, v},
+		{TypeLINT, []byte{0x10, 0x83, 0x9E, 0x38, 0xB3, 0x13, 0x06, 0x00}, v},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dataType.String(), func(t *testing.T) {
+			data, err := EncodeTime(tt.dataType, v.In(time.FixedZone("EST", -5*3600)))
+			if err != nil {
+				t.Fatalf("EncodeTime failed: %v", err)
+			}
+			if !bytes.Equal(data, tt.want) {
+				t.Errorf("EncodeTime = % X, want % X", data, tt.want)
+			}
+
+			got, err := DecodeTime(tt.dataType, data)
+			if err != nil {
+				t.Fatalf("DecodeTime failed: %v", err)
+			}
+			if !got.Equal(tt.decoded) || got.Location() != time.UTC {
+				t.Errorf("DecodeTime = %v, want %v", got, tt.decoded)
+			}
+		})
+	}
+}
+
+func TestEncodeTimeErrors(t *testing.T) {
+	if _, err := EncodeTime(TypeDATE, time.Date(1971, 12, 31, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Error("expected error for a date before 1972")
+	}
+	if _, err := EncodeTime(TypeDATE_AND_TIME, DateEpoch.AddDate(0, 0, 0x10000)); err == nil {
+		t.Error("expected error for a date beyond the DATE range")
+	}
+	if _, err := EncodeTime(TypeDINT, DateEpoch); err == nil {
+		t.Error("expected error for DINT")
+	}
+	if _, err := DecodeTime(TypeDATE_AND_TIME, []byte{1, 2, 3, 4}); err == nil {
+		t.Error("expected error for short data")
+	}
+}
+
+func TestEncodeDecodeDuration(t *testing.T) {
+	tests := []struct {
+		dataType DataType
+		d        time.Duration
+		want     []byte
+	}{
+		{TypeITIME, -1500 * time.Millisecond, []byte{0x24, 0xFA}},
+		{TypeTIME, 90 * time.Second, []byte{0x90, 0x5F, 0x01, 0x00}},
+		{TypeFTIME, 1500 * time.Microsecond, []byte{0xDC, 0x05, 0x00, 0x00}},
+		{TypeLTIME, 2 * time.Hour, []byte{0x00, 0x48, 0x27, 0xAD, 0x01, 0x00, 0x00, 0x00}},
+		{TypeLINT, -time.Millisecond, []byte{0x18, 0xFC, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dataType.String(), func(t *testing.T) {
+			data, err := EncodeDuration(tt.dataType, tt.d)
+			if err != nil {
+				t.Fatalf("EncodeDuration failed: %v", err)
+			}
+			if !bytes.Equal(data, tt.want) {
+				t.Errorf("EncodeDuration = % X, want % X", data, tt.want)
+			}
+
+			got, err := DecodeDuration(tt.dataType, data)
+			if err != nil {
+				t.Fatalf("DecodeDuration failed: %v", err)
+			}
+			if got != tt.d {
+				t.Errorf("DecodeDuration = %v, want %v", got, tt.d)
+			}
+		})
+	}
+
+	if _, err := EncodeDuration(TypeITIME, time.Minute); err == nil {
+		t.Error("expected error for a duration beyond the ITIME range")
+	}
+	if _, err := EncodeDuration(TypeTIME, 30*24*time.Hour); err == nil {
+		t.Error("expected error for a duration beyond the TIME range")
+	}
+}
+
+type batchRecord struct {
+	ID      int16
+	Start   time.Time     `cip:"type=LINT"`
+	Day     time.Time     `cip:"type=DATE"`
+	Elapsed time.Duration `cip:"type=TIME"`
+	Stamp   time.Time
+	Hold    time.Duration
+}
+
+func TestMarshalTime(t *testing.T) {
+	start := time.Date(2024, 3, 15, 6, 0, 0, 0, time.UTC)
+	in := batchRecord{
+		ID:      7,
+		Start:   start,
+		Day:     time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+		Elapsed: 42 * time.Second,
+		Stamp:   start.Add(time.Hour),
+		Hold:    1500 * time.Microsecond,
+	}
+
+	data, err := Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	// ID 0, Start 8 (LINT aligned), Day 16, Elapsed 20, Stamp 24 (6 bytes),
+	// Hold 32 (LTIME aligned), padded to 40
+	if len(data) != 40 {
+		t.Fatalf("len = %d, want 40", len(data))
+	}
+	want, _ := EncodeTime(TypeLINT, start)
+	if !bytes.Equal(data[8:16], want) {
+		t.Errorf("Start = % X, want % X", data[8:16], want)
+	}
+
+	var out batchRecord
+	if err := Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !out.Start.Equal(in.Start) || !out.Day.Equal(in.Day) || !out.Stamp.Equal(in.Stamp) ||
+		out.Elapsed != in.Elapsed || out.Hold != in.Hold || out.ID != in.ID {
+		t.Errorf("Unmarshal = %+v, want %+v", out, in)
+	}
+
+	var d time.Duration
+	if err := Unmarshal([]byte{0xE8, 0x03, 0, 0, 0, 0, 0, 0}, &d); err != nil || d != time.Millisecond {
+		t.Errorf("Unmarshal(LTIME) = %v, %v; want 1ms", d, err)
+	}
+}
+
+func TestGoTypeToCIPTypeTime(t *testing.T) {
+	tests := []struct {
+		v    any
+		want DataType
+	}{
+		{time.Now(), TypeDATE_AND_TIME},
+		{time.Second, TypeLTIME},
+		{[]time.Time{}, TypeDATE_AND_TIME},
+		{[2]time.Duration{}, TypeLTIME},
+	}
+	for _, tt := range tests {
+		got, err := GoTypeToCIPType(tt.v)
+		if err != nil || got != tt.want {
+			t.Errorf("GoTypeToCIPType(%T) = %v, %v; want %v", tt.v, got, err, tt.want)
+		}
+	}
+}
+
+func TestUnmarshalTagTime(t *testing.T) {
+	stamp := time.Date(2024, 3, 15, 13, 45, 30, 0, time.UTC)
+	value, _ := EncodeTime(TypeLINT, stamp)
+
+	var got time.Time
+	if err := UnmarshalTag([]byte{0xC5, 0x00}, value, &got); err != nil {
+		t.Fatalf("UnmarshalTag failed: %v", err)
+	}
+	if !got.Equal(stamp) {
+		t.Errorf("got %v, want %v", got, stamp)
+	}
+
+	// A LINT is not read as nanoseconds
+	var d time.Duration
+	if err := UnmarshalTag([]byte{0xC5, 0x00}, []byte{0x40, 0x42, 0x0F, 0, 0, 0, 0, 0}, &d); err != nil || d != time.Second {
+		t.Errorf("UnmarshalTag(LINT) = %v, %v; want 1s", d, err)
+	}
+
+	if err := UnmarshalTag([]byte{0xC4, 0x00}, value[:4], &got); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("DINT into time.Time: got %v, want type mismatch", err)
+	}
+
+	data, err := MarshalTag([]byte{0xC5, 0x00}, 0, stamp)
+	if err != nil || !bytes.Equal(data, value) {
+		t.Errorf("MarshalTag(LINT) = % X, %v; want % X", data, err, value)
+	}
+	if _, err := MarshalTag([]byte{0xCA, 0x00}, 0, time.Second); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("time.Duration into REAL: got %v, want type mismatch", err)
+	}
+}
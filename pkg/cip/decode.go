@@ -1,8 +1,6 @@
 package cip
 
 import (
-	"bytes"
-	"encoding/binary"
 	"fmt"
 	"reflect"
 )
@@ -27,18 +25,6 @@ func Unmarshal(data []byte, v any) error {
 		return u.UnmarshalCIP(data)
 	}
 
-	// 2. Handle basic types and structs using binary.Read
-	// binary.Read handles:
-	// - bool, int8, uint8, int16, uint16, int32, uint32, int64, uint64, float32, float64, complex64, complex128
-	// - Arrays of the above
-	// - Structs containing only the above (recursively)
-	// It uses LittleEndian by default for CIP? Yes, CIP is Little Endian.
-
-	// We need a reader
-	r := bytes.NewReader(data)
-	if err := binary.Read(r, binary.LittleEndian, v); err != nil {
-		return fmt.Errorf("cip: binary.Read failed: %w", err)
-	}
-
-	return nil
+	// 2. Everything else follows the Logix layout rules, see codec.go
+	return unmarshalValue(data, rv.Elem())
 }
@@ -1,9 +1,9 @@
 package cip
 
 import (
-	"bytes"
-	"encoding/binary"
 	"fmt"
+	"reflect"
+	"time"
 )
 
 // Marshaler is the interface implemented by types that can marshal
@@ -12,20 +12,21 @@ type Marshaler interface {
 	MarshalCIP() ([]byte, error)
 }
 
-// Marshal returns the CIP encoding of v.
+// Marshal returns the CIP encoding of v. Structs are laid out with Logix
+// alignment and can be tuned with `cip` struct tags, see codec.go.
 func Marshal(v any) ([]byte, error) {
 	// 1. Check if v implements Marshaler
 	if m, ok := v.(Marshaler); ok {
 		return m.MarshalCIP()
 	}
 
-	// 2. Handle basic types and structs using binary.Write
-	buf := new(bytes.Buffer)
-	if err := binary.Write(buf, binary.LittleEndian, v); err != nil {
-		return nil, fmt.Errorf("cip: binary.Write failed: %w", err)
+	// 2. BOOL arrays are stored as packed 32-bit words on Logix
+	if bits, ok := v.([]bool); ok {
+		return packBoolArray(bits)
 	}
 
-	return buf.Bytes(), nil
+	// 3. Everything else follows the Logix layout rules, see codec.go
+	return marshalValue(v)
 }
 
 // GoTypeToCIPType maps a Go type to a CIP Data Type.
@@ -55,7 +56,76 @@ func GoTypeToCIPType(v any) (DataType, error) {
 		return TypeLREAL, nil
 	case string:
 		return TypeSTRING, nil // Default to standard STRING for now
-	default:
-		return 0, fmt.Errorf("cip: unsupported Go type for automatic mapping: %T", v)
+	case time.Time:
+		return TypeDATE_AND_TIME, nil
+	case time.Duration:
+		return TypeLTIME, nil
 	}
+
+	// BOOL arrays are packed into DWORDs
+	if _, ok := boolArrayLen(v); ok {
+		return TypeDWORD, nil
+	}
+
+	// Slices and arrays map to the type of their elements
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		elem := rv.Type().Elem()
+		if elem.Kind() != reflect.Slice && elem.Kind() != reflect.Array {
+			return GoTypeToCIPType(reflect.Zero(elem).Interface())
+		}
+	}
+
+	return 0, fmt.Errorf("cip: unsupported Go type for automatic mapping: %T", v)
+}
+
+// ElementCount returns the number of CIP elements represented by v.
+// Slices and arrays count one element per entry, except []bool and [N]bool
+// which count one DWORD per 32 bits. Any other value is a single element.
+func ElementCount(v any) (uint16, error) {
+	if n, ok := boolArrayLen(v); ok {
+		if n%32 != 0 {
+			return 0, fmt.Errorf("cip: BOOL array length %d is not a multiple of 32", n)
+		}
+		if n/32 > 0xFFFF {
+			return 0, fmt.Errorf("cip: too many elements: %d", n/32)
+		}
+		return uint16(n / 32), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		if rv.Len() > 0xFFFF {
+			return 0, fmt.Errorf("cip: too many elements: %d", rv.Len())
+		}
+		return uint16(rv.Len()), nil
+	}
+	return 1, nil
+}
+
+// boolArrayLen returns the length of v if it is a slice or array of bool.
+func boolArrayLen(v any) (int, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return 0, false
+	}
+	if rv.Type().Elem().Kind() != reflect.Bool {
+		return 0, false
+	}
+	return rv.Len(), true
+}
+
+// packBoolArray packs bools into little-endian 32-bit words, bit 0 of the
+// first word holding element 0.
+func packBoolArray(bits []bool) ([]byte, error) {
+	if len(bits)%32 != 0 {
+		return nil, fmt.Errorf("cip: BOOL array length %d is not a multiple of 32", len(bits))
+	}
+	out := make([]byte, len(bits)/8)
+	for i, b := range bits {
+		if b {
+			out[i/8] |= 1 << (i % 8)
+		}
+	}
+	return out, nil
 }
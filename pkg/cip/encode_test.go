@@ -22,6 +22,21 @@ func TestMarshal(t *testing.T) {
 			input: float32(1.0),
 			want:  []byte{0x00, 0x00, 0x80, 0x3F},
 		},
+		{
+			name:  "[]int16",
+			input: []int16{1, -1},
+			want:  []byte{0x01, 0x00, 0xFF, 0xFF},
+		},
+		{
+			name:  "[]bool packed",
+			input: append([]bool{true, false, true}, make([]bool, 29)...),
+			want:  []byte{0x05, 0x00, 0x00, 0x00},
+		},
+		{
+			name:    "[]bool not multiple of 32",
+			input:   []bool{true},
+			wantErr: true,
+		},
 		{
 			name: "Timer",
 			input: &Timer{
@@ -53,3 +68,64 @@ func TestMarshal(t *testing.T) {
 		})
 	}
 }
+
+func TestGoTypeToCIPType(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   any
+		want    DataType
+		wantErr bool
+	}{
+		{"int32", int32(0), TypeDINT, false},
+		{"float64", float64(0), TypeLREAL, false},
+		{"[]int32", []int32{1, 2}, TypeDINT, false},
+		{"[]float32", []float32{1}, TypeREAL, false},
+		{"[4]uint16", [4]uint16{}, TypeUINT, false},
+		{"[]bool", []bool{}, TypeDWORD, false},
+		{"[64]bool", [64]bool{}, TypeDWORD, false},
+		{"[][]int32", [][]int32{}, 0, true},
+		{"struct", struct{}{}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GoTypeToCIPType(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GoTypeToCIPType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GoTypeToCIPType() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestElementCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   any
+		want    uint16
+		wantErr bool
+	}{
+		{"scalar", int32(5), 1, false},
+		{"slice", []float32{1, 2, 3}, 3, false},
+		{"array", [5]int8{}, 5, false},
+		{"bool words", make([]bool, 64), 2, false},
+		{"bool partial", make([]bool, 10), 0, true},
+		{"bool array words", [96]bool{}, 3, false},
+		{"bool array partial", [40]bool{}, 0, true},
+		{"too many", make([]byte, 0x10000), 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ElementCount(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ElementCount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ElementCount() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,338 @@
+package cip
+
+import (
+	"fmt"
+	"strings"
+)
+
+// General Status Codes, as numbered by the CIP specification. Releases
+// before the status catalog had StatusPrivilegeViolation,
+// StatusDeviceStateConflict, StatusReplyDataTooLarge and
+// StatusServiceFragmentation one code off (0x10, 0x11, 0x12 and 0x2D);
+// code that compared against those values now matches the codes the
+// names describe.
+const (
+	StatusSuccess                   USINT = 0x00
+	StatusConnectionFailure         USINT = 0x01
+	StatusResourceUnavailable       USINT = 0x02
+	StatusInvalidParameterValue     USINT = 0x03
+	StatusPathSegmentError          USINT = 0x04
+	StatusPathDestinationUnknown    USINT = 0x05
+	StatusPartialTransfer           USINT = 0x06
+	StatusConnectionLost            USINT = 0x07
+	StatusServiceNotSupported       USINT = 0x08
+	StatusInvalidAttributeValue     USINT = 0x09
+	StatusAttributeListError        USINT = 0x0A
+	StatusAlreadyInRequestedState   USINT = 0x0B
+	StatusObjectStateConflict       USINT = 0x0C
+	StatusObjectAlreadyExists       USINT = 0x0D
+	StatusAttributeNotSettable      USINT = 0x0E
+	StatusPrivilegeViolation        USINT = 0x0F
+	StatusDeviceStateConflict       USINT = 0x10
+	StatusReplyDataTooLarge         USINT = 0x11
+	StatusFragmentationOfPrimitive  USINT = 0x12
+	StatusNotEnoughData             USINT = 0x13
+	StatusAttributeNotSupported     USINT = 0x14
+	StatusTooMuchData               USINT = 0x15
+	StatusObjectDoesNotExist        USINT = 0x16
+	StatusServiceFragmentation      USINT = 0x17 // Fragmentation sequence not in progress
+	StatusNoStoredAttributeData     USINT = 0x18
+	StatusStoreOperationFailure     USINT = 0x19
+	StatusRequestTooLarge           USINT = 0x1A // Routing failure
+	StatusResponseTooLarge          USINT = 0x1B // Routing failure
+	StatusAttributeListShortage     USINT = 0x1C
+	StatusInvalidAttributeValueList USINT = 0x1D
+	StatusEmbeddedServiceError      USINT = 0x1E
+	StatusVendorSpecific            USINT = 0x1F
+	StatusInvalidParameter          USINT = 0x20
+	StatusWriteOnceAlreadyWritten   USINT = 0x21
+	StatusInvalidReply              USINT = 0x22
+	StatusBufferOverflow            USINT = 0x23
+	StatusMessageFormatError        USINT = 0x24
+	StatusKeyFailureInPath          USINT = 0x25
+	StatusPathSizeInvalid           USINT = 0x26
+	StatusUnexpectedAttribute       USINT = 0x27
+	StatusInvalidMemberID           USINT = 0x28
+	StatusMemberNotSettable         USINT = 0x29
+	StatusGroup2ServerFailure       USINT = 0x2A
+	StatusUnknownModbusError        USINT = 0x2B
+	StatusAttributeNotGettable      USINT = 0x2C
+	StatusInstanceNotDeletable      USINT = 0x2D
+	StatusServiceNotSupportedOnPath USINT = 0x2E
+	StatusGeneralError              USINT = 0xFF // Logix: see the extended status
+
+	// Deprecated: use StatusInvalidParameterValue.
+	StatusInvalidSegmentType = StatusInvalidParameterValue
+)
+
+var statusText = map[USINT]string{
+	StatusSuccess:                   "success",
+	StatusConnectionFailure:         "connection failure",
+	StatusResourceUnavailable:       "resource unavailable",
+	StatusInvalidParameterValue:     "invalid parameter value",
+	StatusPathSegmentError:          "path segment error",
+	StatusPathDestinationUnknown:    "path destination unknown (tag not found)",
+	StatusPartialTransfer:           "partial transfer",
+	StatusConnectionLost:            "connection lost",
+	StatusServiceNotSupported:       "service not supported",
+	StatusInvalidAttributeValue:     "invalid attribute value",
+	StatusAttributeListError:        "attribute list error",
+	StatusAlreadyInRequestedState:   "already in requested mode/state",
+	StatusObjectStateConflict:       "object state conflict",
+	StatusObjectAlreadyExists:       "object already exists",
+	StatusAttributeNotSettable:      "attribute not settable",
+	StatusPrivilegeViolation:        "privilege violation",
+	StatusDeviceStateConflict:       "device state conflict",
+	StatusReplyDataTooLarge:         "reply data too large",
+	StatusFragmentationOfPrimitive:  "fragmentation of a primitive value",
+	StatusNotEnoughData:             "not enough data",
+	StatusAttributeNotSupported:     "attribute not supported",
+	StatusTooMuchData:               "too much data",
+	StatusObjectDoesNotExist:        "object does not exist",
+	StatusServiceFragmentation:      "service fragmentation sequence not in progress",
+	StatusNoStoredAttributeData:     "no stored attribute data",
+	StatusStoreOperationFailure:     "store operation failure",
+	StatusRequestTooLarge:           "routing failure, request packet too large",
+	StatusResponseTooLarge:          "routing failure, response packet too large",
+	StatusAttributeListShortage:     "missing attribute list entry data",
+	StatusInvalidAttributeValueList: "invalid attribute value list",
+	StatusEmbeddedServiceError:      "embedded service error",
+	StatusVendorSpecific:            "vendor specific error",
+	StatusInvalidParameter:          "invalid parameter",
+	StatusWriteOnceAlreadyWritten:   "write-once value or medium already written",
+	StatusInvalidReply:              "invalid reply received",
+	StatusBufferOverflow:            "buffer overflow",
+	StatusMessageFormatError:        "message format error",
+	StatusKeyFailureInPath:          "key failure in path",
+	StatusPathSizeInvalid:           "path size invalid",
+	StatusUnexpectedAttribute:       "unexpected attribute in list",
+	StatusInvalidMemberID:           "invalid member ID",
+	StatusMemberNotSettable:         "member not settable",
+	StatusGroup2ServerFailure:       "group 2 only server general failure",
+	StatusUnknownModbusError:        "unknown Modbus error",
+	StatusAttributeNotGettable:      "attribute not gettable",
+	StatusInstanceNotDeletable:      "instance not deletable",
+	StatusServiceNotSupportedOnPath: "service not supported for specified path",
+	StatusGeneralError:              "general error",
+}
+
+// connMgrExtStatusText describes the extended status of Connection Manager
+// replies with status 0x01.
+var connMgrExtStatusText = map[UINT]string{
+	0x0100: "connection in use or duplicate forward open",
+	0x0103: "transport class and trigger combination not supported",
+	0x0106: "ownership conflict",
+	0x0107: "target connection not found",
+	0x0108: "invalid network connection parameter",
+	0x0109: "invalid connection size",
+	0x0110: "target for connection not configured",
+	0x0111: "RPI not supported",
+	0x0112: "RPI value not acceptable",
+	0x0113: "out of connections",
+	0x0114: "vendor ID or product code mismatch",
+	0x0115: "device type mismatch",
+	0x0116: "revision mismatch",
+	0x0117: "invalid produced or consumed application path",
+	0x0118: "invalid or inconsistent configuration application path",
+	0x0119: "non-listen only connection not opened",
+	0x011A: "target object out of connections",
+	0x011B: "RPI is smaller than the production inhibit time",
+	0x011C: "transport class not supported",
+	0x011D: "production trigger not supported",
+	0x011E: "direction not supported",
+	0x011F: "invalid O->T fixed/variable flag",
+	0x0120: "invalid T->O fixed/variable flag",
+	0x0121: "invalid O->T priority",
+	0x0122: "invalid T->O priority",
+	0x0123: "invalid O->T connection type",
+	0x0124: "invalid T->O connection type",
+	0x0125: "invalid O->T redundant owner flag",
+	0x0126: "invalid configuration size",
+	0x0127: "invalid O->T size",
+	0x0128: "invalid T->O size",
+	0x0129: "invalid configuration application path",
+	0x012A: "invalid consuming application path",
+	0x012B: "invalid producing application path",
+	0x012C: "configuration symbol does not exist",
+	0x012D: "consuming symbol does not exist",
+	0x012E: "producing symbol does not exist",
+	0x012F: "inconsistent application path combination",
+	0x0130: "inconsistent consume data format",
+	0x0131: "inconsistent produce data format",
+	0x0132: "null forward open function not supported",
+	0x0133: "connection timeout multiplier not acceptable",
+	0x0203: "connection timed out",
+	0x0204: "unconnected request timed out",
+	0x0205: "parameter error in unconnected request",
+	0x0206: "message too large for unconnected send",
+	0x0207: "unconnected acknowledge without reply",
+	0x0301: "no buffer memory available",
+	0x0302: "network bandwidth not available for data",
+	0x0303: "no consumed connection ID filter available",
+	0x0304: "not configured to send scheduled priority data",
+	0x0305: "schedule signature mismatch",
+	0x0306: "schedule signature validation not possible",
+	0x0311: "port not available",
+	0x0312: "link address not valid",
+	0x0315: "invalid segment in connection path",
+	0x0316: "error in forward close service connection path",
+	0x0317: "scheduling not specified",
+	0x0318: "link address to self invalid",
+	0x0319: "secondary resources unavailable",
+	0x031A: "rack connection already established",
+	0x031B: "module connection already established",
+	0x031C: "miscellaneous",
+	0x031D: "redundant connection mismatch",
+	0x031E: "no more user configurable link consumer resources available",
+	0x031F: "no user configurable link consumer resources configured",
+	0x0800: "network link offline",
+	0x0810: "no target application data available",
+	0x0811: "no originator application data available",
+	0x0812: "node address has changed since the network was scheduled",
+	0x0813: "not configured for off-subnet multicast",
+	0x0814: "invalid produce/consume data format",
+}
+
+// logixExtStatusText describes the extended status Logix controllers
+// return with status 0xFF, and with status 0x10 for the keyswitch and
+// safety codes.
+var logixExtStatusText = map[UINT]string{
+	0x2001: "excessive IOI",
+	0x2002: "bad parameter value",
+	0x2018: "semaphore reject",
+	0x201B: "size too small",
+	0x201C: "invalid size",
+	0x2100: "privilege failure",
+	0x2101: "keyswitch position prevents the requested change",
+	0x2102: "password invalid",
+	0x2103: "no password issued",
+	0x2104: "offset out of range",
+	0x2105: "access beyond end of object",
+	0x2106: "data in use",
+	0x2107: "data type does not match the tag",
+	0x2108: "controller in upload or download mode",
+	0x2109: "attempt to change the number of array dimensions",
+	0x210A: "invalid symbol name",
+	0x210B: "symbol does not exist",
+	0x210E: "search failed",
+	0x210F: "task cannot start",
+	0x2110: "unable to write",
+	0x2111: "unable to read",
+	0x2112: "shared routine not editable",
+	0x2113: "controller in faulted mode",
+	0x2114: "run mode inhibited",
+	0x2802: "safety status prevents the requested change",
+}
+
+// StatusText returns a description of a general status code, or "" if the
+// code is unknown.
+func StatusText(status USINT) string {
+	return statusText[status]
+}
+
+// ExtStatusText returns a description of the extended status ext reported
+// with general status status, or "" if it is unknown. Extended status
+// codes are defined by the object that failed; Connection Manager codes
+// (status 0x01) and Logix codes (status 0xFF and 0x10) are known.
+func ExtStatusText(status USINT, ext UINT) string {
+	switch status {
+	case StatusConnectionFailure:
+		return connMgrExtStatusText[ext]
+	case StatusGeneralError, StatusDeviceStateConflict:
+		return logixExtStatusText[ext]
+	}
+	return ""
+}
+
+// Error represents a CIP error
+type Error struct {
+	Status    USINT
+	ExtStatus []UINT // Extended status is usually a list of words
+}
+
+func (e Error) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "CIP error 0x%02X", e.Status)
+	if text := StatusText(e.Status); text != "" {
+		sb.WriteString(": ")
+		sb.WriteString(text)
+	}
+	if len(e.ExtStatus) > 0 {
+		fmt.Fprintf(&sb, " (ext status 0x%04X", e.ExtStatus[0])
+		if text := ExtStatusText(e.Status, e.ExtStatus[0]); text != "" {
+			sb.WriteString(": ")
+			sb.WriteString(text)
+		}
+		sb.WriteString(")")
+	}
+	return sb.String()
+}
+
+// Is reports whether target is an Error with the same status. If target
+// has an extended status, e must begin with it as well, so
+// errors.Is(err, ErrPathDestinationUnknown) matches any tag-not-found
+// reply and Error{Status: 0x01, ExtStatus: []UINT{0x0100}} one reason
+// for a connection failure.
+func (e Error) Is(target error) bool {
+	var t Error
+	switch v := target.(type) {
+	case Error:
+		t = v
+	case *Error:
+		if v == nil {
+			return false
+		}
+		t = *v
+	default:
+		return false
+	}
+	if e.Status != t.Status || len(e.ExtStatus) < len(t.ExtStatus) {
+		return false
+	}
+	for i, ext := range t.ExtStatus {
+		if e.ExtStatus[i] != ext {
+			return false
+		}
+	}
+	return true
+}
+
+// Sentinel errors for use with errors.Is. A failed reply matches the
+// sentinel of its general status whatever its extended status.
+var (
+	ErrConnectionFailure      = Error{Status: StatusConnectionFailure}
+	ErrResourceUnavailable    = Error{Status: StatusResourceUnavailable}
+	ErrInvalidParameterValue  = Error{Status: StatusInvalidParameterValue}
+	ErrPathSegmentError       = Error{Status: StatusPathSegmentError}
+	ErrPathDestinationUnknown = Error{Status: StatusPathDestinationUnknown}
+	ErrPartialTransfer        = Error{Status: StatusPartialTransfer}
+	ErrConnectionLost         = Error{Status: StatusConnectionLost}
+	ErrServiceNotSupported    = Error{Status: StatusServiceNotSupported}
+	ErrInvalidAttributeValue  = Error{Status: StatusInvalidAttributeValue}
+	ErrObjectStateConflict    = Error{Status: StatusObjectStateConflict}
+	ErrAttributeNotSettable   = Error{Status: StatusAttributeNotSettable}
+	ErrPrivilegeViolation     = Error{Status: StatusPrivilegeViolation}
+	ErrDeviceStateConflict    = Error{Status: StatusDeviceStateConflict}
+	ErrReplyDataTooLarge      = Error{Status: StatusReplyDataTooLarge}
+	ErrNotEnoughData          = Error{Status: StatusNotEnoughData}
+	ErrAttributeNotSupported  = Error{Status: StatusAttributeNotSupported}
+	ErrTooMuchData            = Error{Status: StatusTooMuchData}
+	ErrObjectDoesNotExist     = Error{Status: StatusObjectDoesNotExist}
+	ErrRequestTooLarge        = Error{Status: StatusRequestTooLarge}
+	ErrResponseTooLarge       = Error{Status: StatusResponseTooLarge}
+	ErrEmbeddedServiceError   = Error{Status: StatusEmbeddedServiceError}
+	ErrInvalidParameter       = Error{Status: StatusInvalidParameter}
+	ErrPathSizeInvalid        = Error{Status: StatusPathSizeInvalid}
+	ErrGeneralError           = Error{Status: StatusGeneralError}
+
+	// Logix extended status codes of status 0xFF
+	ErrKeyswitchPosition  = Error{Status: StatusGeneralError, ExtStatus: []UINT{0x2101}}
+	ErrOffsetOutOfRange   = Error{Status: StatusGeneralError, ExtStatus: []UINT{0x2104}}
+	ErrBeyondEndOfObject  = Error{Status: StatusGeneralError, ExtStatus: []UINT{0x2105}}
+	ErrDataInUse          = Error{Status: StatusGeneralError, ExtStatus: []UINT{0x2106}}
+	ErrTypeMismatch       = Error{Status: StatusGeneralError, ExtStatus: []UINT{0x2107}}
+	ErrUploadDownloadMode = Error{Status: StatusGeneralError, ExtStatus: []UINT{0x2108}}
+	ErrInvalidSymbolName  = Error{Status: StatusGeneralError, ExtStatus: []UINT{0x210A}}
+	ErrSymbolDoesNotExist = Error{Status: StatusGeneralError, ExtStatus: []UINT{0x210B}}
+	ErrControllerFaulted  = Error{Status: StatusGeneralError, ExtStatus: []UINT{0x2113}}
+)
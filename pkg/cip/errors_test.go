@@ -0,0 +1,49 @@
+package cip
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestError_Error(t *testing.T) {
+	tests := []struct {
+		err  Error
+		want string
+	}{
+		{Error{Status: 0x05}, "CIP error 0x05: path destination unknown (tag not found)"},
+		{Error{Status: 0x01, ExtStatus: []UINT{0x0100}}, "CIP error 0x01: connection failure (ext status 0x0100: connection in use or duplicate forward open)"},
+		{Error{Status: 0xFF, ExtStatus: []UINT{0x2107}}, "CIP error 0xFF: general error (ext status 0x2107: data type does not match the tag)"},
+		{Error{Status: 0xFF, ExtStatus: []UINT{0x210B}}, "CIP error 0xFF: general error (ext status 0x210B: symbol does not exist)"},
+		{Error{Status: 0x10, ExtStatus: []UINT{0x2101}}, "CIP error 0x10: device state conflict (ext status 0x2101: keyswitch position prevents the requested change)"},
+		{Error{Status: 0x04, ExtStatus: []UINT{0x0001}}, "CIP error 0x04: path segment error (ext status 0x0001)"},
+		{Error{Status: 0x99}, "CIP error 0x99"},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("Error() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestError_Is(t *testing.T) {
+	resp := &MessageRouterResponse{GeneralStatus: StatusPathDestinationUnknown, ExtStatus: []UINT{0x0000}}
+	err := fmt.Errorf("read tag: %w", resp.Error())
+	if !errors.Is(err, ErrPathDestinationUnknown) {
+		t.Errorf("errors.Is(%v, ErrPathDestinationUnknown) = false", err)
+	}
+	if errors.Is(err, ErrPrivilegeViolation) {
+		t.Errorf("errors.Is(%v, ErrPrivilegeViolation) = true", err)
+	}
+
+	logix := Error{Status: StatusGeneralError, ExtStatus: []UINT{0x2107}}
+	if !errors.Is(logix, ErrTypeMismatch) || !errors.Is(logix, ErrGeneralError) {
+		t.Errorf("errors.Is(%v) did not match ErrTypeMismatch and ErrGeneralError", logix)
+	}
+	if errors.Is(logix, ErrOffsetOutOfRange) {
+		t.Errorf("errors.Is(%v, ErrOffsetOutOfRange) = true", logix)
+	}
+	if errors.Is(Error{Status: StatusGeneralError}, ErrTypeMismatch) {
+		t.Error("error without extended status matched ErrTypeMismatch")
+	}
+}
@@ -0,0 +1,97 @@
+package cip
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// MotionInstruction represents a Logix MOTION_INSTRUCTION structure, the
+// control tag of motion instructions (MSO, MAJ, MAM, ...).
+//
+// Memory Layout (16 bytes):
+// Offset 0-3: FLAGS Status Bits (DINT)
+// Offset 4-5: ERR (INT)
+// Offset 6: STATUS (SINT)
+// Offset 7: STATE (SINT)
+// Offset 8-11: SEGMENT (DINT)
+// Offset 12: EXERR (SINT)
+type MotionInstruction struct {
+	ERR     int16 // Error code
+	STATUS  int8  // Message status
+	STATE   int8  // Execution state
+	SEGMENT int32 // Segment of a multi-segment move
+	EXERR   int8  // Extended error code
+	EN      bool  // Enable
+	DN      bool  // Done
+	ER      bool  // Error
+	PC      bool  // Process complete
+	IP      bool  // In process
+	AC      bool  // Active
+	ACCEL   bool  // Accelerating
+	DECEL   bool  // Decelerating
+}
+
+// Bit positions of the MOTION_INSTRUCTION FLAGS status bits
+const (
+	MotionStatusEN    = 31
+	MotionStatusDN    = 29
+	MotionStatusER    = 28
+	MotionStatusPC    = 27
+	MotionStatusIP    = 26
+	MotionStatusAC    = 23
+	MotionStatusACCEL = 1
+	MotionStatusDECEL = 0
+)
+
+func (m *MotionInstruction) bits() []statusBit {
+	return []statusBit{
+		{MotionStatusEN, &m.EN},
+		{MotionStatusDN, &m.DN},
+		{MotionStatusER, &m.ER},
+		{MotionStatusPC, &m.PC},
+		{MotionStatusIP, &m.IP},
+		{MotionStatusAC, &m.AC},
+		{MotionStatusACCEL, &m.ACCEL},
+		{MotionStatusDECEL, &m.DECEL},
+	}
+}
+
+// DecodeMotionInstruction decodes a byte slice into a MotionInstruction
+// struct.
+func DecodeMotionInstruction(data []byte) (*MotionInstruction, error) {
+	if len(data) < 13 {
+		return nil, fmt.Errorf("insufficient data for MotionInstruction: expected at least 13 bytes, got %d", len(data))
+	}
+
+	m := &MotionInstruction{
+		ERR:     int16(binary.LittleEndian.Uint16(data[4:6])),
+		STATUS:  int8(data[6]),
+		STATE:   int8(data[7]),
+		SEGMENT: int32(binary.LittleEndian.Uint32(data[8:12])),
+		EXERR:   int8(data[12]),
+	}
+	unpackStatus(binary.LittleEndian.Uint32(data[0:4]), m.bits())
+	return m, nil
+}
+
+// UnmarshalCIP implements the Unmarshaler interface for MotionInstruction.
+func (m *MotionInstruction) UnmarshalCIP(data []byte) error {
+	decoded, err := DecodeMotionInstruction(data)
+	if err != nil {
+		return err
+	}
+	*m = *decoded
+	return nil
+}
+
+// MarshalCIP implements the Marshaler interface for MotionInstruction.
+func (m *MotionInstruction) MarshalCIP() ([]byte, error) {
+	data := make([]byte, 16)
+	binary.LittleEndian.PutUint32(data[0:4], packStatus(m.bits()))
+	binary.LittleEndian.PutUint16(data[4:6], uint16(m.ERR))
+	data[6] = byte(m.STATUS)
+	data[7] = byte(m.STATE)
+	binary.LittleEndian.PutUint32(data[8:12], uint32(m.SEGMENT))
+	data[12] = byte(m.EXERR)
+	return data, nil
+}
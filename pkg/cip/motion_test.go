@@ -0,0 +1,28 @@
+package cip
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestDecodeMotionInstruction(t *testing.T) {
+	data := make([]byte, 16)
+	binary.LittleEndian.PutUint32(data[0:4], 1<<MotionStatusEN|1<<MotionStatusIP|1<<MotionStatusACCEL)
+	binary.LittleEndian.PutUint16(data[4:6], 7)
+	data[7] = 2
+	binary.LittleEndian.PutUint32(data[8:12], 3)
+
+	m, err := DecodeMotionInstruction(data)
+	if err != nil {
+		t.Fatalf("DecodeMotionInstruction() error = %v", err)
+	}
+	want := MotionInstruction{ERR: 7, STATE: 2, SEGMENT: 3, EN: true, IP: true, ACCEL: true}
+	if *m != want {
+		t.Errorf("DecodeMotionInstruction() = %+v, want %+v", *m, want)
+	}
+
+	encoded, _ := m.MarshalCIP()
+	if got, _ := DecodeMotionInstruction(encoded); *got != want {
+		t.Errorf("round trip = %+v, want %+v", *got, want)
+	}
+}
@@ -0,0 +1,93 @@
+package cip
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// MultipleServiceOverhead is the size of a Multiple Service Packet request
+// before any embedded service: Service (1), Path Size (1), the Message Router
+// path (4) and the service count (2). Each embedded service adds a 2-byte
+// offset plus its encoded length.
+const MultipleServiceOverhead = 8
+
+// NewMultipleServiceRequest creates a Multiple Service Packet request (0x0A)
+// addressed to the Message Router that carries each of reqs.
+func NewMultipleServiceRequest(reqs []*MessageRouterRequest) (*MessageRouterRequest, error) {
+	if len(reqs) == 0 {
+		return nil, fmt.Errorf("cip: multiple service packet needs at least one request")
+	}
+
+	encoded := make([][]byte, len(reqs))
+	for i, r := range reqs {
+		b, err := r.Encode()
+		if err != nil {
+			return nil, err
+		}
+		encoded[i] = b
+	}
+
+	// Request Data:
+	// Number of Services (UINT)
+	// Offsets (UINT each, from the start of the request data)
+	// Services (...)
+	headerLen := 2 + 2*len(reqs)
+	size := headerLen
+	for _, b := range encoded {
+		size += len(b)
+	}
+	if size > 0xFFFF {
+		return nil, fmt.Errorf("cip: multiple service packet too large: %d bytes", size)
+	}
+
+	reqData := make([]byte, headerLen, size)
+	binary.LittleEndian.PutUint16(reqData[0:2], uint16(len(reqs)))
+	offset := headerLen
+	for i, b := range encoded {
+		binary.LittleEndian.PutUint16(reqData[2+2*i:], uint16(offset))
+		reqData = append(reqData, b...)
+		offset += len(b)
+	}
+
+	return &MessageRouterRequest{
+		Service:     ServiceMultipleServicePacket,
+		RequestPath: BuildPath(ClassMessageRouter, 1, 0),
+		RequestData: reqData,
+	}, nil
+}
+
+// DecodeMultipleServiceResponse splits the response data of a Multiple
+// Service Packet reply into the individual service replies, in request order.
+func DecodeMultipleServiceResponse(data []byte) ([]*MessageRouterResponse, error) {
+	if len(data) < 2 {
+		return nil, fmt.Errorf("cip: multiple service response too short")
+	}
+	count := int(binary.LittleEndian.Uint16(data[0:2]))
+	if len(data) < 2+2*count {
+		return nil, fmt.Errorf("cip: multiple service response truncated offset table")
+	}
+
+	offsets := make([]int, count)
+	for i := range offsets {
+		offsets[i] = int(binary.LittleEndian.Uint16(data[2+2*i:]))
+	}
+
+	replies := make([]*MessageRouterResponse, count)
+	for i, start := range offsets {
+		end := len(data)
+		if i+1 < count {
+			end = offsets[i+1]
+		}
+		if start < 2+2*count || start > end || end > len(data) {
+			return nil, fmt.Errorf("cip: invalid offset %d for service %d", start, i)
+		}
+
+		resp, err := DecodeMessageRouterResponse(data[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("cip: failed to decode service %d: %w", i, err)
+		}
+		replies[i] = resp
+	}
+
+	return replies, nil
+}
@@ -0,0 +1,95 @@
+package cip
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNewMultipleServiceRequest(t *testing.T) {
+	p1 := NewPath()
+	p1.AddSymbolicSegment("A")
+	p2 := NewPath()
+	p2.AddSymbolicSegment("BC")
+
+	req, err := NewMultipleServiceRequest([]*MessageRouterRequest{
+		NewReadTagRequest(p1, 1),
+		NewReadTagRequest(p2, 1),
+	})
+	if err != nil {
+		t.Fatalf("NewMultipleServiceRequest() error = %v", err)
+	}
+
+	if req.Service != ServiceMultipleServicePacket {
+		t.Errorf("Service = 0x%02X, want 0x0A", req.Service)
+	}
+	if !bytes.Equal(req.RequestPath, []byte{0x20, 0x02, 0x24, 0x01}) {
+		t.Errorf("RequestPath = %X, want 20022401", []byte(req.RequestPath))
+	}
+
+	want := []byte{
+		0x02, 0x00, // Count
+		0x06, 0x00, // Offset 1
+		0x0E, 0x00, // Offset 2
+		0x4C, 0x02, 0x91, 0x01, 'A', 0x00, 0x01, 0x00, // Read A
+		0x4C, 0x02, 0x91, 0x02, 'B', 'C', 0x01, 0x00, // Read BC
+	}
+	if !bytes.Equal(req.RequestData, want) {
+		t.Errorf("RequestData = %X, want %X", req.RequestData, want)
+	}
+
+	enc, _ := req.Encode()
+	if len(enc) != MultipleServiceOverhead+2*2+16 {
+		t.Errorf("encoded size = %d, want %d", len(enc), MultipleServiceOverhead+2*2+16)
+	}
+}
+
+func TestNewMultipleServiceRequest_Empty(t *testing.T) {
+	if _, err := NewMultipleServiceRequest(nil); err == nil {
+		t.Error("NewMultipleServiceRequest(nil) expected error")
+	}
+}
+
+func TestDecodeMultipleServiceResponse(t *testing.T) {
+	data := []byte{
+		0x02, 0x00, // Count
+		0x06, 0x00, // Offset 1
+		0x10, 0x00, // Offset 2
+		0xCC, 0x00, 0x00, 0x00, 0xC4, 0x00, 0x2A, 0x00, 0x00, 0x00, // DINT 42
+		0xCC, 0x00, 0x05, 0x01, 0x00, 0x00, // Path destination unknown, 1 ext status word
+	}
+
+	replies, err := DecodeMultipleServiceResponse(data)
+	if err != nil {
+		t.Fatalf("DecodeMultipleServiceResponse() error = %v", err)
+	}
+	if len(replies) != 2 {
+		t.Fatalf("len(replies) = %d, want 2", len(replies))
+	}
+
+	if !replies[0].IsSuccess() || !bytes.Equal(replies[0].ResponseData, []byte{0xC4, 0x00, 0x2A, 0x00, 0x00, 0x00}) {
+		t.Errorf("reply 0 = %+v", replies[0])
+	}
+	if replies[1].GeneralStatus != StatusPathDestinationUnknown || len(replies[1].ExtStatus) != 1 {
+		t.Errorf("reply 1 = %+v", replies[1])
+	}
+}
+
+func TestDecodeMultipleServiceResponse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"Empty", nil},
+		{"Truncated Offsets", []byte{0x02, 0x00, 0x06, 0x00}},
+		{"Offset Out Of Range", []byte{0x01, 0x00, 0x40, 0x00, 0xCC, 0x00, 0x00, 0x00}},
+		{"Offset Inside Table", []byte{0x01, 0x00, 0x02, 0x00, 0xCC, 0x00, 0x00, 0x00}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeMultipleServiceResponse(tt.data); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -121,6 +121,28 @@ func (p *Path) AddMember(memberID UINT) {
 	}
 }
 
+// AddElement adds an Element segment (array index) to the path.
+// Logix encodes array indices as member-type logical segments using the
+// smallest of the 8, 16 or 32-bit formats that fits the index.
+func (p *Path) AddElement(index uint32) {
+	if index <= 0xFF {
+		*p = append(*p, SegmentTypeLogical|LogicalTypeMember|LogicalFormat8Bit)
+		*p = append(*p, byte(index))
+	} else if index <= 0xFFFF {
+		*p = append(*p, SegmentTypeLogical|LogicalTypeMember|LogicalFormat16Bit)
+		*p = append(*p, 0x00) // Pad
+		b := make([]byte, 2)
+		binary.LittleEndian.PutUint16(b, uint16(index))
+		*p = append(*p, b...)
+	} else {
+		*p = append(*p, SegmentTypeLogical|LogicalTypeMember|LogicalFormat32Bit)
+		*p = append(*p, 0x00) // Pad
+		b := make([]byte, 4)
+		binary.LittleEndian.PutUint32(b, index)
+		*p = append(*p, b...)
+	}
+}
+
 // AddSymbolicSegment adds a Symbolic segment (ANSI Extended Symbol)
 func (p *Path) AddSymbolicSegment(symbol string) {
 	*p = append(*p, 0x91) // Extended Symbol Segment (Data Segment 0x80 | 0x11)
@@ -132,30 +154,34 @@ func (p *Path) AddSymbolicSegment(symbol string) {
 	}
 }
 
-// AddPortSegment adds a Port segment
+// AddPortSegment adds a Port segment.
+// Ports from 15 up are encoded as extended ports. A link address longer than
+// one byte, such as the IP address of a next hop written as text
+// ("192.168.2.10"), sets the extended link address flag.
 func (p *Path) AddPortSegment(port UINT, linkAddress []byte) {
-	// Simple port segment: 000xxxxx where xxxxx is port number if < 15
-	// If port >= 15, then 00001111 followed by extended port
-	// For now, assume port < 15 and link address is simple
+	b := SegmentTypePort
 	if port < 15 {
-		b := SegmentTypePort | byte(port)
-		if len(linkAddress) > 1 {
-			b |= 0x10 // Link Address Size bit (0 = 1 byte, 1 = >1 byte)
-			// Actually, if Link Address is > 1 byte, we need to add the length byte
-			// If Link Address is 1 byte, we just append it.
-			// Let's implement the simple case: Port < 15, Link Address 1 byte (e.g. Backplane slot)
-		}
-		*p = append(*p, b)
-		*p = append(*p, linkAddress...)
-		if len(linkAddress)%2 == 0 {
-			// Port segment must be even length?
-			// "The Port Segment shall be padded to a 16-bit boundary if necessary."
-			// 1 byte segment + 1 byte link address = 2 bytes (OK)
-			// 1 byte segment + 2 byte link address = 3 bytes -> Pad to 4
-		}
+		b |= byte(port)
 	} else {
-		// Extended port not implemented yet
-		panic("Extended port segments not implemented")
+		b |= 0x0F // Extended port follows
+	}
+	if len(linkAddress) > 1 {
+		b |= 0x10 // Link address size follows
+	}
+
+	start := len(*p)
+	*p = append(*p, b)
+	if len(linkAddress) > 1 {
+		*p = append(*p, byte(len(linkAddress)))
+	}
+	if port >= 15 {
+		*p = binary.LittleEndian.AppendUint16(*p, uint16(port))
+	}
+	*p = append(*p, linkAddress...)
+
+	// The Port Segment shall be padded to a 16-bit boundary
+	if (len(*p)-start)%2 != 0 {
+		*p = append(*p, 0x00)
 	}
 }
 
@@ -155,6 +155,40 @@ func TestPath_AddMember(t *testing.T) {
 	}
 }
 
+func TestPath_AddElement(t *testing.T) {
+	tests := []struct {
+		name  string
+		index uint32
+		want  []byte
+	}{
+		{
+			name:  "8-bit Element",
+			index: 0x05,
+			want:  []byte{0x28, 0x05},
+		},
+		{
+			name:  "16-bit Element",
+			index: 0x1234,
+			want:  []byte{0x29, 0x00, 0x34, 0x12},
+		},
+		{
+			name:  "32-bit Element",
+			index: 0x12345678,
+			want:  []byte{0x2A, 0x00, 0x78, 0x56, 0x34, 0x12},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPath()
+			p.AddElement(tt.index)
+			if !bytes.Equal(p.Bytes(), tt.want) {
+				t.Errorf("Path.AddElement() = %X, want %X", p.Bytes(), tt.want)
+			}
+		})
+	}
+}
+
 func TestPath_AddSymbolicSegment(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -185,30 +219,86 @@ func TestPath_AddSymbolicSegment(t *testing.T) {
 }
 
 func TestPath_AddPortSegment(t *testing.T) {
-	// tests := []struct {
-	// 	name        string
-	// 	port        UINT
-	// 	linkAddress []byte
-	// 	want        []byte
-	// }{
-	// 	{
-	// 		name:        "Port 1, 1 byte address",
-	// 		port:        1,
-	// 		linkAddress: []byte{0x00},
-	// 		want:        []byte{0x01, 0x00},
-	// 	},
-	// }
-
-	// Skipping the 2nd test case for now as I need to verify the implementation first
-	// But let's test the basic case which is used most often (Backplane)
-	t.Run("Backplane Port", func(t *testing.T) {
-		p := NewPath()
-		p.AddPortSegment(1, []byte{0x00}) // Port 1 (Backplane), Slot 0
-		want := []byte{0x01, 0x00}
-		if !bytes.Equal(p.Bytes(), want) {
-			t.Errorf("Path.AddPortSegment() = %X, want %X", p.Bytes(), want)
-		}
-	})
+	tests := []struct {
+		name        string
+		port        UINT
+		linkAddress []byte
+		want        []byte
+	}{
+		{
+			name:        "Backplane Port",
+			port:        1,
+			linkAddress: []byte{0x00},
+			want:        []byte{0x01, 0x00},
+		},
+		{
+			name:        "Extended Link Address",
+			port:        2,
+			linkAddress: []byte("192.168.2.10"),
+			want:        append([]byte{0x12, 0x0C}, "192.168.2.10"...),
+		},
+		{
+			name:        "Extended Link Address, Padded",
+			port:        2,
+			linkAddress: []byte("10.0.0.1"),
+			want:        append([]byte{0x12, 0x08}, "10.0.0.1"...),
+		},
+		{
+			name:        "Extended Port",
+			port:        0x21,
+			linkAddress: []byte{0x05},
+			want:        []byte{0x0F, 0x21, 0x00, 0x05},
+		},
+		{
+			name:        "Extended Port and Link Address",
+			port:        0x21,
+			linkAddress: []byte("1.2.3.4"),
+			want:        append(append([]byte{0x1F, 0x07, 0x21, 0x00}, "1.2.3.4"...), 0x00),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPath()
+			p.AddPortSegment(tt.port, tt.linkAddress)
+			if !bytes.Equal(p.Bytes(), tt.want) {
+				t.Errorf("Path.AddPortSegment() = %X, want %X", p.Bytes(), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRoutePath(t *testing.T) {
+	tests := []struct {
+		route   string
+		want    []byte
+		wantErr bool
+	}{
+		{route: "", want: []byte{}},
+		{route: "1,3", want: []byte{0x01, 0x03}},
+		{
+			route: "1,0,2,192.168.2.10,1,0",
+			want: append(append([]byte{0x01, 0x00, 0x12, 0x0C}, "192.168.2.10"...),
+				0x01, 0x00),
+		},
+		{route: " 1, 3 ", want: []byte{0x01, 0x03}},
+		{route: "1", wantErr: true},
+		{route: "0,1", wantErr: true},
+		{route: "1,x", wantErr: true},
+		{route: "1,256", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			p, err := ParseRoutePath(tt.route)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRoutePath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !bytes.Equal(p.Bytes(), tt.want) {
+				t.Errorf("ParseRoutePath() = %X, want %X", p.Bytes(), tt.want)
+			}
+		})
+	}
 }
 
 func TestBuildPath(t *testing.T) {
@@ -0,0 +1,171 @@
+package cip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// PID represents a Logix PID structure, used by the PID instruction.
+//
+// Memory Layout (184 bytes):
+// Offset 0-3: CTL Status Bits (DINT)
+// Offset 4-115: SP through MAXTIE (28 REALs, in field order)
+// Offset 116-183: DATA (REAL[17]), internal instruction data
+type PID struct {
+	SP     float32 // Setpoint
+	KP     float32 // Proportional gain
+	KI     float32 // Integral gain (1/min)
+	KD     float32 // Derivative time (min)
+	BIAS   float32 // Feedforward or bias (%)
+	MAXS   float32 // Maximum scaled value
+	MINS   float32 // Minimum scaled value
+	DB     float32 // Deadband
+	SO     float32 // Set output (%)
+	MAXO   float32 // Maximum output limit (%)
+	MINO   float32 // Minimum output limit (%)
+	UPD    float32 // Loop update time (s)
+	PV     float32 // Scaled process variable
+	ERR    float32 // Scaled error
+	OUT    float32 // Output (%)
+	PVH    float32 // Process variable high alarm limit
+	PVL    float32 // Process variable low alarm limit
+	DVP    float32 // Positive deviation alarm limit
+	DVN    float32 // Negative deviation alarm limit
+	PVDB   float32 // Process variable alarm deadband
+	DVDB   float32 // Deviation alarm deadband
+	MAXI   float32 // Maximum unscaled input
+	MINI   float32 // Minimum unscaled input
+	TIE    float32 // Tieback value for manual control
+	MAXCV  float32 // Maximum CV value
+	MINCV  float32 // Minimum CV value
+	MINTIE float32 // Minimum tieback value
+	MAXTIE float32 // Maximum tieback value
+	DATA   [17]float32
+
+	EN   bool // Enabled
+	CT   bool // Cascade type (slave when clear)
+	CL   bool // Cascade loop
+	PVT  bool // Process variable tracking
+	DOE  bool // Derivative of error (PV when clear)
+	SWM  bool // Software manual
+	CA   bool // Control action (E=PV-SP when set)
+	MO   bool // Station mode (manual when set)
+	PE   bool // Dependent gains (independent when clear)
+	NDF  bool // No derivative smoothing
+	NOBC bool // No bias back calculation
+	NOZC bool // No zero crossing in deadband
+	INI  bool // PID initialized
+	SPOR bool // Setpoint out of range
+	OLL  bool // Output below MINO
+	OLH  bool // Output above MAXO
+	EWD  bool // Error within deadband
+	DVNA bool // Deviation below DVN
+	DVPA bool // Deviation above DVP
+	PVLA bool // PV below PVL
+	PVHA bool // PV above PVH
+}
+
+// Bit positions of the PID CTL status bits
+const (
+	PIDStatusEN   = 31
+	PIDStatusCT   = 30
+	PIDStatusCL   = 29
+	PIDStatusPVT  = 28
+	PIDStatusDOE  = 27
+	PIDStatusSWM  = 26
+	PIDStatusCA   = 25
+	PIDStatusMO   = 24
+	PIDStatusPE   = 23
+	PIDStatusNDF  = 22
+	PIDStatusNOBC = 21
+	PIDStatusNOZC = 20
+	PIDStatusINI  = 15
+	PIDStatusSPOR = 14
+	PIDStatusOLL  = 13
+	PIDStatusOLH  = 12
+	PIDStatusEWD  = 11
+	PIDStatusDVNA = 10
+	PIDStatusDVPA = 9
+	PIDStatusPVLA = 8
+	PIDStatusPVHA = 7
+)
+
+// pidSize is the size of the PID structure in bytes.
+const pidSize = 184
+
+func (p *PID) bits() []statusBit {
+	return []statusBit{
+		{PIDStatusEN, &p.EN},
+		{PIDStatusCT, &p.CT},
+		{PIDStatusCL, &p.CL},
+		{PIDStatusPVT, &p.PVT},
+		{PIDStatusDOE, &p.DOE},
+		{PIDStatusSWM, &p.SWM},
+		{PIDStatusCA, &p.CA},
+		{PIDStatusMO, &p.MO},
+		{PIDStatusPE, &p.PE},
+		{PIDStatusNDF, &p.NDF},
+		{PIDStatusNOBC, &p.NOBC},
+		{PIDStatusNOZC, &p.NOZC},
+		{PIDStatusINI, &p.INI},
+		{PIDStatusSPOR, &p.SPOR},
+		{PIDStatusOLL, &p.OLL},
+		{PIDStatusOLH, &p.OLH},
+		{PIDStatusEWD, &p.EWD},
+		{PIDStatusDVNA, &p.DVNA},
+		{PIDStatusDVPA, &p.DVPA},
+		{PIDStatusPVLA, &p.PVLA},
+		{PIDStatusPVHA, &p.PVHA},
+	}
+}
+
+// values returns the REAL members in memory order.
+func (p *PID) values() []any {
+	return []any{
+		&p.SP, &p.KP, &p.KI, &p.KD, &p.BIAS, &p.MAXS, &p.MINS, &p.DB,
+		&p.SO, &p.MAXO, &p.MINO, &p.UPD, &p.PV, &p.ERR, &p.OUT, &p.PVH,
+		&p.PVL, &p.DVP, &p.DVN, &p.PVDB, &p.DVDB, &p.MAXI, &p.MINI, &p.TIE,
+		&p.MAXCV, &p.MINCV, &p.MINTIE, &p.MAXTIE, &p.DATA,
+	}
+}
+
+// DecodePID decodes a byte slice into a PID struct.
+func DecodePID(data []byte) (*PID, error) {
+	if len(data) < pidSize {
+		return nil, fmt.Errorf("insufficient data for PID: expected at least %d bytes, got %d", pidSize, len(data))
+	}
+
+	p := &PID{}
+	unpackStatus(binary.LittleEndian.Uint32(data[0:4]), p.bits())
+
+	r := bytes.NewReader(data[4:])
+	for _, v := range p.values() {
+		if err := binary.Read(r, binary.LittleEndian, v); err != nil {
+			return nil, fmt.Errorf("pid: %w", err)
+		}
+	}
+	return p, nil
+}
+
+// UnmarshalCIP implements the Unmarshaler interface for PID.
+func (p *PID) UnmarshalCIP(data []byte) error {
+	decoded, err := DecodePID(data)
+	if err != nil {
+		return err
+	}
+	*p = *decoded
+	return nil
+}
+
+// MarshalCIP implements the Marshaler interface for PID.
+func (p *PID) MarshalCIP() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, packStatus(p.bits()))
+	for _, v := range p.values() {
+		if err := binary.Write(buf, binary.LittleEndian, v); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
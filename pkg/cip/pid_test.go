@@ -0,0 +1,39 @@
+package cip
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestDecodePID(t *testing.T) {
+	data := make([]byte, pidSize)
+	binary.LittleEndian.PutUint32(data[0:4], 1<<PIDStatusEN|1<<PIDStatusMO|1<<PIDStatusPVHA)
+	binary.LittleEndian.PutUint32(data[4:8], math.Float32bits(50))     // SP
+	binary.LittleEndian.PutUint32(data[8:12], math.Float32bits(1.5))   // KP
+	binary.LittleEndian.PutUint32(data[60:64], math.Float32bits(42))   // OUT
+	binary.LittleEndian.PutUint32(data[112:116], math.Float32bits(90)) // MAXTIE
+
+	p, err := DecodePID(data)
+	if err != nil {
+		t.Fatalf("DecodePID() error = %v", err)
+	}
+	if p.SP != 50 || p.KP != 1.5 || p.OUT != 42 || p.MAXTIE != 90 {
+		t.Errorf("values = SP %v KP %v OUT %v MAXTIE %v", p.SP, p.KP, p.OUT, p.MAXTIE)
+	}
+	if !p.EN || !p.MO || !p.PVHA || p.CT || p.INI {
+		t.Errorf("status bits = %+v", p)
+	}
+
+	encoded, err := p.MarshalCIP()
+	if err != nil || len(encoded) != pidSize {
+		t.Fatalf("MarshalCIP() = %d bytes, %v", len(encoded), err)
+	}
+	if got, _ := DecodePID(encoded); *got != *p {
+		t.Errorf("round trip = %+v, want %+v", got, p)
+	}
+
+	if _, err := DecodePID(data[:100]); err == nil {
+		t.Error("expected error for short data")
+	}
+}
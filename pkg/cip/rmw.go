@@ -0,0 +1,51 @@
+package cip
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// ServiceReadModifyWriteTag changes individual bits of a tag inside the
+// controller, so bits the request does not touch keep whatever value the
+// program gives them meanwhile (Logix).
+const ServiceReadModifyWriteTag USINT = 0x4E
+
+// NewReadModifyWriteTagRequest creates a Read-Modify-Write Tag request.
+// The controller stores (value | orMask) & andMask; size is the size in
+// bytes of the tag's data type (1, 2, 4 or 8) and of each mask.
+func NewReadModifyWriteTagRequest(tagPath Path, size int, orMask, andMask uint64) (*MessageRouterRequest, error) {
+	if size != 1 && size != 2 && size != 4 && size != 8 {
+		return nil, fmt.Errorf("cip: invalid read-modify-write mask size %d", size)
+	}
+
+	// Mask Size (UINT) + OR Mask + AND Mask
+	reqData := make([]byte, 2+2*size)
+	binary.LittleEndian.PutUint16(reqData[0:2], uint16(size))
+	var mask [8]byte
+	binary.LittleEndian.PutUint64(mask[:], orMask)
+	copy(reqData[2:2+size], mask[:size])
+	binary.LittleEndian.PutUint64(mask[:], andMask)
+	copy(reqData[2+size:], mask[:size])
+
+	return &MessageRouterRequest{
+		Service:     ServiceReadModifyWriteTag,
+		RequestPath: tagPath,
+		RequestData: reqData,
+	}, nil
+}
+
+// NewWriteBitRequest creates a Read-Modify-Write Tag request that sets or
+// clears a single bit of a tag whose data type is size bytes long.
+func NewWriteBitRequest(tagPath Path, size int, bit int, value bool) (*MessageRouterRequest, error) {
+	if bit < 0 || bit >= size*8 {
+		return nil, fmt.Errorf("cip: bit %d out of range for %d-byte value", bit, size)
+	}
+
+	orMask, andMask := uint64(0), ^uint64(0)
+	if value {
+		orMask = 1 << bit
+	} else {
+		andMask &^= 1 << bit
+	}
+	return NewReadModifyWriteTagRequest(tagPath, size, orMask, andMask)
+}
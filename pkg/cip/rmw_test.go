@@ -0,0 +1,74 @@
+package cip
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNewWriteBitRequest(t *testing.T) {
+	path := NewPath()
+	path.AddSymbolicSegment("Tag")
+
+	tests := []struct {
+		name    string
+		size    int
+		bit     int
+		value   bool
+		want    []byte
+		wantErr bool
+	}{
+		{
+			name:  "set bit 5 of DINT",
+			size:  4,
+			bit:   5,
+			value: true,
+			want:  []byte{0x04, 0x00, 0x20, 0x00, 0x00, 0x00, 0xFF, 0xFF, 0xFF, 0xFF},
+		},
+		{
+			name:  "clear bit 9 of INT",
+			size:  2,
+			bit:   9,
+			value: false,
+			want:  []byte{0x02, 0x00, 0x00, 0x00, 0xFF, 0xFD},
+		},
+		{
+			name:  "set bit 63 of LINT",
+			size:  8,
+			bit:   63,
+			value: true,
+			want: []byte{0x08, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x80,
+				0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF},
+		},
+		{
+			name:    "bit out of range",
+			size:    1,
+			bit:     8,
+			wantErr: true,
+		},
+		{
+			name:    "invalid size",
+			size:    3,
+			bit:     0,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := NewWriteBitRequest(path, tt.size, tt.bit, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewWriteBitRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if req.Service != ServiceReadModifyWriteTag {
+				t.Errorf("Service = 0x%02X, want 0x4E", req.Service)
+			}
+			if !bytes.Equal(req.RequestData, tt.want) {
+				t.Errorf("RequestData = % X, want % X", req.RequestData, tt.want)
+			}
+		})
+	}
+}
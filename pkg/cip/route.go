@@ -0,0 +1,50 @@
+package cip
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// ParseRoutePath parses a route such as "1,3" or "1,0,2,192.168.2.10,1,0"
+// into port segments. The route is a list of port/link address pairs: the
+// port is a number, the link address a slot or node number, or an IP
+// address for EtherNet/IP ports, which is encoded as text in an extended
+// link address.
+func ParseRoutePath(s string) (Path, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return NewPath(), nil
+	}
+
+	parts := strings.Split(s, ",")
+	if len(parts)%2 != 0 {
+		return nil, fmt.Errorf("route path %q: expected port/link pairs", s)
+	}
+
+	p := NewPath()
+	for i := 0; i < len(parts); i += 2 {
+		portStr := strings.TrimSpace(parts[i])
+		linkStr := strings.TrimSpace(parts[i+1])
+
+		port, err := strconv.ParseUint(portStr, 10, 16)
+		if err != nil || port == 0 {
+			return nil, fmt.Errorf("route path %q: invalid port %q", s, portStr)
+		}
+
+		var link []byte
+		if ip := net.ParseIP(linkStr); ip != nil && ip.To4() != nil {
+			link = []byte(linkStr)
+		} else {
+			n, err := strconv.ParseUint(linkStr, 10, 8)
+			if err != nil {
+				return nil, fmt.Errorf("route path %q: invalid link address %q", s, linkStr)
+			}
+			link = []byte{byte(n)}
+		}
+
+		p.AddPortSegment(UINT(port), link)
+	}
+	return p, nil
+}
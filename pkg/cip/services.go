@@ -1,5 +1,10 @@
 package cip
 
+import (
+	"encoding/binary"
+	"fmt"
+)
+
 // NewGetAttributeSingleRequest creates a request to read a single attribute
 func NewGetAttributeSingleRequest(path Path) *MessageRouterRequest {
 	return &MessageRouterRequest{
@@ -69,3 +74,97 @@ func NewWriteTagRequest(tagPath Path, dataType DataType, elements uint16, data [
 		RequestData: reqData,
 	}
 }
+
+// NewWriteStructTagRequest creates a request to write a structure tag, such
+// as a STRING. Logix expects the structure handle after the 0x02A0 type code.
+func NewWriteStructTagRequest(tagPath Path, handle uint16, elements uint16, data []byte) *MessageRouterRequest {
+	reqData := make([]byte, 6+len(data))
+	binary.LittleEndian.PutUint16(reqData[0:2], uint16(TypeSTRUCT))
+	binary.LittleEndian.PutUint16(reqData[2:4], handle)
+	binary.LittleEndian.PutUint16(reqData[4:6], elements)
+	copy(reqData[6:], data)
+
+	return &MessageRouterRequest{
+		Service:     ServiceWriteTag,
+		RequestPath: tagPath,
+		RequestData: reqData,
+	}
+}
+
+// Logix fragmented tag services. These carry a byte offset so that values
+// larger than a single packet can be transferred in several requests.
+const ServiceReadTagFragmented USINT = 0x52
+const ServiceWriteTagFragmented USINT = 0x53
+
+// NewReadTagFragmentedRequest creates a Read Tag Fragmented request.
+// offset is the byte offset into the tag data at which the reply should start.
+func NewReadTagFragmentedRequest(tagPath Path, elements uint16, offset uint32) *MessageRouterRequest {
+	// Read Tag Fragmented Request Data:
+	// Number of Elements (UINT)
+	// Byte Offset (UDINT)
+	reqData := make([]byte, 6)
+	binary.LittleEndian.PutUint16(reqData[0:2], elements)
+	binary.LittleEndian.PutUint32(reqData[2:6], offset)
+
+	return &MessageRouterRequest{
+		Service:     ServiceReadTagFragmented,
+		RequestPath: tagPath,
+		RequestData: reqData,
+	}
+}
+
+// NewWriteTagFragmentedRequest creates a Write Tag Fragmented request carrying
+// the slice of the tag data that starts at the given byte offset.
+func NewWriteTagFragmentedRequest(tagPath Path, dataType DataType, elements uint16, offset uint32, data []byte) *MessageRouterRequest {
+	// Write Tag Fragmented Request Data:
+	// Data Type (UINT)
+	// Number of Elements (UINT)
+	// Byte Offset (UDINT)
+	// Data (...)
+	reqData := make([]byte, 8+len(data))
+	binary.LittleEndian.PutUint16(reqData[0:2], uint16(dataType))
+	binary.LittleEndian.PutUint16(reqData[2:4], elements)
+	binary.LittleEndian.PutUint32(reqData[4:8], offset)
+	copy(reqData[8:], data)
+
+	return &MessageRouterRequest{
+		Service:     ServiceWriteTagFragmented,
+		RequestPath: tagPath,
+		RequestData: reqData,
+	}
+}
+
+// NewWriteStructTagFragmentedRequest is the structure counterpart of
+// NewWriteTagFragmentedRequest, carrying the structure handle.
+func NewWriteStructTagFragmentedRequest(tagPath Path, handle uint16, elements uint16, offset uint32, data []byte) *MessageRouterRequest {
+	reqData := make([]byte, 10+len(data))
+	binary.LittleEndian.PutUint16(reqData[0:2], uint16(TypeSTRUCT))
+	binary.LittleEndian.PutUint16(reqData[2:4], handle)
+	binary.LittleEndian.PutUint16(reqData[4:6], elements)
+	binary.LittleEndian.PutUint32(reqData[6:10], offset)
+	copy(reqData[10:], data)
+
+	return &MessageRouterRequest{
+		Service:     ServiceWriteTagFragmented,
+		RequestPath: tagPath,
+		RequestData: reqData,
+	}
+}
+
+// SplitReadTagResponse separates the type information at the start of a
+// Read Tag (or Read Tag Fragmented) reply from the value bytes.
+// Atomic types carry a 2-byte type code; structures carry the 0x02A0 marker
+// followed by a 2-byte structure handle.
+func SplitReadTagResponse(data []byte) (typeInfo []byte, value []byte, err error) {
+	if len(data) < 2 {
+		return nil, nil, fmt.Errorf("cip: read tag response too short to contain type code")
+	}
+	n := 2
+	if DataType(binary.LittleEndian.Uint16(data[0:2])) == TypeSTRUCT {
+		n = 4
+		if len(data) < n {
+			return nil, nil, fmt.Errorf("cip: read tag response too short to contain structure handle")
+		}
+	}
+	return data[:n], data[n:], nil
+}
@@ -0,0 +1,90 @@
+package cip
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNewReadTagFragmentedRequest(t *testing.T) {
+	p := NewPath()
+	p.AddSymbolicSegment("Tag")
+
+	req := NewReadTagFragmentedRequest(p, 10, 0x01020304)
+	if req.Service != ServiceReadTagFragmented {
+		t.Errorf("Service = 0x%02X, want 0x52", req.Service)
+	}
+	want := []byte{0x0A, 0x00, 0x04, 0x03, 0x02, 0x01}
+	if !bytes.Equal(req.RequestData, want) {
+		t.Errorf("RequestData = %X, want %X", req.RequestData, want)
+	}
+}
+
+func TestNewWriteTagFragmentedRequest(t *testing.T) {
+	p := NewPath()
+	p.AddSymbolicSegment("Tag")
+
+	req := NewWriteTagFragmentedRequest(p, TypeDINT, 2, 4, []byte{0xAA, 0xBB, 0xCC, 0xDD})
+	if req.Service != ServiceWriteTagFragmented {
+		t.Errorf("Service = 0x%02X, want 0x53", req.Service)
+	}
+	want := []byte{
+		0xC4, 0x00, // DINT
+		0x02, 0x00, // Elements
+		0x04, 0x00, 0x00, 0x00, // Offset
+		0xAA, 0xBB, 0xCC, 0xDD,
+	}
+	if !bytes.Equal(req.RequestData, want) {
+		t.Errorf("RequestData = %X, want %X", req.RequestData, want)
+	}
+}
+
+func TestSplitReadTagResponse(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      []byte
+		wantType  []byte
+		wantValue []byte
+		wantErr   bool
+	}{
+		{
+			name:      "Atomic",
+			data:      []byte{0xC4, 0x00, 0x01, 0x02, 0x03, 0x04},
+			wantType:  []byte{0xC4, 0x00},
+			wantValue: []byte{0x01, 0x02, 0x03, 0x04},
+		},
+		{
+			name:      "Structure",
+			data:      []byte{0xA0, 0x02, 0x34, 0x12, 0x01},
+			wantType:  []byte{0xA0, 0x02, 0x34, 0x12},
+			wantValue: []byte{0x01},
+		},
+		{
+			name:    "Too Short",
+			data:    []byte{0xC4},
+			wantErr: true,
+		},
+		{
+			name:    "Structure Missing Handle",
+			data:    []byte{0xA0, 0x02, 0x34},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typeInfo, value, err := SplitReadTagResponse(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SplitReadTagResponse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !bytes.Equal(typeInfo, tt.wantType) {
+				t.Errorf("typeInfo = %X, want %X", typeInfo, tt.wantType)
+			}
+			if !bytes.Equal(value, tt.wantValue) {
+				t.Errorf("value = %X, want %X", value, tt.wantValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package cip
+
+import (
+	"encoding/binary"
+	"fmt"
+	"unicode/utf16"
+)
+
+// StringHandle is the structure handle of the predefined Logix STRING type.
+const StringHandle uint16 = 0x0FCE
+
+// StringType describes a Logix string structure: a DINT LEN member followed
+// by a SINT DATA array of Capacity characters. User-defined string types
+// (e.g. STRING20) share the layout with a different capacity and handle.
+type StringType struct {
+	Handle   uint16 // Structure handle, used when writing the tag
+	Capacity int    // Length of the DATA array
+}
+
+// LogixString is the predefined 82-character Logix STRING.
+var LogixString = StringType{Handle: StringHandle, Capacity: defaultStringLength}
+
+// Size returns the size of the structure in bytes, including padding.
+func (st StringType) Size() int {
+	return alignUp(4+st.Capacity, 4)
+}
+
+// Encode returns the structure bytes holding s.
+func (st StringType) Encode(s string) ([]byte, error) {
+	if len(s) > st.Capacity {
+		return nil, fmt.Errorf("cip: string of %d characters exceeds capacity %d", len(s), st.Capacity)
+	}
+	data := make([]byte, st.Size())
+	binary.LittleEndian.PutUint32(data, uint32(len(s)))
+	copy(data[4:], s)
+	return data, nil
+}
+
+// Decode returns the string held by the structure bytes in data.
+func (st StringType) Decode(data []byte) (string, error) {
+	if len(data) < 4 {
+		return "", fmt.Errorf("cip: string structure needs at least 4 bytes, got %d", len(data))
+	}
+	n := int(int32(binary.LittleEndian.Uint32(data)))
+	if n < 0 || n > st.Capacity || 4+n > len(data) {
+		return "", fmt.Errorf("cip: string length %d exceeds capacity %d", n, st.Capacity)
+	}
+	return string(data[4 : 4+n]), nil
+}
+
+// StringTypeFromTemplate reports whether t describes a Logix string
+// structure and returns its string type.
+func StringTypeFromTemplate(t *Template) (StringType, bool) {
+	var length, chars *TemplateMember
+	for i := range t.Members {
+		m := &t.Members[i]
+		switch {
+		case m.Hidden():
+			continue
+		case m.Name == "LEN" && !m.IsStructure() && !m.IsArray() && m.Type() == TypeDINT:
+			length = m
+		case m.Name == "DATA" && !m.IsStructure() && m.IsArray() && m.Type() == TypeSINT:
+			chars = m
+		default:
+			return StringType{}, false
+		}
+	}
+	if length == nil || chars == nil || length.Offset != 0 || chars.Offset != 4 {
+		return StringType{}, false
+	}
+	return StringType{Handle: t.Handle, Capacity: int(chars.Info)}, true
+}
+
+// EncodeString encodes s as one of the elementary CIP string types:
+// STRING (UINT length), SHORT_STRING (USINT length) or STRING2 (UINT length,
+// 16-bit characters).
+func EncodeString(t DataType, s string) ([]byte, error) {
+	switch t {
+	case TypeSTRING:
+		if len(s) > 0xFFFF {
+			return nil, fmt.Errorf("cip: string too long for %s: %d", t, len(s))
+		}
+		data := make([]byte, 2+len(s))
+		binary.LittleEndian.PutUint16(data, uint16(len(s)))
+		copy(data[2:], s)
+		return data, nil
+
+	case TypeSHORT_STRING:
+		if len(s) > 0xFF {
+			return nil, fmt.Errorf("cip: string too long for %s: %d", t, len(s))
+		}
+		return append([]byte{byte(len(s))}, s...), nil
+
+	case TypeSTRING2:
+		chars := utf16.Encode([]rune(s))
+		if len(chars) > 0xFFFF {
+			return nil, fmt.Errorf("cip: string too long for %s: %d", t, len(chars))
+		}
+		data := make([]byte, 2+2*len(chars))
+		binary.LittleEndian.PutUint16(data, uint16(len(chars)))
+		for i, c := range chars {
+			binary.LittleEndian.PutUint16(data[2+2*i:], c)
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("cip: %s is not a string type", t)
+}
+
+// DecodeString decodes a string value of type t. Besides the elementary
+// string types, TypeSTRUCT decodes a Logix string structure whose DATA array
+// fills the rest of data.
+func DecodeString(t DataType, data []byte) (string, error) {
+	switch t {
+	case TypeSTRUCT:
+		return StringType{Capacity: len(data) - 4}.Decode(data)
+
+	case TypeSTRING:
+		if len(data) < 2 {
+			return "", fmt.Errorf("cip: %s too short", t)
+		}
+		n := int(binary.LittleEndian.Uint16(data))
+		if 2+n > len(data) {
+			return "", fmt.Errorf("cip: %s length %d exceeds data", t, n)
+		}
+		return string(data[2 : 2+n]), nil
+
+	case TypeSHORT_STRING:
+		if len(data) < 1 {
+			return "", fmt.Errorf("cip: %s too short", t)
+		}
+		n := int(data[0])
+		if 1+n > len(data) {
+			return "", fmt.Errorf("cip: %s length %d exceeds data", t, n)
+		}
+		return string(data[1 : 1+n]), nil
+
+	case TypeSTRING2:
+		if len(data) < 2 {
+			return "", fmt.Errorf("cip: %s too short", t)
+		}
+		n := int(binary.LittleEndian.Uint16(data))
+		if 2+2*n > len(data) {
+			return "", fmt.Errorf("cip: %s length %d exceeds data", t, n)
+		}
+		chars := make([]uint16, n)
+		for i := range chars {
+			chars[i] = binary.LittleEndian.Uint16(data[2+2*i:])
+		}
+		return string(utf16.Decode(chars)), nil
+	}
+	return "", fmt.Errorf("cip: %s is not a string type", t)
+}
+
+// IsStringType reports whether t is one of the elementary CIP string types.
+func IsStringType(t DataType) bool {
+	return t == TypeSTRING || t == TypeSHORT_STRING || t == TypeSTRING2
+}
@@ -0,0 +1,136 @@
+package cip
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncodeDecodeString(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  DataType
+		s    string
+		want []byte
+	}{
+		{"STRING", TypeSTRING, "hi", []byte{0x02, 0x00, 'h', 'i'}},
+		{"SHORT_STRING", TypeSHORT_STRING, "abc", []byte{0x03, 'a', 'b', 'c'}},
+		{"STRING2", TypeSTRING2, "hé", []byte{0x02, 0x00, 'h', 0x00, 0xE9, 0x00}},
+		{"empty", TypeSHORT_STRING, "", []byte{0x00}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EncodeString(tt.typ, tt.s)
+			if err != nil {
+				t.Fatalf("EncodeString() error = %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("EncodeString() = % X, want % X", got, tt.want)
+			}
+
+			s, err := DecodeString(tt.typ, got)
+			if err != nil {
+				t.Fatalf("DecodeString() error = %v", err)
+			}
+			if s != tt.s {
+				t.Errorf("DecodeString() = %q, want %q", s, tt.s)
+			}
+		})
+	}
+}
+
+func TestDecodeString_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  DataType
+		data []byte
+	}{
+		{"not a string", TypeDINT, []byte{0, 0, 0, 0}},
+		{"STRING length past data", TypeSTRING, []byte{0x05, 0x00, 'a'}},
+		{"SHORT_STRING empty", TypeSHORT_STRING, nil},
+		{"struct length past data", TypeSTRUCT, []byte{0x09, 0x00, 0x00, 0x00, 'a', 'b', 'c', 'd'}},
+		{"struct negative length", TypeSTRUCT, []byte{0xFF, 0xFF, 0xFF, 0xFF}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeString(tt.typ, tt.data); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestStringType(t *testing.T) {
+	if LogixString.Size() != 88 {
+		t.Errorf("LogixString.Size() = %d, want 88", LogixString.Size())
+	}
+
+	st := StringType{Handle: 0x1234, Capacity: 20}
+	data, err := st.Encode("hello")
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	want := append([]byte{0x05, 0x00, 0x00, 0x00, 'h', 'e', 'l', 'l', 'o'}, make([]byte, 15)...)
+	if !bytes.Equal(data, want) {
+		t.Errorf("Encode() = % X, want % X", data, want)
+	}
+
+	s, err := st.Decode(data)
+	if err != nil || s != "hello" {
+		t.Errorf("Decode() = %q, %v, want \"hello\"", s, err)
+	}
+
+	if _, err := st.Encode(string(make([]byte, 21))); err == nil {
+		t.Error("expected error for string over capacity")
+	}
+}
+
+func TestStringTypeFromTemplate(t *testing.T) {
+	str20 := &Template{
+		Name:   "STRING20",
+		Handle: 0x5A5A,
+		Members: []TemplateMember{
+			{Name: "LEN", TypeCode: uint16(TypeDINT), Offset: 0},
+			{Name: "DATA", Info: 20, TypeCode: 0x2000 | uint16(TypeSINT), Offset: 4},
+		},
+	}
+	st, ok := StringTypeFromTemplate(str20)
+	if !ok {
+		t.Fatal("STRING20 not recognized")
+	}
+	if st.Handle != 0x5A5A || st.Capacity != 20 {
+		t.Errorf("StringTypeFromTemplate() = %+v", st)
+	}
+
+	point := &Template{Name: "Point", Members: testPointMembers}
+	if _, ok := StringTypeFromTemplate(point); ok {
+		t.Error("Point recognized as a string")
+	}
+}
+
+func TestTemplate_DecodeStringMember(t *testing.T) {
+	str4 := &Template{
+		InstanceID:    0x0200,
+		Name:          "STRING4",
+		StructureSize: 8,
+		Members: []TemplateMember{
+			{Name: "LEN", TypeCode: uint16(TypeDINT), Offset: 0},
+			{Name: "DATA", Info: 4, TypeCode: 0x2000 | uint16(TypeSINT), Offset: 4},
+		},
+	}
+	outer := &Template{
+		Name:          "Msg",
+		StructureSize: 8,
+		Members:       []TemplateMember{{Name: "Text", TypeCode: 0x8200, Offset: 0}},
+	}
+	lookup := func(id uint16) (*Template, error) { return str4, nil }
+
+	got, err := outer.Decode([]byte{0x02, 0x00, 0x00, 0x00, 'o', 'k', 0x00, 0x00}, lookup)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if got["Text"] != "ok" {
+		t.Errorf("Text = %#v, want \"ok\"", got["Text"])
+	}
+}
@@ -3,25 +3,166 @@ package cip
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
 	"io"
+	"strings"
 )
 
 // Symbol Object Class ID
 const ClassSymbol UINT = 0x6B
 
+// Symbol Object attributes
+const (
+	SymbolAttrName           UINT = 1
+	SymbolAttrType           UINT = 2
+	SymbolAttrDimensions     UINT = 8
+	SymbolAttrExternalAccess UINT = 10
+)
+
+// ExternalAccess is the External Access setting of a tag.
+type ExternalAccess uint8
+
+const (
+	ExternalAccessReadWrite ExternalAccess = 0
+	ExternalAccessReadOnly  ExternalAccess = 2
+	ExternalAccessNone      ExternalAccess = 3
+)
+
+func (a ExternalAccess) String() string {
+	switch a {
+	case ExternalAccessReadWrite:
+		return "Read/Write"
+	case ExternalAccessReadOnly:
+		return "Read Only"
+	case ExternalAccessNone:
+		return "None"
+	}
+	return fmt.Sprintf("ExternalAccess(%d)", uint8(a))
+}
+
 // Symbol Instance Structure (partial, for listing)
 type SymbolInstance struct {
 	InstanceID uint32
 	Name       string
 	Type       DataType
+
+	// Program is the program of a program-scoped tag, empty for
+	// controller-scoped tags.
+	Program string
+	// Dims are the array dimensions, zero for unused dimensions.
+	// Only set by Get Instance Attribute List.
+	Dims           [3]uint32
+	ExternalAccess ExternalAccess
+}
+
+// programPrefix starts the names of the symbols of programs.
+const programPrefix = "Program:"
+
+// FullName returns the name of the tag as used in tag expressions:
+// "Program:Main.Motor" for program-scoped tags, the name otherwise.
+func (s SymbolInstance) FullName() string {
+	if s.Program != "" {
+		return programPrefix + s.Program + "." + s.Name
+	}
+	return s.Name
+}
+
+// IsProgram reports whether the symbol is a program ("Program:Main"),
+// whose own tags are listed with a program-scoped request.
+func (s SymbolInstance) IsProgram() bool {
+	return strings.HasPrefix(s.Name, programPrefix)
 }
 
-// GetInstanceAttributeListRequest creates a request to list instances of a class
-// This uses the "Get Instance Attribute List" service (0x55) if supported,
-// or we might have to iterate using "Find Next Object Instance" (0x11).
-// Logix controllers typically support iterating via GetInstanceAttributeList on the Symbol Class.
+// ProgramName returns the name of the program of a program symbol.
+func (s SymbolInstance) ProgramName() string {
+	return strings.TrimPrefix(s.Name, programPrefix)
+}
+
+// IsSystem reports whether the symbol is a controller-internal symbol
+// rather than a user tag: symbols flagged as system in their type,
+// names starting with "__", and module, task, map and other symbols with a
+// "Prefix:" name. Program symbols count as system symbols.
+func (s SymbolInstance) IsSystem() bool {
+	return uint16(s.Type)&symbolTypeSystem != 0 ||
+		strings.HasPrefix(s.Name, "__") ||
+		strings.Contains(s.Name, ":")
+}
+
+// ServiceGetInstanceAttributeList is the Logix Get Instance Attribute List
+// service. It returns the requested attributes of the instances of a
+// class from a starting instance on, as many as fit in the reply; status
+// 0x06 (partial transfer) means more instances follow.
 const ServiceGetInstanceAttributeList USINT = 0x55
 
+// symbolListAttributes are the attributes requested by
+// NewGetInstanceAttributeListRequest, in reply order.
+var symbolListAttributes = []UINT{
+	SymbolAttrName,
+	SymbolAttrType,
+	SymbolAttrDimensions,
+	SymbolAttrExternalAccess,
+}
+
+// NewGetInstanceAttributeListRequest creates a Get Instance Attribute List
+// request for the symbols from instance start on. A non-empty program
+// lists the tags of that program instead of the controller-scoped tags.
+func NewGetInstanceAttributeListRequest(program string, start uint32) *MessageRouterRequest {
+	p := NewPath()
+	if program != "" {
+		p.AddSymbolicSegment(programPrefix + program)
+	}
+	p.AddClass(ClassSymbol)
+	p.AddInstance32(start)
+
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, uint16(len(symbolListAttributes)))
+	for _, attr := range symbolListAttributes {
+		binary.Write(buf, binary.LittleEndian, attr)
+	}
+
+	return &MessageRouterRequest{
+		Service:     ServiceGetInstanceAttributeList,
+		RequestPath: p,
+		RequestData: buf.Bytes(),
+	}
+}
+
+// DecodeGetInstanceAttributeListResponse decodes the symbols in the reply
+// to NewGetInstanceAttributeListRequest. Each entry is the instance ID
+// followed by the values of the requested attributes.
+func DecodeGetInstanceAttributeListResponse(program string, data []byte) ([]SymbolInstance, error) {
+	r := bytes.NewReader(data)
+	var symbols []SymbolInstance
+
+	for r.Len() > 0 {
+		s := SymbolInstance{Program: program}
+		var nameLen, typeCode uint16
+
+		if err := binary.Read(r, binary.LittleEndian, &s.InstanceID); err != nil {
+			return nil, fmt.Errorf("instance attribute list: %w", err)
+		}
+		if err := binary.Read(r, binary.LittleEndian, &nameLen); err != nil {
+			return nil, fmt.Errorf("instance attribute list: instance %d: %w", s.InstanceID, err)
+		}
+		name := make([]byte, nameLen)
+		if _, err := io.ReadFull(r, name); err != nil {
+			return nil, fmt.Errorf("instance attribute list: instance %d: name: %w", s.InstanceID, err)
+		}
+		s.Name = string(name)
+
+		fields := []any{&typeCode, &s.Dims, &s.ExternalAccess}
+		for _, f := range fields {
+			if err := binary.Read(r, binary.LittleEndian, f); err != nil {
+				return nil, fmt.Errorf("instance attribute list: %s: %w", s.Name, err)
+			}
+		}
+		s.Type = DataType(typeCode)
+
+		symbols = append(symbols, s)
+	}
+	return symbols, nil
+}
+
 // NewGetSymbolClassAttributesRequest creates a request to get attributes of the Symbol Class (Instance 0)
 func NewGetSymbolClassAttributesRequest() *MessageRouterRequest {
 	p := NewPath()
@@ -137,3 +278,30 @@ func DecodeSymbolAttributesResponse(data []byte) (string, DataType, error) {
 	}
 	return name, DataType(typeCode), nil
 }
+
+// ClassController is the Logix controller object. Its attributes include
+// counters that change whenever tags or data types are created, deleted or
+// downloaded.
+const ClassController UINT = 0xAC
+
+// changeCounterAttributes are the controller attributes read by
+// NewGetChangeCountersRequest.
+var changeCounterAttributes = []UINT{1, 2, 3, 4, 10}
+
+// NewGetChangeCountersRequest creates a Get Attribute List request for the
+// change counters of a Logix controller. The reply data is only meaningful
+// compared with an earlier reply: if it differs, symbol instance IDs and
+// template IDs read before may no longer be valid.
+func NewGetChangeCountersRequest() *MessageRouterRequest {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, uint16(len(changeCounterAttributes)))
+	for _, attr := range changeCounterAttributes {
+		binary.Write(buf, binary.LittleEndian, attr)
+	}
+
+	return &MessageRouterRequest{
+		Service:     ServiceGetAttributeList,
+		RequestPath: BuildPath(ClassController, 1, 0),
+		RequestData: buf.Bytes(),
+	}
+}
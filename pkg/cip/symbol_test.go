@@ -0,0 +1,89 @@
+package cip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// encodeSymbolEntry encodes one entry of a Get Instance Attribute List reply.
+func encodeSymbolEntry(id uint32, name string, typeCode uint16, dims [3]uint32, access ExternalAccess) []byte {
+	b := binary.LittleEndian.AppendUint32(nil, id)
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(name)))
+	b = append(b, name...)
+	b = binary.LittleEndian.AppendUint16(b, typeCode)
+	for _, d := range dims {
+		b = binary.LittleEndian.AppendUint32(b, d)
+	}
+	return append(b, byte(access))
+}
+
+func TestNewGetInstanceAttributeListRequest(t *testing.T) {
+	req := NewGetInstanceAttributeListRequest("", 0x1234)
+	if req.Service != ServiceGetInstanceAttributeList {
+		t.Errorf("Service = 0x%02X, want 0x55", req.Service)
+	}
+	wantPath := []byte{0x20, 0x6B, 0x25, 0x00, 0x34, 0x12}
+	if !bytes.Equal(req.RequestPath, wantPath) {
+		t.Errorf("RequestPath = % X, want % X", []byte(req.RequestPath), wantPath)
+	}
+	wantData := []byte{0x04, 0x00, 0x01, 0x00, 0x02, 0x00, 0x08, 0x00, 0x0A, 0x00}
+	if !bytes.Equal(req.RequestData, wantData) {
+		t.Errorf("RequestData = % X, want % X", req.RequestData, wantData)
+	}
+
+	req = NewGetInstanceAttributeListRequest("Main", 0)
+	wantPath = append(append([]byte{0x91, 0x0C}, "Program:Main"...), 0x20, 0x6B, 0x24, 0x00)
+	if !bytes.Equal(req.RequestPath, wantPath) {
+		t.Errorf("program RequestPath = % X, want % X", []byte(req.RequestPath), wantPath)
+	}
+}
+
+func TestDecodeGetInstanceAttributeListResponse(t *testing.T) {
+	data := encodeSymbolEntry(1, "Speed", uint16(TypeREAL), [3]uint32{}, ExternalAccessReadWrite)
+	data = append(data, encodeSymbolEntry(7, "Recipe", 0x2000|uint16(TypeDINT), [3]uint32{10}, ExternalAccessReadOnly)...)
+
+	symbols, err := DecodeGetInstanceAttributeListResponse("Main", data)
+	if err != nil {
+		t.Fatalf("DecodeGetInstanceAttributeListResponse() error = %v", err)
+	}
+	if len(symbols) != 2 {
+		t.Fatalf("got %d symbols, want 2", len(symbols))
+	}
+
+	s := symbols[1]
+	if s.InstanceID != 7 || s.Name != "Recipe" || s.Dimensions() != 1 || s.Dims[0] != 10 || s.ExternalAccess != ExternalAccessReadOnly {
+		t.Errorf("symbol = %+v", s)
+	}
+	if got := s.FullName(); got != "Program:Main.Recipe" {
+		t.Errorf("FullName() = %q", got)
+	}
+
+	if _, err := DecodeGetInstanceAttributeListResponse("", data[:len(data)-3]); err == nil {
+		t.Error("expected error for truncated reply")
+	}
+}
+
+func TestSymbolInstance_IsSystem(t *testing.T) {
+	tests := []struct {
+		s    SymbolInstance
+		want bool
+	}{
+		{SymbolInstance{Name: "Speed", Type: DataType(TypeREAL)}, false},
+		{SymbolInstance{Name: "__Hidden", Type: DataType(TypeDINT)}, true},
+		{SymbolInstance{Name: "Map:Local", Type: 0x1069}, true},
+		{SymbolInstance{Name: "Task:MainTask", Type: 0x1070}, true},
+		{SymbolInstance{Name: "Local:1:I", Type: 0x8123}, true},
+		{SymbolInstance{Name: "Program:Main", Type: 0x1068}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.s.IsSystem(); got != tt.want {
+			t.Errorf("%s.IsSystem() = %v, want %v", tt.s.Name, got, tt.want)
+		}
+	}
+
+	p := SymbolInstance{Name: "Program:Main"}
+	if !p.IsProgram() || p.ProgramName() != "Main" {
+		t.Errorf("IsProgram() = %v, ProgramName() = %q", p.IsProgram(), p.ProgramName())
+	}
+}
@@ -0,0 +1,222 @@
+package cip
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TagPath is a parsed Logix tag expression such as
+// "Program:Main.Motor[3].Speed", "Data[1,2]" or "Status.5".
+//
+// Each member of the expression becomes a symbolic segment, array
+// subscripts become element segments and a trailing numeric member is
+// kept aside as a bit selector (it is not part of the EPATH).
+type TagPath struct {
+	Segments []TagSegment
+	// Bit is the bit selector of the expression, or -1 if there is none.
+	Bit int
+}
+
+// TagSegment is one member of a tag expression with its array subscripts.
+type TagSegment struct {
+	Name    string
+	Indices []uint32
+}
+
+// ParseTagPath parses a Logix tag expression.
+//
+// The first member may carry a scope prefix containing colons
+// ("Program:Main", "Local:1:I"); later members must be plain identifiers.
+// Subscripts are decimal and comma separated for multi-dimensional arrays.
+// A final member made only of digits selects a bit of the preceding value.
+func ParseTagPath(tag string) (*TagPath, error) {
+	tp := &TagPath{Bit: -1}
+	if tag == "" {
+		return nil, fmt.Errorf("cip: empty tag path")
+	}
+
+	members := splitMembers(tag)
+	for i, m := range members {
+		last := i == len(members)-1
+
+		if i > 0 && last && isDigits(m) {
+			bit, err := strconv.Atoi(m)
+			if err != nil || bit > 63 {
+				return nil, fmt.Errorf("cip: invalid bit selector %q in %q", m, tag)
+			}
+			tp.Bit = bit
+			break
+		}
+
+		seg, err := parseTagSegment(m, i == 0)
+		if err != nil {
+			return nil, fmt.Errorf("cip: %w in %q", err, tag)
+		}
+		tp.Segments = append(tp.Segments, seg)
+	}
+
+	return tp, nil
+}
+
+// splitMembers splits a tag expression on dots that are not inside brackets.
+func splitMembers(tag string) []string {
+	var members []string
+	depth := 0
+	start := 0
+	for i := 0; i < len(tag); i++ {
+		switch tag[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '.':
+			if depth == 0 {
+				members = append(members, tag[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(members, tag[start:])
+}
+
+func parseTagSegment(s string, first bool) (TagSegment, error) {
+	name := s
+	var seg TagSegment
+
+	if open := strings.IndexByte(s, '['); open >= 0 {
+		if !strings.HasSuffix(s, "]") {
+			return seg, fmt.Errorf("unterminated subscript in %q", s)
+		}
+		name = s[:open]
+		for _, idx := range strings.Split(s[open+1:len(s)-1], ",") {
+			v, err := strconv.ParseUint(strings.TrimSpace(idx), 10, 32)
+			if err != nil {
+				return seg, fmt.Errorf("invalid subscript %q", idx)
+			}
+			seg.Indices = append(seg.Indices, uint32(v))
+		}
+	}
+
+	if !isTagName(name, first) {
+		return seg, fmt.Errorf("invalid member name %q", name)
+	}
+	seg.Name = name
+	return seg, nil
+}
+
+// isTagName reports whether s is a valid Logix identifier.
+// Scope prefixes with colons are only accepted for the first member.
+func isTagName(s string, allowScope bool) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '_' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z'):
+		case c >= '0' && c <= '9':
+			if i == 0 {
+				return false
+			}
+		case c == ':' && allowScope && i > 0 && i < len(s)-1:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// HasBit reports whether the expression ends with a bit selector.
+func (t *TagPath) HasBit() bool {
+	return t.Bit >= 0
+}
+
+// Path encodes the expression as an EPATH of symbolic and element segments.
+// The bit selector is not encoded; callers extract the bit from the value.
+func (t *TagPath) Path() Path {
+	p := NewPath()
+	for _, seg := range t.Segments {
+		p.AddSymbolicSegment(seg.Name)
+		for _, idx := range seg.Indices {
+			p.AddElement(idx)
+		}
+	}
+	return p
+}
+
+// Symbol returns the tag the expression starts with: its program, empty
+// for controller-scoped tags, and its name. ok is false when the
+// expression names a program only.
+func (t *TagPath) Symbol() (program, name string, ok bool) {
+	first := t.Segments[0].Name
+	if !strings.HasPrefix(first, programPrefix) {
+		return "", first, true
+	}
+	if len(t.Segments) < 2 {
+		return "", "", false
+	}
+	return strings.TrimPrefix(first, programPrefix), t.Segments[1].Name, true
+}
+
+// InstancePath encodes the expression like Path, but addresses the tag it
+// starts with by its Symbol Object instance instead of its name, which
+// makes a shorter request.
+func (t *TagPath) InstancePath(instanceID uint32) Path {
+	p := NewPath()
+	segments := t.Segments
+	if strings.HasPrefix(segments[0].Name, programPrefix) {
+		p.AddSymbolicSegment(segments[0].Name)
+		segments = segments[1:]
+	}
+
+	p.AddClass(ClassSymbol)
+	p.AddInstance32(instanceID)
+	for i, seg := range segments {
+		if i > 0 {
+			p.AddSymbolicSegment(seg.Name)
+		}
+		for _, idx := range seg.Indices {
+			p.AddElement(idx)
+		}
+	}
+	return p
+}
+
+// String returns the expression in Logix syntax.
+func (t *TagPath) String() string {
+	var sb strings.Builder
+	for i, seg := range t.Segments {
+		if i > 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(seg.Name)
+		if len(seg.Indices) > 0 {
+			sb.WriteByte('[')
+			for j, idx := range seg.Indices {
+				if j > 0 {
+					sb.WriteByte(',')
+				}
+				sb.WriteString(strconv.FormatUint(uint64(idx), 10))
+			}
+			sb.WriteByte(']')
+		}
+	}
+	if t.HasBit() {
+		sb.WriteByte('.')
+		sb.WriteString(strconv.Itoa(t.Bit))
+	}
+	return sb.String()
+}
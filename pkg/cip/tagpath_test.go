@@ -0,0 +1,208 @@
+package cip
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseTagPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		tag      string
+		wantPath []byte
+		wantBit  int
+	}{
+		{
+			name:     "Simple",
+			tag:      "Tag",
+			wantPath: []byte{0x91, 0x03, 'T', 'a', 'g', 0x00},
+			wantBit:  -1,
+		},
+		{
+			name: "Program Scope With Member And Index",
+			tag:  "Program:Main.Motor[3].Speed",
+			wantPath: []byte{
+				0x91, 0x0C, 'P', 'r', 'o', 'g', 'r', 'a', 'm', ':', 'M', 'a', 'i', 'n',
+				0x91, 0x05, 'M', 'o', 't', 'o', 'r', 0x00,
+				0x28, 0x03,
+				0x91, 0x05, 'S', 'p', 'e', 'e', 'd', 0x00,
+			},
+			wantBit: -1,
+		},
+		{
+			name: "Multi-Dimensional",
+			tag:  "Data[1, 300]",
+			wantPath: []byte{
+				0x91, 0x04, 'D', 'a', 't', 'a',
+				0x28, 0x01,
+				0x29, 0x00, 0x2C, 0x01,
+			},
+			wantBit: -1,
+		},
+		{
+			name: "32-bit Index",
+			tag:  "Big[70000]",
+			wantPath: []byte{
+				0x91, 0x03, 'B', 'i', 'g', 0x00,
+				0x2A, 0x00, 0x70, 0x11, 0x01, 0x00,
+			},
+			wantBit: -1,
+		},
+		{
+			name:     "Bit Selector",
+			tag:      "Status.5",
+			wantPath: []byte{0x91, 0x06, 'S', 't', 'a', 't', 'u', 's'},
+			wantBit:  5,
+		},
+		{
+			name: "Bit Of Array Element",
+			tag:  "Words[2].15",
+			wantPath: []byte{
+				0x91, 0x05, 'W', 'o', 'r', 'd', 's', 0x00,
+				0x28, 0x02,
+			},
+			wantBit: 15,
+		},
+		{
+			name:     "IO Module Tag",
+			tag:      "Local:1:I.Data",
+			wantPath: []byte{0x91, 0x09, 'L', 'o', 'c', 'a', 'l', ':', '1', ':', 'I', 0x00, 0x91, 0x04, 'D', 'a', 't', 'a'},
+			wantBit:  -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp, err := ParseTagPath(tt.tag)
+			if err != nil {
+				t.Fatalf("ParseTagPath(%q) error = %v", tt.tag, err)
+			}
+			if got := tp.Path(); !bytes.Equal(got, tt.wantPath) {
+				t.Errorf("Path() = %X, want %X", []byte(got), tt.wantPath)
+			}
+			if tp.Bit != tt.wantBit {
+				t.Errorf("Bit = %d, want %d", tp.Bit, tt.wantBit)
+			}
+		})
+	}
+}
+
+func TestParseTagPath_Errors(t *testing.T) {
+	tests := []string{
+		"",
+		"5",
+		"Tag.",
+		".Tag",
+		"A..B",
+		"Tag[",
+		"Tag[1",
+		"Tag[x]",
+		"Tag[-1]",
+		"Tag.Member:X",
+		"Tag.64",
+		"Tag.1.Member",
+		"1Tag",
+		"Tag Name",
+	}
+
+	for _, tag := range tests {
+		t.Run(tag, func(t *testing.T) {
+			if _, err := ParseTagPath(tag); err == nil {
+				t.Errorf("ParseTagPath(%q) expected error", tag)
+			}
+		})
+	}
+}
+
+func TestTagPath_String(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"Tag", "Tag"},
+		{"Program:Main.Motor[3].Speed", "Program:Main.Motor[3].Speed"},
+		{"Data[1, 2]", "Data[1,2]"},
+		{"Status.5", "Status.5"},
+		{"Words[2].15", "Words[2].15"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			tp, err := ParseTagPath(tt.tag)
+			if err != nil {
+				t.Fatalf("ParseTagPath(%q) error = %v", tt.tag, err)
+			}
+			if got := tp.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+
+			// The string form must parse back to the same path
+			again, err := ParseTagPath(tp.String())
+			if err != nil {
+				t.Fatalf("ParseTagPath(String()) error = %v", err)
+			}
+			if !bytes.Equal(again.Path(), tp.Path()) || again.Bit != tp.Bit {
+				t.Errorf("round trip mismatch for %q", tt.tag)
+			}
+		})
+	}
+}
+
+func TestTagPath_InstancePath(t *testing.T) {
+	tests := []struct {
+		tag  string
+		id   uint32
+		want []byte
+	}{
+		{
+			tag:  "Counter",
+			id:   0x0123,
+			want: []byte{0x20, 0x6B, 0x25, 0x00, 0x23, 0x01},
+		},
+		{
+			tag:  "Motor[3].Speed",
+			id:   0x10,
+			want: []byte{0x20, 0x6B, 0x24, 0x10, 0x28, 0x03, 0x91, 0x05, 'S', 'p', 'e', 'e', 'd', 0x00},
+		},
+		{
+			tag: "Program:Main.Step",
+			id:  5,
+			want: append(append([]byte{0x91, 0x0C}, "Program:Main"...),
+				0x20, 0x6B, 0x24, 0x05),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			tp, err := ParseTagPath(tt.tag)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tp.InstancePath(tt.id); !bytes.Equal(got, tt.want) {
+				t.Errorf("InstancePath() = % X, want % X", []byte(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestTagPath_Symbol(t *testing.T) {
+	tests := []struct {
+		tag           string
+		program, name string
+		ok            bool
+	}{
+		{"Motor[3].Speed", "", "Motor", true},
+		{"Program:Main.Step.2", "Main", "Step", true},
+		{"Program:Main", "", "", false},
+	}
+	for _, tt := range tests {
+		tp, err := ParseTagPath(tt.tag)
+		if err != nil {
+			t.Fatal(err)
+		}
+		program, name, ok := tp.Symbol()
+		if program != tt.program || name != tt.name || ok != tt.ok {
+			t.Errorf("%s: Symbol() = %q, %q, %v, want %q, %q, %v", tt.tag, program, name, ok, tt.program, tt.name, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,543 @@
+package cip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Template Object Class ID (Logix)
+const ClassTemplate UINT = 0x6C
+
+// ServiceReadTemplate reads the member definitions of a Template instance.
+// It shares its code with the Read Tag service but is addressed to class 0x6C.
+const ServiceReadTemplate USINT = 0x4C
+
+// Template Object attributes
+const (
+	TemplateAttrHandle         UINT = 1 // Structure Handle (CRC), UINT
+	TemplateAttrMemberCount    UINT = 2 // Number of members, UINT
+	TemplateAttrDefinitionSize UINT = 4 // Template definition size in 32-bit words, UDINT
+	TemplateAttrStructureSize  UINT = 5 // Size of the structure data in bytes, UDINT
+)
+
+// Symbol and template member type word layout (Logix):
+// Bit 15:     structure flag
+// Bits 13-14: number of array dimensions
+// Bit 12:     system/reserved
+// Bits 0-11:  atomic type code, or template instance ID for structures
+const (
+	symbolTypeStruct    = 0x8000
+	symbolTypeDimsMask  = 0x6000
+	symbolTypeDimsShift = 13
+	symbolTypeSystem    = 0x1000
+	symbolTypeIDMask    = 0x0FFF
+)
+
+// hiddenMemberPrefix marks the host SINT members that Logix creates to hold
+// packed BOOL members.
+const hiddenMemberPrefix = "ZZZZZZZZZZ"
+
+// Template describes the layout of a Logix structure (UDT or predefined type)
+// as reported by the Template Object.
+type Template struct {
+	InstanceID     uint16
+	Name           string
+	Handle         uint16 // Structure handle, also returned by Read Tag for this type
+	MemberCount    uint16
+	DefinitionSize uint32 // In 32-bit words
+	StructureSize  uint32 // In bytes
+	Members        []TemplateMember
+}
+
+// TemplateMember is one member of a Template.
+type TemplateMember struct {
+	Name string
+	// Info is the array length for array members and the bit number for
+	// BOOL members; zero otherwise.
+	Info     uint16
+	TypeCode uint16 // Raw type word, see IsStructure, TemplateID and Type
+	Offset   uint32 // Byte offset of the member within the structure
+}
+
+// IsStructure reports whether the member is itself a structure.
+func (m TemplateMember) IsStructure() bool {
+	return m.TypeCode&symbolTypeStruct != 0
+}
+
+// TemplateID returns the template instance of a structure member.
+func (m TemplateMember) TemplateID() uint16 {
+	return m.TypeCode & symbolTypeIDMask
+}
+
+// Type returns the atomic data type of a non-structure member.
+func (m TemplateMember) Type() DataType {
+	return DataType(m.TypeCode & 0x00FF)
+}
+
+// IsArray reports whether the member is an array.
+func (m TemplateMember) IsArray() bool {
+	return m.TypeCode&symbolTypeDimsMask != 0
+}
+
+// Hidden reports whether the member is an internal member that is not part
+// of the user-visible layout, such as the host SINT of packed BOOLs.
+func (m TemplateMember) Hidden() bool {
+	return strings.HasPrefix(m.Name, hiddenMemberPrefix) || strings.HasPrefix(m.Name, "__")
+}
+
+// IsStructure reports whether the symbol is a structure (UDT or predefined).
+func (s SymbolInstance) IsStructure() bool {
+	return uint16(s.Type)&symbolTypeStruct != 0
+}
+
+// TemplateID returns the template instance of a structure symbol.
+func (s SymbolInstance) TemplateID() uint16 {
+	return uint16(s.Type) & symbolTypeIDMask
+}
+
+// Dimensions returns the number of array dimensions of the symbol (0-3).
+func (s SymbolInstance) Dimensions() int {
+	return int(uint16(s.Type)&symbolTypeDimsMask) >> symbolTypeDimsShift
+}
+
+// NewGetTemplateAttributesRequest creates a Get Attribute List request for
+// the handle, member count, definition size and structure size of a template.
+func NewGetTemplateAttributesRequest(instanceID uint16) *MessageRouterRequest {
+	p := NewPath()
+	p.AddClass(ClassTemplate)
+	p.AddInstance(UINT(instanceID))
+
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, uint16(4)) // 4 Attributes
+	binary.Write(buf, binary.LittleEndian, TemplateAttrHandle)
+	binary.Write(buf, binary.LittleEndian, TemplateAttrMemberCount)
+	binary.Write(buf, binary.LittleEndian, TemplateAttrDefinitionSize)
+	binary.Write(buf, binary.LittleEndian, TemplateAttrStructureSize)
+
+	return &MessageRouterRequest{
+		Service:     ServiceGetAttributeList,
+		RequestPath: p,
+		RequestData: buf.Bytes(),
+	}
+}
+
+// DecodeTemplateAttributesResponse decodes the reply to
+// NewGetTemplateAttributesRequest into a Template without members.
+func DecodeTemplateAttributesResponse(instanceID uint16, data []byte) (*Template, error) {
+	r := bytes.NewReader(data)
+	var count uint16
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		return nil, err
+	}
+
+	t := &Template{InstanceID: instanceID}
+	for i := 0; i < int(count); i++ {
+		var attrID, status uint16
+		if err := binary.Read(r, binary.LittleEndian, &attrID); err != nil {
+			return nil, err
+		}
+		if err := binary.Read(r, binary.LittleEndian, &status); err != nil {
+			return nil, err
+		}
+		if status != 0 {
+			return nil, fmt.Errorf("cip: template %d attribute %d failed with status 0x%02X", instanceID, attrID, status)
+		}
+
+		var err error
+		switch UINT(attrID) {
+		case TemplateAttrHandle:
+			err = binary.Read(r, binary.LittleEndian, &t.Handle)
+		case TemplateAttrMemberCount:
+			err = binary.Read(r, binary.LittleEndian, &t.MemberCount)
+		case TemplateAttrDefinitionSize:
+			err = binary.Read(r, binary.LittleEndian, &t.DefinitionSize)
+		case TemplateAttrStructureSize:
+			err = binary.Read(r, binary.LittleEndian, &t.StructureSize)
+		default:
+			return nil, fmt.Errorf("cip: unexpected template attribute %d", attrID)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// DefinitionBytes returns the number of bytes to request with Read Template
+// to fetch the whole member definition.
+func (t *Template) DefinitionBytes() uint32 {
+	// The definition size counts 32-bit words and includes a 23-byte
+	// header that is not returned by the Read Template service.
+	if t.DefinitionSize*4 < 23 {
+		return 0
+	}
+	return t.DefinitionSize*4 - 23
+}
+
+// NewReadTemplateRequest creates a Read Template request (0x4C on class 0x6C)
+// for length bytes of the definition starting at offset.
+func NewReadTemplateRequest(instanceID uint16, offset uint32, length uint16) *MessageRouterRequest {
+	p := NewPath()
+	p.AddClass(ClassTemplate)
+	p.AddInstance(UINT(instanceID))
+
+	reqData := make([]byte, 6)
+	binary.LittleEndian.PutUint32(reqData[0:4], offset)
+	binary.LittleEndian.PutUint16(reqData[4:6], length)
+
+	return &MessageRouterRequest{
+		Service:     ServiceReadTemplate,
+		RequestPath: p,
+		RequestData: reqData,
+	}
+}
+
+// DecodeTemplateDefinition parses the data returned by Read Template into
+// t.Name and t.Members. t.MemberCount must already be set.
+//
+// The definition holds one 8-byte entry per member (Info UINT, Type UINT,
+// Offset UDINT), followed by the template name ("Name;n..." NUL terminated)
+// and the NUL terminated member names.
+func DecodeTemplateDefinition(t *Template, data []byte) error {
+	n := int(t.MemberCount)
+	if len(data) < n*8 {
+		return fmt.Errorf("cip: template definition too short: %d bytes for %d members", len(data), n)
+	}
+
+	members := make([]TemplateMember, n)
+	for i := range members {
+		entry := data[i*8:]
+		members[i] = TemplateMember{
+			Info:     binary.LittleEndian.Uint16(entry[0:2]),
+			TypeCode: binary.LittleEndian.Uint16(entry[2:4]),
+			Offset:   binary.LittleEndian.Uint32(entry[4:8]),
+		}
+	}
+
+	names := strings.Split(string(data[n*8:]), "\x00")
+	if len(names) < n+1 {
+		return fmt.Errorf("cip: template definition has %d names for %d members", len(names)-1, n)
+	}
+
+	name := names[0]
+	if i := strings.IndexByte(name, ';'); i >= 0 {
+		name = name[:i]
+	}
+	t.Name = name
+
+	for i := range members {
+		members[i].Name = names[i+1]
+	}
+	t.Members = members
+	return nil
+}
+
+// TemplateLookup resolves the template of a nested structure member.
+type TemplateLookup func(instanceID uint16) (*Template, error)
+
+// Decode converts raw structure bytes into a tree of Go values keyed by
+// member name. Atomic members decode to their native Go type (int32,
+// float32, bool, ...), arrays to []any, string structures to string and
+// other nested structures to map[string]any using lookup. Hidden members
+// are omitted.
+func (t *Template) Decode(data []byte, lookup TemplateLookup) (map[string]any, error) {
+	if uint32(len(data)) < t.StructureSize {
+		return nil, fmt.Errorf("cip: %s needs %d bytes, got %d", t.Name, t.StructureSize, len(data))
+	}
+
+	out := make(map[string]any, len(t.Members))
+	for _, m := range t.Members {
+		if m.Hidden() {
+			continue
+		}
+		v, err := t.decodeMember(m, data, lookup)
+		if err != nil {
+			return nil, fmt.Errorf("cip: %s.%s: %w", t.Name, m.Name, err)
+		}
+		out[m.Name] = v
+	}
+	return out, nil
+}
+
+func (t *Template) decodeMember(m TemplateMember, data []byte, lookup TemplateLookup) (any, error) {
+	off := int(m.Offset)
+
+	// BOOL members are single bits of a host byte
+	if !m.IsStructure() && m.Type() == TypeBOOL && !m.IsArray() {
+		if off >= len(data) {
+			return nil, fmt.Errorf("offset %d out of range", off)
+		}
+		return data[off]&(1<<(m.Info%8)) != 0, nil
+	}
+
+	// BOOL arrays are packed one bit per element starting at the offset
+	if !m.IsStructure() && m.Type() == TypeBOOL {
+		count := int(m.Info)
+		if off+(count+7)/8 > len(data) {
+			return nil, fmt.Errorf("BOOL array of %d elements at offset %d out of range", count, off)
+		}
+		values := make([]any, count)
+		for i := range values {
+			values[i] = data[off+i/8]&(1<<(i%8)) != 0
+		}
+		return values, nil
+	}
+
+	var nested *Template
+	elemSize := 0
+	if m.IsStructure() {
+		if lookup == nil {
+			return nil, fmt.Errorf("no template lookup for nested structure %d", m.TemplateID())
+		}
+		var err error
+		nested, err = lookup(m.TemplateID())
+		if err != nil {
+			return nil, err
+		}
+		elemSize = int(nested.StructureSize)
+	} else {
+		elemSize = AtomicSize(m.Type())
+		if elemSize == 0 {
+			return nil, fmt.Errorf("unsupported member type %s", m.Type())
+		}
+	}
+
+	decodeOne := func(b []byte) (any, error) {
+		if nested != nil {
+			if st, ok := StringTypeFromTemplate(nested); ok {
+				return st.Decode(b)
+			}
+			return nested.Decode(b, lookup)
+		}
+		return DecodeAtomic(m.Type(), b)
+	}
+
+	if !m.IsArray() {
+		if off+elemSize > len(data) {
+			return nil, fmt.Errorf("offset %d out of range", off)
+		}
+		return decodeOne(data[off : off+elemSize])
+	}
+
+	count := int(m.Info)
+	if off+count*elemSize > len(data) {
+		return nil, fmt.Errorf("array of %d elements at offset %d out of range", count, off)
+	}
+	values := make([]any, count)
+	for i := range values {
+		start := off + i*elemSize
+		v, err := decodeOne(data[start : start+elemSize])
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// Encode writes members, keyed by member name like the result of Decode,
+// into data, the raw bytes of a structure with this template. Members
+// missing from the map keep their bytes, so data is usually the current
+// value of the tag; names without a member are ignored. Atomic members take
+// any Go number, arrays take a slice of their elements, string structures a
+// string and other nested structures a map[string]any.
+func (t *Template) Encode(data []byte, members map[string]any, lookup TemplateLookup) error {
+	if uint32(len(data)) < t.StructureSize {
+		return fmt.Errorf("cip: %s needs %d bytes, got %d", t.Name, t.StructureSize, len(data))
+	}
+
+	for _, m := range t.Members {
+		v, ok := members[m.Name]
+		if !ok || m.Hidden() {
+			continue
+		}
+		if err := t.encodeMember(m, data, v, lookup); err != nil {
+			return fmt.Errorf("cip: %s.%s: %w", t.Name, m.Name, err)
+		}
+	}
+	return nil
+}
+
+func (t *Template) encodeMember(m TemplateMember, data []byte, v any, lookup TemplateLookup) error {
+	off := int(m.Offset)
+
+	// BOOL members are single bits of a host byte
+	if !m.IsStructure() && m.Type() == TypeBOOL && !m.IsArray() {
+		b, ok := v.(bool)
+		if !ok {
+			return fmt.Errorf("%T is not a bool", v)
+		}
+		if off >= len(data) {
+			return fmt.Errorf("offset %d out of range", off)
+		}
+		setBit(data[off:], int(m.Info%8), b)
+		return nil
+	}
+
+	var nested *Template
+	elemSize := 0
+	if m.IsStructure() {
+		if lookup == nil {
+			return fmt.Errorf("no template lookup for nested structure %d", m.TemplateID())
+		}
+		var err error
+		nested, err = lookup(m.TemplateID())
+		if err != nil {
+			return err
+		}
+		elemSize = int(nested.StructureSize)
+	} else if m.Type() != TypeBOOL {
+		elemSize = AtomicSize(m.Type())
+		if elemSize == 0 {
+			return fmt.Errorf("unsupported member type %s", m.Type())
+		}
+	}
+
+	encodeOne := func(b []byte, v any) error {
+		if nested == nil {
+			rv := reflect.ValueOf(v)
+			if !isNumber(rv.Kind()) {
+				return fmt.Errorf("%T is not a number", v)
+			}
+			encodeAtomic(m.Type(), b, rv)
+			return nil
+		}
+		if st, ok := StringTypeFromTemplate(nested); ok {
+			s, ok := v.(string)
+			if !ok {
+				return fmt.Errorf("%T is not a string", v)
+			}
+			enc, err := st.Encode(s)
+			if err != nil {
+				return err
+			}
+			copy(b, enc)
+			return nil
+		}
+		fields, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%T is not a map[string]any", v)
+		}
+		return nested.Encode(b, fields, lookup)
+	}
+
+	if !m.IsArray() {
+		if off+elemSize > len(data) {
+			return fmt.Errorf("offset %d out of range", off)
+		}
+		return encodeOne(data[off:off+elemSize], v)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return fmt.Errorf("%T is not a slice", v)
+	}
+	count := int(m.Info)
+	if rv.Len() > count {
+		return fmt.Errorf("%d elements do not fit an array of %d", rv.Len(), count)
+	}
+
+	// BOOL arrays are packed one bit per element starting at the offset
+	if elemSize == 0 {
+		if off+(count+7)/8 > len(data) {
+			return fmt.Errorf("BOOL array of %d elements at offset %d out of range", count, off)
+		}
+		for i := 0; i < rv.Len(); i++ {
+			b, ok := rv.Index(i).Interface().(bool)
+			if !ok {
+				return fmt.Errorf("element %d: %T is not a bool", i, rv.Index(i).Interface())
+			}
+			setBit(data[off+i/8:], i%8, b)
+		}
+		return nil
+	}
+
+	if off+count*elemSize > len(data) {
+		return fmt.Errorf("array of %d elements at offset %d out of range", count, off)
+	}
+	for i := 0; i < rv.Len(); i++ {
+		start := off + i*elemSize
+		if err := encodeOne(data[start:start+elemSize], rv.Index(i).Interface()); err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// setBit sets or clears bit n of b[0].
+func setBit(b []byte, n int, value bool) {
+	if value {
+		b[0] |= 1 << n
+	} else {
+		b[0] &^= 1 << n
+	}
+}
+
+// isNumber reports whether values of kind k are integers or floats.
+func isNumber(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// AtomicSize returns the encoded size in bytes of an atomic data type, or 0
+// if the type is not atomic.
+func AtomicSize(t DataType) int {
+	switch t {
+	case TypeBOOL, TypeSINT, TypeUSINT, TypeBYTE:
+		return 1
+	case TypeINT, TypeUINT, TypeWORD:
+		return 2
+	case TypeDINT, TypeUDINT, TypeREAL, TypeDWORD:
+		return 4
+	case TypeLINT, TypeULINT, TypeLREAL, TypeLWORD:
+		return 8
+	}
+	return 0
+}
+
+// DecodeAtomic decodes one little-endian value of an atomic data type into
+// its native Go type.
+func DecodeAtomic(t DataType, b []byte) (any, error) {
+	size := AtomicSize(t)
+	if size == 0 {
+		return nil, fmt.Errorf("cip: %s is not an atomic type", t)
+	}
+	if len(b) < size {
+		return nil, fmt.Errorf("cip: %s needs %d bytes, got %d", t, size, len(b))
+	}
+
+	switch t {
+	case TypeBOOL:
+		return b[0] != 0, nil
+	case TypeSINT:
+		return int8(b[0]), nil
+	case TypeUSINT, TypeBYTE:
+		return b[0], nil
+	case TypeINT:
+		return int16(binary.LittleEndian.Uint16(b)), nil
+	case TypeUINT, TypeWORD:
+		return binary.LittleEndian.Uint16(b), nil
+	case TypeDINT:
+		return int32(binary.LittleEndian.Uint32(b)), nil
+	case TypeUDINT, TypeDWORD:
+		return binary.LittleEndian.Uint32(b), nil
+	case TypeREAL:
+		return math.Float32frombits(binary.LittleEndian.Uint32(b)), nil
+	case TypeLINT:
+		return int64(binary.LittleEndian.Uint64(b)), nil
+	case TypeULINT, TypeLWORD:
+		return binary.LittleEndian.Uint64(b), nil
+	case TypeLREAL:
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+	}
+	return nil, fmt.Errorf("cip: %s is not an atomic type", t)
+}
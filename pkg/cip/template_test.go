@@ -0,0 +1,269 @@
+package cip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// buildTemplateDefinition encodes members the way Read Template returns them.
+func buildTemplateDefinition(name string, members []TemplateMember) []byte {
+	var buf bytes.Buffer
+	for _, m := range members {
+		binary.Write(&buf, binary.LittleEndian, m.Info)
+		binary.Write(&buf, binary.LittleEndian, m.TypeCode)
+		binary.Write(&buf, binary.LittleEndian, m.Offset)
+	}
+	buf.WriteString(name + ";n\x00")
+	for _, m := range members {
+		buf.WriteString(m.Name + "\x00")
+	}
+	return buf.Bytes()
+}
+
+var testPointMembers = []TemplateMember{
+	{Name: "X", TypeCode: uint16(TypeINT), Offset: 0},
+	{Name: "Y", TypeCode: uint16(TypeDINT), Offset: 4},
+}
+
+var testUDTMembers = []TemplateMember{
+	{Name: "ZZZZZZZZZZMyUDT0", TypeCode: uint16(TypeSINT), Offset: 0},
+	{Name: "Run", Info: 0, TypeCode: uint16(TypeBOOL), Offset: 0},
+	{Name: "Fault", Info: 1, TypeCode: uint16(TypeBOOL), Offset: 0},
+	{Name: "Count", TypeCode: uint16(TypeDINT), Offset: 4},
+	{Name: "Speeds", Info: 3, TypeCode: 0x2000 | uint16(TypeREAL), Offset: 8},
+	{Name: "Pos", TypeCode: 0x8123, Offset: 20},
+	{Name: "Flags", Info: 10, TypeCode: 0x2000 | uint16(TypeBOOL), Offset: 28},
+}
+
+func TestDecodeTemplateAttributesResponse(t *testing.T) {
+	data := []byte{
+		0x04, 0x00, // Count
+		0x01, 0x00, 0x00, 0x00, 0xCD, 0xAB, // Handle
+		0x02, 0x00, 0x00, 0x00, 0x06, 0x00, // Member Count
+		0x04, 0x00, 0x00, 0x00, 0x20, 0x00, 0x00, 0x00, // Definition Size
+		0x05, 0x00, 0x00, 0x00, 0x1C, 0x00, 0x00, 0x00, // Structure Size
+	}
+
+	tmpl, err := DecodeTemplateAttributesResponse(0x0456, data)
+	if err != nil {
+		t.Fatalf("DecodeTemplateAttributesResponse() error = %v", err)
+	}
+
+	want := &Template{InstanceID: 0x0456, Handle: 0xABCD, MemberCount: 6, DefinitionSize: 32, StructureSize: 28}
+	if !reflect.DeepEqual(tmpl, want) {
+		t.Errorf("got %+v, want %+v", tmpl, want)
+	}
+	if tmpl.DefinitionBytes() != 32*4-23 {
+		t.Errorf("DefinitionBytes() = %d, want %d", tmpl.DefinitionBytes(), 32*4-23)
+	}
+}
+
+func TestDecodeTemplateAttributesResponse_AttributeError(t *testing.T) {
+	data := []byte{0x01, 0x00, 0x01, 0x00, 0x14, 0x00}
+	if _, err := DecodeTemplateAttributesResponse(1, data); err == nil {
+		t.Error("expected error for failed attribute")
+	}
+}
+
+func TestNewReadTemplateRequest(t *testing.T) {
+	req := NewReadTemplateRequest(0x0123, 0x10, 0x64)
+	if req.Service != ServiceReadTemplate {
+		t.Errorf("Service = 0x%02X, want 0x4C", req.Service)
+	}
+	wantPath := []byte{0x20, 0x6C, 0x25, 0x00, 0x23, 0x01}
+	if !bytes.Equal(req.RequestPath, wantPath) {
+		t.Errorf("RequestPath = %X, want %X", []byte(req.RequestPath), wantPath)
+	}
+	wantData := []byte{0x10, 0x00, 0x00, 0x00, 0x64, 0x00}
+	if !bytes.Equal(req.RequestData, wantData) {
+		t.Errorf("RequestData = %X, want %X", req.RequestData, wantData)
+	}
+}
+
+func TestDecodeTemplateDefinition(t *testing.T) {
+	tmpl := &Template{MemberCount: uint16(len(testUDTMembers))}
+	if err := DecodeTemplateDefinition(tmpl, buildTemplateDefinition("MyUDT", testUDTMembers)); err != nil {
+		t.Fatalf("DecodeTemplateDefinition() error = %v", err)
+	}
+
+	if tmpl.Name != "MyUDT" {
+		t.Errorf("Name = %q, want MyUDT", tmpl.Name)
+	}
+	if !reflect.DeepEqual(tmpl.Members, testUDTMembers) {
+		t.Errorf("Members = %+v, want %+v", tmpl.Members, testUDTMembers)
+	}
+
+	speeds := tmpl.Members[4]
+	if !speeds.IsArray() || speeds.IsStructure() || speeds.Type() != TypeREAL {
+		t.Errorf("Speeds flags wrong: %+v", speeds)
+	}
+	pos := tmpl.Members[5]
+	if !pos.IsStructure() || pos.TemplateID() != 0x0123 {
+		t.Errorf("Pos flags wrong: %+v", pos)
+	}
+	if !tmpl.Members[0].Hidden() || tmpl.Members[1].Hidden() {
+		t.Error("Hidden() mismatch")
+	}
+}
+
+func TestDecodeTemplateDefinition_Short(t *testing.T) {
+	tmpl := &Template{MemberCount: 2}
+	if err := DecodeTemplateDefinition(tmpl, make([]byte, 10)); err == nil {
+		t.Error("expected error for short definition")
+	}
+	if err := DecodeTemplateDefinition(tmpl, make([]byte, 16)); err == nil {
+		t.Error("expected error for missing names")
+	}
+}
+
+func TestTemplate_Decode(t *testing.T) {
+	point := &Template{InstanceID: 0x0123, Name: "Point", StructureSize: 8, Members: testPointMembers}
+	udt := &Template{InstanceID: 0x0456, Name: "MyUDT", StructureSize: 32, Members: testUDTMembers}
+
+	data := make([]byte, 32)
+	data[0] = 0x02 // Fault
+	binary.LittleEndian.PutUint32(data[4:], 42)
+	binary.LittleEndian.PutUint32(data[8:], 0x3F800000)  // 1.0
+	binary.LittleEndian.PutUint32(data[12:], 0x40000000) // 2.0
+	binary.LittleEndian.PutUint32(data[16:], 0x40400000) // 3.0
+	binary.LittleEndian.PutUint16(data[20:], 0xFFFF)     // X = -1
+	binary.LittleEndian.PutUint32(data[24:], 100000)     // Y
+	data[28] = 0x01                                      // Flags[0]
+	data[29] = 0x02                                      // Flags[9]
+
+	lookup := func(id uint16) (*Template, error) {
+		if id == point.InstanceID {
+			return point, nil
+		}
+		return nil, fmt.Errorf("unknown template %d", id)
+	}
+
+	got, err := udt.Decode(data, lookup)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	want := map[string]any{
+		"Run":    false,
+		"Fault":  true,
+		"Count":  int32(42),
+		"Speeds": []any{float32(1), float32(2), float32(3)},
+		"Pos":    map[string]any{"X": int16(-1), "Y": int32(100000)},
+		"Flags":  []any{true, false, false, false, false, false, false, false, false, true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode() = %#v, want %#v", got, want)
+	}
+
+	if _, err := udt.Decode(data[:10], lookup); err == nil {
+		t.Error("expected error for short data")
+	}
+	if _, err := udt.Decode(data, nil); err == nil {
+		t.Error("expected error without lookup for nested structure")
+	}
+}
+
+func TestTemplate_Encode(t *testing.T) {
+	point := &Template{InstanceID: 0x0123, Name: "Point", StructureSize: 8, Members: testPointMembers}
+	udt := &Template{InstanceID: 0x0456, Name: "MyUDT", StructureSize: 32, Members: testUDTMembers}
+	lookup := func(id uint16) (*Template, error) {
+		if id == point.InstanceID {
+			return point, nil
+		}
+		return nil, fmt.Errorf("unknown template %d", id)
+	}
+
+	data := make([]byte, 32)
+	data[0] = 0x01                                 // Run, left out below
+	binary.LittleEndian.PutUint32(data[16:], 0xAA) // Speeds[2], left out below
+
+	members := map[string]any{
+		"Fault":   true,
+		"Count":   42, // Any Go number
+		"Speeds":  []float32{1, 2},
+		"Pos":     map[string]any{"X": int16(-1), "Y": int32(100000)},
+		"Flags":   []bool{true, false, false, false, false, false, false, false, false, true},
+		"Unknown": "ignored",
+	}
+	if err := udt.Encode(data, members, lookup); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	got, err := udt.Decode(data, lookup)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	want := map[string]any{
+		"Run":    true,
+		"Fault":  true,
+		"Count":  int32(42),
+		"Speeds": []any{float32(1), float32(2), math.Float32frombits(0xAA)},
+		"Pos":    map[string]any{"X": int16(-1), "Y": int32(100000)},
+		"Flags":  []any{true, false, false, false, false, false, false, false, false, true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode(Encode()) = %#v, want %#v", got, want)
+	}
+
+	for name, v := range map[string]any{
+		"Count":  true,
+		"Fault":  1,
+		"Speeds": []float32{1, 2, 3, 4},
+		"Pos":    "x",
+	} {
+		if err := udt.Encode(data, map[string]any{name: v}, lookup); err == nil {
+			t.Errorf("Encode(%s: %v) expected error", name, v)
+		}
+	}
+}
+
+func TestSymbolInstance_TypeBits(t *testing.T) {
+	s := SymbolInstance{Type: DataType(0x8000 | 0x2000 | 0x0123)}
+	if !s.IsStructure() || s.TemplateID() != 0x0123 || s.Dimensions() != 1 {
+		t.Errorf("struct array: IsStructure=%v TemplateID=0x%X Dimensions=%d", s.IsStructure(), s.TemplateID(), s.Dimensions())
+	}
+
+	s = SymbolInstance{Type: DataType(0x4000 | uint16(TypeDINT))}
+	if s.IsStructure() || s.Dimensions() != 2 {
+		t.Errorf("atomic 2D: IsStructure=%v Dimensions=%d", s.IsStructure(), s.Dimensions())
+	}
+}
+
+func TestDecodeAtomic(t *testing.T) {
+	tests := []struct {
+		typ  DataType
+		data []byte
+		want any
+	}{
+		{TypeBOOL, []byte{0x01}, true},
+		{TypeSINT, []byte{0xFF}, int8(-1)},
+		{TypeUINT, []byte{0x34, 0x12}, uint16(0x1234)},
+		{TypeDINT, []byte{0xFE, 0xFF, 0xFF, 0xFF}, int32(-2)},
+		{TypeREAL, []byte{0x00, 0x00, 0x80, 0x3F}, float32(1)},
+		{TypeLINT, []byte{0x01, 0, 0, 0, 0, 0, 0, 0x80}, int64(-9223372036854775807)},
+		{TypeLREAL, []byte{0, 0, 0, 0, 0, 0, 0xF0, 0x3F}, float64(1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typ.String(), func(t *testing.T) {
+			got, err := DecodeAtomic(tt.typ, tt.data)
+			if err != nil {
+				t.Fatalf("DecodeAtomic() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("DecodeAtomic() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := DecodeAtomic(TypeSTRING, []byte{0}); err == nil {
+		t.Error("expected error for non-atomic type")
+	}
+	if _, err := DecodeAtomic(TypeDINT, []byte{0}); err == nil {
+		t.Error("expected error for short data")
+	}
+}
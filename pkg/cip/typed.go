@@ -0,0 +1,425 @@
+package cip
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// UnmarshalTag and MarshalTag check the type of a tag against a Go type
+// before converting between them, where Marshal and Unmarshal trust the
+// caller:
+//
+//   - Numbers convert only when no value can be lost: a DINT decodes into
+//     int32, int64 or float64, but not into int16, uint32 or float32.
+//     Encoding checks the value itself, so int(5) encodes as a SINT.
+//   - bool matches BOOL, and arrays of bool match BOOL arrays (DWORD).
+//   - string matches the elementary string types and string structures.
+//   - Structures match by structure handle when the Go type implements
+//     StructHandler, and by size otherwise.
+//   - time.Time matches DATE, TIME_OF_DAY, DATE_AND_TIME and LINT
+//     (microseconds since 1970); time.Duration matches the duration types
+//     and LINT (microseconds). See datetime.go.
+//   - Arrays and slices match element by element.
+//
+// Types implementing Marshaler or Unmarshaler check the data themselves;
+// only their structure handle is compared.
+
+// StructHandler is implemented by Go types describing a Logix structure.
+// StructHandle returns the structure handle (the CRC of the template)
+// that Read Tag replies carry for the structure.
+type StructHandler interface {
+	StructHandle() uint16
+}
+
+// TypeMismatchError reports a tag whose type does not match the Go type it
+// is read into or written from.
+type TypeMismatchError struct {
+	DataType DataType
+	Handle   uint16 // Structure handle when DataType is TypeSTRUCT
+	GoType   reflect.Type
+}
+
+func (e *TypeMismatchError) Error() string {
+	if e.DataType == TypeSTRUCT {
+		return fmt.Sprintf("cip: type mismatch: structure 0x%04X does not match %s", e.Handle, e.GoType)
+	}
+	return fmt.Sprintf("cip: type mismatch: %s does not match %s", e.DataType, e.GoType)
+}
+
+// Is reports whether target is ErrTypeMismatch, which controllers return
+// for a write of the wrong type.
+func (e *TypeMismatchError) Is(target error) bool {
+	t, ok := target.(Error)
+	return ok && t.Status == ErrTypeMismatch.Status &&
+		len(t.ExtStatus) == 1 && t.ExtStatus[0] == ErrTypeMismatch.ExtStatus[0]
+}
+
+// SplitTypeInfo splits the type information of a Read Tag reply (see
+// SplitReadTagResponse) into the data type and, for structures, the
+// structure handle.
+func SplitTypeInfo(typeInfo []byte) (DataType, uint16) {
+	if len(typeInfo) < 2 {
+		return 0, 0
+	}
+	dataType := DataType(binary.LittleEndian.Uint16(typeInfo))
+	if len(typeInfo) < 4 {
+		return dataType, 0
+	}
+	return dataType, binary.LittleEndian.Uint16(typeInfo[2:])
+}
+
+// UnmarshalTag decodes the value of a Read Tag reply into v, which must be
+// a pointer, after checking the tag's type information against the type
+// of v. A type that does not match returns a *TypeMismatchError.
+func UnmarshalTag(typeInfo, value []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("cip: UnmarshalTag(non-pointer %T)", v)
+	}
+	dataType, handle := SplitTypeInfo(typeInfo)
+	return decodeTag(dataType, handle, value, rv.Elem())
+}
+
+// MarshalTag encodes v as a value of the tag type described by typeInfo,
+// converting numbers that fit. size is the size of one element of the tag,
+// the length of the value Read Tag returns for it; structures that do not
+// implement StructHandler must have that size, unless size is 0. A type or
+// value that does not match returns an error wrapping a *TypeMismatchError.
+func MarshalTag(typeInfo []byte, size int, v any) ([]byte, error) {
+	dataType, handle := SplitTypeInfo(typeInfo)
+	return encodeTag(dataType, handle, size, addressable(reflect.ValueOf(v)))
+}
+
+func mismatch(dataType DataType, handle uint16, rt reflect.Type) error {
+	return &TypeMismatchError{DataType: dataType, Handle: handle, GoType: rt}
+}
+
+func decodeTag(dataType DataType, handle uint16, data []byte, v reflect.Value) error {
+	rt := v.Type()
+	if u, ok := v.Addr().Interface().(Unmarshaler); ok {
+		if err := checkHandle(dataType, handle, rt); err != nil {
+			return err
+		}
+		return u.UnmarshalCIP(data)
+	}
+	if rt == timeType || rt == durationType {
+		ct, err := timeLayout(rt, dataType)
+		if err != nil {
+			return mismatch(dataType, handle, rt)
+		}
+		if len(data) < ct.size {
+			return fmt.Errorf("cip: %s needs %d bytes, got %d", dataType, ct.size, len(data))
+		}
+		return ct.decode(data, v)
+	}
+
+	switch rt.Kind() {
+	case reflect.Bool:
+		if dataType != TypeBOOL || len(data) < 1 {
+			return mismatch(dataType, handle, rt)
+		}
+		v.SetBool(data[0] != 0)
+		return nil
+
+	case reflect.String:
+		if !IsStringType(dataType) && dataType != TypeSTRUCT {
+			return mismatch(dataType, handle, rt)
+		}
+		s, err := DecodeString(dataType, data)
+		if err != nil {
+			return err
+		}
+		v.SetString(s)
+		return nil
+
+	case reflect.Struct:
+		if err := checkStruct(dataType, handle, len(data), rt); err != nil {
+			return err
+		}
+		return unmarshalValue(data, v)
+
+	case reflect.Array, reflect.Slice:
+		return decodeTagArray(dataType, handle, data, v)
+	}
+
+	if !converts(dataType, rt.Kind()) {
+		return mismatch(dataType, handle, rt)
+	}
+	val, err := DecodeAtomic(dataType, data)
+	if err != nil {
+		return err
+	}
+	v.Set(reflect.ValueOf(val).Convert(rt))
+	return nil
+}
+
+// decodeTagArray decodes the elements of data into an array, or into a
+// slice sized to hold all of them.
+func decodeTagArray(dataType DataType, handle uint16, data []byte, v reflect.Value) error {
+	elem := v.Type().Elem()
+	if elem.Kind() == reflect.Bool {
+		if dataType != TypeDWORD {
+			return mismatch(dataType, handle, v.Type())
+		}
+		return unmarshalValue(data, v)
+	}
+
+	size := AtomicSize(dataType)
+	n := v.Len()
+	if v.Kind() == reflect.Slice {
+		if size == 0 {
+			// Structures: size the slice from the Go layout
+			ct, err := layoutOf(elem, noOptions)
+			if err != nil {
+				return err
+			}
+			size = max(ct.size, ct.used)
+		}
+		if size > 0 {
+			n = len(data) / size
+		}
+		if v.Len() != n {
+			v.Set(reflect.MakeSlice(v.Type(), n, n))
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	if size == 0 {
+		size = len(data) / n
+	}
+	if len(data) < n*size {
+		return fmt.Errorf("cip: %d elements of %s need %d bytes, got %d", n, dataType, n*size, len(data))
+	}
+
+	for i := 0; i < n; i++ {
+		if err := decodeTag(dataType, handle, data[i*size:(i+1)*size], v.Index(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeTag(dataType DataType, handle uint16, size int, v reflect.Value) ([]byte, error) {
+	rt := v.Type()
+	if m, ok := v.Addr().Interface().(Marshaler); ok {
+		if err := checkHandle(dataType, handle, rt); err != nil {
+			return nil, err
+		}
+		return m.MarshalCIP()
+	}
+	if rt == timeType || rt == durationType {
+		ct, err := timeLayout(rt, dataType)
+		if err != nil {
+			return nil, mismatch(dataType, handle, rt)
+		}
+		data := make([]byte, ct.size)
+		if err := ct.encode(data, v); err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+
+	switch rt.Kind() {
+	case reflect.Bool:
+		if dataType != TypeBOOL {
+			return nil, mismatch(dataType, handle, rt)
+		}
+		if v.Bool() {
+			return []byte{1}, nil
+		}
+		return []byte{0}, nil
+
+	case reflect.String:
+		if dataType == TypeSTRUCT && handle == StringHandle {
+			return StringType{Handle: handle, Capacity: defaultStringLength}.Encode(v.String())
+		}
+		if !IsStringType(dataType) {
+			return nil, mismatch(dataType, handle, rt)
+		}
+		return EncodeString(dataType, v.String())
+
+	case reflect.Struct:
+		ct, err := layoutOf(rt, noOptions)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			size = ct.size
+		}
+		if err := checkStruct(dataType, handle, size, rt); err != nil {
+			return nil, err
+		}
+		return marshalValue(v.Interface())
+
+	case reflect.Array, reflect.Slice:
+		if rt.Elem().Kind() == reflect.Bool {
+			if dataType != TypeDWORD {
+				return nil, mismatch(dataType, handle, rt)
+			}
+			if v.Kind() == reflect.Slice {
+				return packBoolArray(v.Interface().([]bool))
+			}
+			return marshalValue(v.Interface())
+		}
+		var data []byte
+		for i := 0; i < v.Len(); i++ {
+			b, err := encodeTag(dataType, handle, size, addressable(v.Index(i)))
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, b...)
+		}
+		return data, nil
+	}
+
+	if class, _ := classOfKind(rt.Kind()); class == 0 || classOf(dataType) == 0 {
+		return nil, mismatch(dataType, handle, rt)
+	}
+	if !fits(dataType, v) {
+		return nil, fmt.Errorf("cip: %v does not fit: %w", v.Interface(), mismatch(dataType, handle, rt))
+	}
+	data := make([]byte, AtomicSize(dataType))
+	encodeAtomic(dataType, data, v)
+	return data, nil
+}
+
+// checkHandle compares the structure handle of a tag with the one of rt,
+// if rt implements StructHandler.
+func checkHandle(dataType DataType, handle uint16, rt reflect.Type) error {
+	h, ok := reflect.New(rt).Interface().(StructHandler)
+	if !ok {
+		return nil
+	}
+	if dataType != TypeSTRUCT || h.StructHandle() != handle {
+		return mismatch(dataType, handle, rt)
+	}
+	return nil
+}
+
+// checkStruct checks that a structure of size bytes matches the struct
+// type rt.
+func checkStruct(dataType DataType, handle uint16, size int, rt reflect.Type) error {
+	if dataType != TypeSTRUCT {
+		return mismatch(dataType, handle, rt)
+	}
+	if _, ok := reflect.New(rt).Interface().(StructHandler); ok {
+		return checkHandle(dataType, handle, rt)
+	}
+	ct, err := layoutOf(rt, noOptions)
+	if err != nil {
+		return err
+	}
+	if size != ct.size {
+		return mismatch(dataType, handle, rt)
+	}
+	return nil
+}
+
+type numberClass int
+
+const (
+	classSigned numberClass = iota + 1
+	classUnsigned
+	classFloat
+)
+
+// classOf returns the class of a numeric data type, or 0.
+func classOf(t DataType) numberClass {
+	switch t {
+	case TypeSINT, TypeINT, TypeDINT, TypeLINT:
+		return classSigned
+	case TypeUSINT, TypeUINT, TypeUDINT, TypeULINT, TypeBYTE, TypeWORD, TypeDWORD, TypeLWORD:
+		return classUnsigned
+	case TypeREAL, TypeLREAL:
+		return classFloat
+	}
+	return 0
+}
+
+// classOfKind returns the class and size of a numeric Go kind.
+func classOfKind(k reflect.Kind) (numberClass, int) {
+	switch k {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return classSigned, AtomicSize(defaultAtomicType(k))
+	case reflect.Int:
+		return classSigned, 8
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return classUnsigned, AtomicSize(defaultAtomicType(k))
+	case reflect.Uint:
+		return classUnsigned, 8
+	case reflect.Float32:
+		return classFloat, 4
+	case reflect.Float64:
+		return classFloat, 8
+	}
+	return 0, 0
+}
+
+// converts reports whether every value of type t converts to kind k
+// without loss.
+func converts(t DataType, k reflect.Kind) bool {
+	from, fromSize := classOf(t), AtomicSize(t)
+	to, toSize := classOfKind(k)
+	if from == 0 || to == 0 {
+		return false
+	}
+	switch to {
+	case classSigned:
+		return (from == classSigned && fromSize <= toSize) || (from == classUnsigned && fromSize < toSize)
+	case classUnsigned:
+		return from == classUnsigned && fromSize <= toSize
+	default:
+		// A float holds integers of up to half its size exactly
+		return (from == classFloat && fromSize <= toSize) || (from != classFloat && 2*fromSize <= toSize)
+	}
+}
+
+// fits reports whether the number held by v is exactly representable as
+// data type t.
+func fits(t DataType, v reflect.Value) bool {
+	size := AtomicSize(t)
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		switch t {
+		case TypeREAL:
+			return math.IsNaN(f) || float64(float32(f)) == f
+		case TypeLREAL:
+			return true
+		}
+		return false
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := v.Int()
+		switch classOf(t) {
+		case classSigned:
+			bits := uint(8*size - 1)
+			return size == 8 || (i >= -1<<bits && i < 1<<bits)
+		case classUnsigned:
+			return i >= 0 && (size == 8 || i < 1<<uint(8*size))
+		default:
+			return exactFloat(t, float64(i)) && int64(float64(i)) == i
+		}
+
+	default:
+		u := v.Uint()
+		switch classOf(t) {
+		case classSigned:
+			return u < 1<<uint(8*size-1)
+		case classUnsigned:
+			return size == 8 || u < 1<<uint(8*size)
+		default:
+			return exactFloat(t, float64(u)) && uint64(float64(u)) == u
+		}
+	}
+}
+
+func exactFloat(t DataType, f float64) bool {
+	if t == TypeREAL {
+		return float64(float32(f)) == f
+	}
+	return true
+}
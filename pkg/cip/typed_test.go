@@ -0,0 +1,161 @@
+package cip
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func typeInfo(t DataType) []byte {
+	return []byte{byte(t), byte(t >> 8)}
+}
+
+func TestUnmarshalTag_Numbers(t *testing.T) {
+	dint := []byte{0xFE, 0xFF, 0xFF, 0xFF} // -2
+
+	var i32 int32
+	if err := UnmarshalTag(typeInfo(TypeDINT), dint, &i32); err != nil || i32 != -2 {
+		t.Errorf("DINT into int32 = %d, %v", i32, err)
+	}
+	var i64 int64
+	if err := UnmarshalTag(typeInfo(TypeDINT), dint, &i64); err != nil || i64 != -2 {
+		t.Errorf("DINT into int64 = %d, %v", i64, err)
+	}
+	var f64 float64
+	if err := UnmarshalTag(typeInfo(TypeDINT), dint, &f64); err != nil || f64 != -2 {
+		t.Errorf("DINT into float64 = %v, %v", f64, err)
+	}
+	var u64 uint64
+	if err := UnmarshalTag(typeInfo(TypeUINT), []byte{0x34, 0x12}, &u64); err != nil || u64 != 0x1234 {
+		t.Errorf("UINT into uint64 = %d, %v", u64, err)
+	}
+
+	// Conversions that could lose values
+	tests := []struct {
+		dataType DataType
+		v        any
+	}{
+		{TypeDINT, new(int16)},
+		{TypeDINT, new(uint32)},
+		{TypeDINT, new(float32)},
+		{TypeUDINT, new(int32)},
+		{TypeREAL, new(int64)},
+		{TypeLREAL, new(float32)},
+		{TypeBOOL, new(int8)},
+		{TypeDINT, new(bool)},
+		{TypeDINT, new(string)},
+	}
+	for _, tt := range tests {
+		err := UnmarshalTag(typeInfo(tt.dataType), make([]byte, 8), tt.v)
+		var mismatch *TypeMismatchError
+		if !errors.As(err, &mismatch) {
+			t.Errorf("%s into %T: error = %v, want *TypeMismatchError", tt.dataType, tt.v, err)
+		}
+		if !errors.Is(err, ErrTypeMismatch) {
+			t.Errorf("%s into %T: errors.Is(err, ErrTypeMismatch) = false", tt.dataType, tt.v)
+		}
+	}
+}
+
+func TestUnmarshalTag_Arrays(t *testing.T) {
+	data := []byte{1, 0, 2, 0, 3, 0}
+
+	var arr [3]int32
+	if err := UnmarshalTag(typeInfo(TypeINT), data, &arr); err != nil || arr != [3]int32{1, 2, 3} {
+		t.Errorf("INT[3] into [3]int32 = %v, %v", arr, err)
+	}
+	var s []float64
+	if err := UnmarshalTag(typeInfo(TypeINT), data, &s); err != nil || len(s) != 3 || s[2] != 3 {
+		t.Errorf("INT[3] into []float64 = %v, %v", s, err)
+	}
+
+	var bits [32]bool
+	if err := UnmarshalTag(typeInfo(TypeDWORD), []byte{0x05, 0, 0, 0}, &bits); err != nil || !bits[0] || bits[1] || !bits[2] {
+		t.Errorf("BOOL[32] = %v, %v", bits, err)
+	}
+}
+
+type motor struct {
+	Speed   float32
+	Running bool
+}
+
+type handledMotor motor
+
+func (handledMotor) StructHandle() uint16 { return 0xBEEF }
+
+func TestUnmarshalTag_Structs(t *testing.T) {
+	data := []byte{0, 0, 0x80, 0x3F, 1, 0, 0, 0} // 1.0, true
+	info := []byte{0xA0, 0x02, 0xEF, 0xBE}
+
+	var m motor
+	if err := UnmarshalTag(info, data, &m); err != nil || m.Speed != 1 || !m.Running {
+		t.Errorf("struct = %+v, %v", m, err)
+	}
+	if err := UnmarshalTag(info, data[:4], &m); err == nil {
+		t.Error("struct of another size: expected mismatch")
+	}
+
+	var hm handledMotor
+	if err := UnmarshalTag(info, data, &hm); err != nil || !hm.Running {
+		t.Errorf("struct with handle = %+v, %v", hm, err)
+	}
+	if err := UnmarshalTag([]byte{0xA0, 0x02, 0x34, 0x12}, data, &hm); err == nil {
+		t.Error("struct with another handle: expected mismatch")
+	}
+	if err := UnmarshalTag(typeInfo(TypeDINT), data, &m); err == nil {
+		t.Error("DINT into struct: expected mismatch")
+	}
+}
+
+func TestMarshalTag(t *testing.T) {
+	tests := []struct {
+		dataType DataType
+		v        any
+		want     []byte
+	}{
+		{TypeSINT, 5, []byte{5}},
+		{TypeINT, int64(-2), []byte{0xFE, 0xFF}},
+		{TypeUDINT, uint8(200), []byte{200, 0, 0, 0}},
+		{TypeREAL, 1.5, []byte{0, 0, 0xC0, 0x3F}},
+		{TypeREAL, int16(2), []byte{0, 0, 0, 0x40}},
+		{TypeDINT, []int{1, 2}, []byte{1, 0, 0, 0, 2, 0, 0, 0}},
+		{TypeBOOL, true, []byte{1}},
+		{TypeSTRING, "hi", []byte{2, 0, 'h', 'i'}},
+	}
+	for _, tt := range tests {
+		got, err := MarshalTag(typeInfo(tt.dataType), 0, tt.v)
+		if err != nil || !bytes.Equal(got, tt.want) {
+			t.Errorf("MarshalTag(%s, %v) = % X, %v, want % X", tt.dataType, tt.v, got, err, tt.want)
+		}
+	}
+
+	mismatches := []struct {
+		dataType DataType
+		v        any
+	}{
+		{TypeSINT, 300},
+		{TypeUINT, -1},
+		{TypeDINT, 1.5},
+		{TypeREAL, 0.1},
+		{TypeREAL, int32(1<<24 + 1)},
+		{TypeBOOL, 1},
+		{TypeDINT, "1"},
+		{TypeDINT, motor{}},
+	}
+	for _, tt := range mismatches {
+		_, err := MarshalTag(typeInfo(tt.dataType), 0, tt.v)
+		var mismatch *TypeMismatchError
+		if !errors.As(err, &mismatch) {
+			t.Errorf("MarshalTag(%s, %v) error = %v, want *TypeMismatchError", tt.dataType, tt.v, err)
+		}
+	}
+
+	info := []byte{0xA0, 0x02, 0xEF, 0xBE}
+	if _, err := MarshalTag(info, 8, motor{Speed: 1}); err != nil {
+		t.Errorf("MarshalTag(struct) error = %v", err)
+	}
+	if _, err := MarshalTag(info, 12, motor{}); err == nil {
+		t.Error("MarshalTag(struct of another size): expected mismatch")
+	}
+}
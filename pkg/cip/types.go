@@ -107,39 +107,6 @@ const (
 	TypeSTRUCT        DataType = 0x02A0 // Common struct type code
 )
 
-// General Status Codes
-const (
-	StatusSuccess                USINT = 0x00
-	StatusPathDestinationUnknown USINT = 0x05
-	StatusPartialTransfer        USINT = 0x06
-	StatusAttributeListShortage  USINT = 0x1C
-	StatusPathSegmentError       USINT = 0x04
-	StatusConnectionFailure      USINT = 0x01
-	StatusResourceUnavailable    USINT = 0x02
-	StatusInvalidSegmentType     USINT = 0x03 // or 0x04 depending on context
-	StatusServiceNotSupported    USINT = 0x08
-	StatusInvalidAttributeValue  USINT = 0x09
-	StatusAttributeNotSettable   USINT = 0x0E
-	StatusPrivilegeViolation     USINT = 0x10
-	StatusDeviceStateConflict    USINT = 0x11
-	StatusReplyDataTooLarge      USINT = 0x12
-	StatusNotEnoughData          USINT = 0x13
-	StatusAttributeNotSupported  USINT = 0x14
-	StatusTooMuchData            USINT = 0x15
-	StatusObjectDoesNotExist     USINT = 0x16
-	StatusServiceFragmentation   USINT = 0x2D
-)
-
-// Error represents a CIP error
-type Error struct {
-	Status    USINT
-	ExtStatus []UINT // Extended status is usually a list of words
-}
-
-func (e Error) Error() string {
-	return fmt.Sprintf("CIP Error: Status=0x%02X", e.Status)
-}
-
 // IsArray returns true if the array bit (0x8000) is set
 func (d DataType) IsArray() bool {
 	return (d & 0x8000) != 0
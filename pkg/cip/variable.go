@@ -0,0 +1,341 @@
+package cip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Omron NJ/NX controllers describe their published variables with their
+// own objects instead of the Logix Symbol and Template Objects: the Tag Name
+// Server lists the variables, the Variable Object holds the type of each
+// variable and the Variable Type Object the members of structure types.
+// The Variable Object and the Variable Type Object share their class IDs
+// with the Logix Symbol and Template Objects.
+const (
+	ClassTagNameServer UINT = 0x6A
+	ClassVariable      UINT = 0x6B
+	ClassVariableType  UINT = 0x6C
+)
+
+// ServiceGetInstanceListExtended lists the variables registered with the
+// Tag Name Server, from a starting instance on (Omron).
+const ServiceGetInstanceListExtended USINT = 0x5F
+
+// VariableKind selects the variables listed by
+// NewGetInstanceListExtendedRequest.
+type VariableKind uint16
+
+const (
+	// VariablesSystem are the controller's system-defined variables
+	// ("_CurrentTime", "_ErrSta", ...).
+	VariablesSystem VariableKind = 1
+	// VariablesUser are the published user-defined variables.
+	VariablesUser VariableKind = 2
+)
+
+// variableTypeArray is the data type code of an array variable; the element
+// type is reported separately.
+const variableTypeArray = 0xA3
+
+// NewGetInstanceListExtendedRequest creates a request for at most count
+// variables of a kind from instance start on.
+func NewGetInstanceListExtendedRequest(start, count uint32, kind VariableKind) *MessageRouterRequest {
+	p := NewPath()
+	p.AddClass(ClassTagNameServer)
+	p.AddInstance(0)
+
+	// Start Instance (UDINT) + Number of Instances (UDINT) + Kind (UINT)
+	data := make([]byte, 10)
+	binary.LittleEndian.PutUint32(data[0:4], start)
+	binary.LittleEndian.PutUint32(data[4:8], count)
+	binary.LittleEndian.PutUint16(data[8:10], uint16(kind))
+
+	return &MessageRouterRequest{
+		Service:     ServiceGetInstanceListExtended,
+		RequestPath: p,
+		RequestData: data,
+	}
+}
+
+// VariableEntry is one variable listed by the Tag Name Server.
+type VariableEntry struct {
+	InstanceID uint32 // Variable Object instance
+	Name       string
+}
+
+// DecodeGetInstanceListExtendedResponse decodes the reply to
+// NewGetInstanceListExtendedRequest: the number of entries (UDINT), then
+// for each the instance ID (UDINT) and the name (USINT length and
+// characters).
+func DecodeGetInstanceListExtendedResponse(data []byte) ([]VariableEntry, error) {
+	r := bytes.NewReader(data)
+	var count uint32
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		return nil, fmt.Errorf("instance list: %w", err)
+	}
+
+	entries := make([]VariableEntry, 0, min(count, uint32(len(data))/5))
+	for i := uint32(0); i < count; i++ {
+		var e VariableEntry
+		var nameLen uint8
+		if err := binary.Read(r, binary.LittleEndian, &e.InstanceID); err != nil {
+			return nil, fmt.Errorf("instance list: entry %d: %w", i, err)
+		}
+		if err := binary.Read(r, binary.LittleEndian, &nameLen); err != nil {
+			return nil, fmt.Errorf("instance list: instance %d: %w", e.InstanceID, err)
+		}
+		name := make([]byte, nameLen)
+		if _, err := io.ReadFull(r, name); err != nil {
+			return nil, fmt.Errorf("instance list: instance %d: name: %w", e.InstanceID, err)
+		}
+		e.Name = string(name)
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// VariableInfo is the type of an Omron variable or of a member of a
+// structure type, as returned by Get Attribute All on the Variable Object
+// or the Variable Type Object.
+type VariableInfo struct {
+	Size     uint32   // Size of the whole value in bytes
+	Type     DataType // Data type, the element type for arrays
+	Dims     int      // Number of array dimensions, 0 for scalars
+	Elements uint32   // Number of array elements
+	Start    uint32   // Subscript of the first array element
+
+	// Nesting is, for structure variables and members, the Variable Type
+	// instance of their structure type, and for a structure type the
+	// instance of its first member.
+	Nesting uint32
+	// Next is the instance of the next member of the enclosing structure
+	// type, zero after the last member and for variables.
+	Next uint32
+	// Handle is the CRC of a structure's layout, also returned by Read Tag.
+	Handle uint16
+	// Name is the name of a member or structure type; variables have
+	// their name in the Tag Name Server instead.
+	Name string
+}
+
+// variableInfoSize is the size of the fixed part of a Get Attribute All
+// reply of the Variable and Variable Type Objects.
+const variableInfoSize = 27
+
+// NewGetVariableRequest creates a Get Attribute All request for a Variable
+// Object instance.
+func NewGetVariableRequest(instanceID uint32) *MessageRouterRequest {
+	return newGetVariableInfoRequest(ClassVariable, instanceID)
+}
+
+// NewGetVariableTypeRequest creates a Get Attribute All request for a
+// Variable Type Object instance.
+func NewGetVariableTypeRequest(instanceID uint32) *MessageRouterRequest {
+	return newGetVariableInfoRequest(ClassVariableType, instanceID)
+}
+
+func newGetVariableInfoRequest(class UINT, instanceID uint32) *MessageRouterRequest {
+	p := NewPath()
+	p.AddClass(class)
+	p.AddInstance32(instanceID)
+	return &MessageRouterRequest{
+		Service:     ServiceGetAttributeAll,
+		RequestPath: p,
+	}
+}
+
+// DecodeVariableInfo decodes the reply to NewGetVariableRequest or
+// NewGetVariableTypeRequest:
+//
+//	Offset  Size  Field
+//	0       4     Size (UDINT)
+//	4       1     Data type (USINT), 0xA3 for arrays
+//	5       1     Array element data type (USINT)
+//	6       1     Array dimensions (USINT)
+//	7       1     Padding
+//	8       4     Number of elements (UDINT)
+//	12      4     First subscript (UDINT)
+//	16      4     Nesting Variable Type instance (UDINT)
+//	20      4     Next member instance (UDINT)
+//	24      2     Structure CRC (UINT)
+//	26      1     Name length (USINT), followed by the name
+func DecodeVariableInfo(data []byte) (VariableInfo, error) {
+	if len(data) < variableInfoSize {
+		return VariableInfo{}, fmt.Errorf("variable info: need %d bytes, got %d", variableInfoSize, len(data))
+	}
+
+	v := VariableInfo{
+		Size:     binary.LittleEndian.Uint32(data[0:4]),
+		Type:     variableDataType(data[4]),
+		Dims:     int(data[6]),
+		Elements: binary.LittleEndian.Uint32(data[8:12]),
+		Start:    binary.LittleEndian.Uint32(data[12:16]),
+		Nesting:  binary.LittleEndian.Uint32(data[16:20]),
+		Next:     binary.LittleEndian.Uint32(data[20:24]),
+		Handle:   binary.LittleEndian.Uint16(data[24:26]),
+	}
+	if data[4] == variableTypeArray {
+		v.Type = variableDataType(data[5])
+	}
+
+	nameLen := int(data[26])
+	if variableInfoSize+nameLen > len(data) {
+		return VariableInfo{}, fmt.Errorf("variable info: name of %d bytes exceeds data", nameLen)
+	}
+	v.Name = string(data[variableInfoSize : variableInfoSize+nameLen])
+	return v, nil
+}
+
+// variableDataType converts a one-byte data type code of the Variable
+// Objects. Structures are 0xA0, the first byte of TypeSTRUCT.
+func variableDataType(code byte) DataType {
+	if code == byte(TypeSTRUCT&0xFF) {
+		return TypeSTRUCT
+	}
+	return DataType(code)
+}
+
+// IsStructure reports whether the variable or member is a structure.
+func (v VariableInfo) IsStructure() bool {
+	return v.Type == TypeSTRUCT
+}
+
+// IsArray reports whether the variable or member is an array.
+func (v VariableInfo) IsArray() bool {
+	return v.Dims > 0
+}
+
+// Symbol converts a variable listed by the Tag Name Server into a
+// SymbolInstance, so Omron variables are listed like Logix tags. The
+// symbol of a structure variable carries its Variable Type instance as
+// template ID; Dims[0] holds the total number of elements of arrays.
+func (v VariableInfo) Symbol(e VariableEntry) (SymbolInstance, error) {
+	s := SymbolInstance{InstanceID: e.InstanceID, Name: e.Name}
+
+	typeWord := uint16(v.Type) & 0x00FF
+	if v.IsStructure() {
+		if v.Nesting > symbolTypeIDMask {
+			return SymbolInstance{}, fmt.Errorf("variable %s: type instance %d does not fit a symbol type", e.Name, v.Nesting)
+		}
+		typeWord = symbolTypeStruct | uint16(v.Nesting)
+	}
+	if v.IsArray() {
+		typeWord |= uint16(min(v.Dims, 3)) << symbolTypeDimsShift
+		s.Dims[0] = v.Elements
+	}
+	s.Type = DataType(typeWord)
+	return s, nil
+}
+
+// VariableType is an Omron structure type with its members, read from the
+// Variable Type Object.
+type VariableType struct {
+	InstanceID uint32
+	Name       string
+	Handle     uint16 // CRC of the layout, also returned by Read Tag
+	Size       uint32 // In bytes
+	Members    []VariableInfo
+}
+
+// DecodeMember decodes the Read Tag reply of member m (type information
+// followed by the value). Atomic members decode to their native Go type,
+// STRING members to string and arrays to []any. Structure members are read
+// through their own members instead.
+func DecodeMember(m VariableInfo, data []byte) (any, error) {
+	typeInfo, value, err := SplitReadTagResponse(data)
+	if err != nil {
+		return nil, err
+	}
+	if m.IsStructure() {
+		return nil, fmt.Errorf("cip: %s is a structure", m.Name)
+	}
+	if got, _ := SplitTypeInfo(typeInfo); got != m.Type {
+		return nil, fmt.Errorf("cip: %s: reply is %s, want %s", m.Name, got, m.Type)
+	}
+
+	if m.Type == TypeSTRING {
+		return DecodeString(m.Type, value)
+	}
+	if !m.IsArray() {
+		return DecodeAtomic(m.Type, value)
+	}
+
+	size := AtomicSize(m.Type)
+	if size == 0 {
+		return nil, fmt.Errorf("cip: unsupported array element type %s", m.Type)
+	}
+	if len(value) < int(m.Elements)*size {
+		return nil, fmt.Errorf("cip: array of %d %s needs %d bytes, got %d", m.Elements, m.Type, int(m.Elements)*size, len(value))
+	}
+	values := make([]any, m.Elements)
+	for i := range values {
+		values[i], err = DecodeAtomic(m.Type, value[i*size:])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// EncodeMember encodes v as the value of member m for a Write Tag request
+// and returns the number of elements written. Atomic members take any Go
+// number or a bool for BOOL, STRING members a string and arrays a slice of
+// at most m.Elements elements, written from the first element on.
+func EncodeMember(m VariableInfo, v any) ([]byte, uint16, error) {
+	if m.IsStructure() {
+		return nil, 0, fmt.Errorf("cip: %s is a structure", m.Name)
+	}
+	if m.Type == TypeSTRING {
+		s, ok := v.(string)
+		if !ok {
+			return nil, 0, fmt.Errorf("cip: %s: %T is not a string", m.Name, v)
+		}
+		data, err := EncodeString(m.Type, s)
+		return data, 1, err
+	}
+
+	size := AtomicSize(m.Type)
+	if size == 0 {
+		return nil, 0, fmt.Errorf("cip: %s: unsupported type %s", m.Name, m.Type)
+	}
+	encodeOne := func(b []byte, v any) error {
+		if bv, ok := v.(bool); ok && m.Type == TypeBOOL {
+			if bv {
+				b[0] = 1
+			}
+			return nil
+		}
+		rv := reflect.ValueOf(v)
+		if !isNumber(rv.Kind()) {
+			return fmt.Errorf("%T is not a number", v)
+		}
+		encodeAtomic(m.Type, b, rv)
+		return nil
+	}
+
+	if !m.IsArray() {
+		data := make([]byte, size)
+		if err := encodeOne(data, v); err != nil {
+			return nil, 0, fmt.Errorf("cip: %s: %w", m.Name, err)
+		}
+		return data, 1, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, 0, fmt.Errorf("cip: %s: %T is not a slice", m.Name, v)
+	}
+	if rv.Len() > int(m.Elements) {
+		return nil, 0, fmt.Errorf("cip: %s: %d elements do not fit an array of %d", m.Name, rv.Len(), m.Elements)
+	}
+	data := make([]byte, rv.Len()*size)
+	for i := 0; i < rv.Len(); i++ {
+		if err := encodeOne(data[i*size:], rv.Index(i).Interface()); err != nil {
+			return nil, 0, fmt.Errorf("cip: %s: element %d: %w", m.Name, i, err)
+		}
+	}
+	return data, uint16(rv.Len()), nil
+}
@@ -0,0 +1,145 @@
+package cip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+// encodeVariableInfo encodes a Get Attribute All reply of the Variable or
+// Variable Type Object. Arrays are reported with type code 0xA3.
+func encodeVariableInfo(v VariableInfo) []byte {
+	b := binary.LittleEndian.AppendUint32(nil, v.Size)
+	if v.Dims > 0 {
+		b = append(b, variableTypeArray, byte(v.Type))
+	} else {
+		b = append(b, byte(v.Type), 0)
+	}
+	b = append(b, byte(v.Dims), 0)
+	b = binary.LittleEndian.AppendUint32(b, v.Elements)
+	b = binary.LittleEndian.AppendUint32(b, v.Start)
+	b = binary.LittleEndian.AppendUint32(b, v.Nesting)
+	b = binary.LittleEndian.AppendUint32(b, v.Next)
+	b = binary.LittleEndian.AppendUint16(b, v.Handle)
+	b = append(b, byte(len(v.Name)))
+	return append(b, v.Name...)
+}
+
+func TestNewGetInstanceListExtendedRequest(t *testing.T) {
+	req := NewGetInstanceListExtendedRequest(0x101, 100, VariablesUser)
+	if req.Service != ServiceGetInstanceListExtended {
+		t.Errorf("Service = 0x%02X, want 0x5F", req.Service)
+	}
+	if want := []byte{0x20, 0x6A, 0x24, 0x00}; !bytes.Equal(req.RequestPath, want) {
+		t.Errorf("RequestPath = % X, want % X", []byte(req.RequestPath), want)
+	}
+	if want := []byte{0x01, 0x01, 0, 0, 0x64, 0, 0, 0, 0x02, 0x00}; !bytes.Equal(req.RequestData, want) {
+		t.Errorf("RequestData = % X, want % X", req.RequestData, want)
+	}
+}
+
+func TestDecodeGetInstanceListExtendedResponse(t *testing.T) {
+	data := []byte{0x02, 0, 0, 0}
+	data = append(data, 0x05, 0, 0, 0, 0x05)
+	data = append(data, "Speed"...)
+	data = append(data, 0x09, 0, 0, 0, 0x03)
+	data = append(data, "Pos"...)
+
+	entries, err := DecodeGetInstanceListExtendedResponse(data)
+	if err != nil {
+		t.Fatalf("DecodeGetInstanceListExtendedResponse() error = %v", err)
+	}
+	want := []VariableEntry{{5, "Speed"}, {9, "Pos"}}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("entries = %+v, want %+v", entries, want)
+	}
+
+	if _, err := DecodeGetInstanceListExtendedResponse(data[:len(data)-1]); err == nil {
+		t.Error("expected error for truncated name")
+	}
+}
+
+func TestDecodeVariableInfo(t *testing.T) {
+	tests := []struct {
+		name string
+		info VariableInfo
+	}{
+		{"scalar", VariableInfo{Size: 4, Type: TypeDINT}},
+		{"array", VariableInfo{Size: 40, Type: TypeREAL, Dims: 1, Elements: 10, Start: 1}},
+		{"structure", VariableInfo{Size: 12, Type: TypeSTRUCT, Nesting: 3, Handle: 0xBEEF}},
+		{"member", VariableInfo{Size: 2, Type: TypeINT, Next: 8, Name: "Speed"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeVariableInfo(encodeVariableInfo(tt.info))
+			if err != nil {
+				t.Fatalf("DecodeVariableInfo() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.info) {
+				t.Errorf("DecodeVariableInfo() = %+v, want %+v", got, tt.info)
+			}
+		})
+	}
+
+	if _, err := DecodeVariableInfo(make([]byte, variableInfoSize-1)); err == nil {
+		t.Error("expected error for short reply")
+	}
+}
+
+func TestVariableInfo_Symbol(t *testing.T) {
+	s, err := VariableInfo{Type: TypeSTRUCT, Dims: 1, Elements: 4, Nesting: 0x12}.Symbol(VariableEntry{7, "Axes"})
+	if err != nil {
+		t.Fatalf("Symbol() error = %v", err)
+	}
+	if !s.IsStructure() || s.TemplateID() != 0x12 || s.Dimensions() != 1 || s.Dims[0] != 4 || s.InstanceID != 7 {
+		t.Errorf("Symbol() = %+v", s)
+	}
+
+	s, err = VariableInfo{Type: TypeLREAL}.Symbol(VariableEntry{8, "Setpoint"})
+	if err != nil || s.IsStructure() || s.Type != TypeLREAL {
+		t.Errorf("Symbol() = %+v, %v", s, err)
+	}
+
+	if _, err := (VariableInfo{Type: TypeSTRUCT, Nesting: 0x1000}).Symbol(VariableEntry{9, "Big"}); err == nil {
+		t.Error("expected error for a type instance beyond the template ID range")
+	}
+}
+
+func TestDecodeEncodeMember(t *testing.T) {
+	scalar := VariableInfo{Name: "Speed", Type: TypeINT}
+	v, err := DecodeMember(scalar, []byte{0xC3, 0x00, 0xDC, 0x05})
+	if err != nil || v != int16(1500) {
+		t.Errorf("DecodeMember(INT) = %v, %v", v, err)
+	}
+	if _, err := DecodeMember(scalar, []byte{0xC4, 0x00, 0xDC, 0x05, 0, 0}); err == nil {
+		t.Error("expected error for a reply of another type")
+	}
+	data, n, err := EncodeMember(scalar, 1500)
+	if err != nil || n != 1 || !bytes.Equal(data, []byte{0xDC, 0x05}) {
+		t.Errorf("EncodeMember(INT) = % X, %d, %v", data, n, err)
+	}
+
+	array := VariableInfo{Name: "Limits", Type: TypeDINT, Dims: 1, Elements: 2}
+	v, err = DecodeMember(array, []byte{0xC4, 0x00, 1, 0, 0, 0, 2, 0, 0, 0})
+	if err != nil || !reflect.DeepEqual(v, []any{int32(1), int32(2)}) {
+		t.Errorf("DecodeMember(DINT[2]) = %v, %v", v, err)
+	}
+	data, n, err = EncodeMember(array, []int32{3, 4})
+	if err != nil || n != 2 || !bytes.Equal(data, []byte{3, 0, 0, 0, 4, 0, 0, 0}) {
+		t.Errorf("EncodeMember(DINT[2]) = % X, %d, %v", data, n, err)
+	}
+	if _, _, err := EncodeMember(array, []int32{1, 2, 3}); err == nil {
+		t.Error("expected error for too many elements")
+	}
+
+	str := VariableInfo{Name: "Label", Type: TypeSTRING}
+	v, err = DecodeMember(str, []byte{0xD0, 0x00, 0x02, 0x00, 'h', 'i'})
+	if err != nil || v != "hi" {
+		t.Errorf("DecodeMember(STRING) = %v, %v", v, err)
+	}
+	data, _, err = EncodeMember(VariableInfo{Name: "On", Type: TypeBOOL}, true)
+	if err != nil || !bytes.Equal(data, []byte{1}) {
+		t.Errorf("EncodeMember(BOOL) = % X, %v", data, err)
+	}
+}
@@ -0,0 +1,131 @@
+package cip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ClassWallClockTime is the Logix WallClockTime object, the controller's
+// real-time clock. Instance 1 is the controller clock.
+const ClassWallClockTime UINT = 0x8B
+
+// WallClockTime attributes
+const (
+	WallClockAttrCurrentValue  UINT = 6  // LINT: microseconds since 1970-01-01 UTC
+	WallClockAttrTimeZone      UINT = 9  // INT: offset of local time from UTC, minutes
+	WallClockAttrDSTAdjustment UINT = 10 // INT: daylight saving time adjustment, minutes
+	WallClockAttrApplyDST      UINT = 12 // SINT: DSTAdjustment is applied when non-zero
+)
+
+// wallClockAttributes are the attributes read by NewGetWallClockRequest.
+var wallClockAttributes = []UINT{
+	WallClockAttrCurrentValue,
+	WallClockAttrTimeZone,
+	WallClockAttrDSTAdjustment,
+	WallClockAttrApplyDST,
+}
+
+// WallClock is the state of a controller clock.
+type WallClock struct {
+	UTC           time.Time     // Current time
+	TimeZone      time.Duration // Offset of local time from UTC
+	DSTAdjustment time.Duration // Daylight saving time adjustment
+	ApplyDST      bool          // DSTAdjustment is in effect
+}
+
+// Local returns the controller's local time, including the time zone and
+// any daylight saving time adjustment.
+func (w *WallClock) Local() time.Time {
+	offset := w.TimeZone
+	if w.ApplyDST {
+		offset += w.DSTAdjustment
+	}
+	return w.UTC.In(time.FixedZone("", int(offset/time.Second)))
+}
+
+// NewGetWallClockRequest creates a Get Attribute List request for the
+// controller clock, its time zone and daylight saving time settings.
+func NewGetWallClockRequest() *MessageRouterRequest {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, uint16(len(wallClockAttributes)))
+	for _, attr := range wallClockAttributes {
+		binary.Write(buf, binary.LittleEndian, attr)
+	}
+
+	return &MessageRouterRequest{
+		Service:     ServiceGetAttributeList,
+		RequestPath: BuildPath(ClassWallClockTime, 1, 0),
+		RequestData: buf.Bytes(),
+	}
+}
+
+// DecodeWallClockResponse decodes the reply to NewGetWallClockRequest.
+// The current value is required; controllers without time zone or
+// daylight saving time attributes leave those fields zero.
+func DecodeWallClockResponse(data []byte) (*WallClock, error) {
+	r := bytes.NewReader(data)
+	var count uint16
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		return nil, fmt.Errorf("wall clock: %w", err)
+	}
+
+	w := &WallClock{}
+	haveTime := false
+	for i := 0; i < int(count); i++ {
+		var hdr struct{ ID, Status uint16 }
+		if err := binary.Read(r, binary.LittleEndian, &hdr); err != nil {
+			return nil, fmt.Errorf("wall clock: %w", err)
+		}
+		if hdr.Status != 0 {
+			if UINT(hdr.ID) == WallClockAttrCurrentValue {
+				return nil, fmt.Errorf("wall clock: current value: %w", Error{Status: USINT(hdr.Status)})
+			}
+			continue
+		}
+
+		var err error
+		switch UINT(hdr.ID) {
+		case WallClockAttrCurrentValue:
+			value := make([]byte, 8)
+			if _, err = io.ReadFull(r, value); err == nil {
+				w.UTC, err = DecodeTime(TypeLINT, value)
+				haveTime = true
+			}
+		case WallClockAttrTimeZone:
+			var minutes int16
+			err = binary.Read(r, binary.LittleEndian, &minutes)
+			w.TimeZone = time.Duration(minutes) * time.Minute
+		case WallClockAttrDSTAdjustment:
+			var minutes int16
+			err = binary.Read(r, binary.LittleEndian, &minutes)
+			w.DSTAdjustment = time.Duration(minutes) * time.Minute
+		case WallClockAttrApplyDST:
+			var apply int8
+			err = binary.Read(r, binary.LittleEndian, &apply)
+			w.ApplyDST = apply != 0
+		default:
+			return nil, fmt.Errorf("wall clock: unexpected attribute %d", hdr.ID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("wall clock: attribute %d: %w", hdr.ID, err)
+		}
+	}
+
+	if !haveTime {
+		return nil, fmt.Errorf("wall clock: reply has no current value")
+	}
+	return w, nil
+}
+
+// NewSetWallClockRequest creates a Set Attribute Single request setting
+// the controller clock to t.
+func NewSetWallClockRequest(t time.Time) (*MessageRouterRequest, error) {
+	value, err := EncodeTime(TypeLINT, t)
+	if err != nil {
+		return nil, err
+	}
+	return NewSetAttributeSingleRequest(BuildPath(ClassWallClockTime, 1, WallClockAttrCurrentValue), value), nil
+}
@@ -0,0 +1,90 @@
+package cip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewGetWallClockRequest(t *testing.T) {
+	req := NewGetWallClockRequest()
+	if req.Service != ServiceGetAttributeList {
+		t.Errorf("Service = 0x%02X, want 0x03", req.Service)
+	}
+	if want := BuildPath(ClassWallClockTime, 1, 0); !bytes.Equal(req.RequestPath, want) {
+		t.Errorf("RequestPath = % X, want % X", req.RequestPath, want)
+	}
+	want := []byte{4, 0, 6, 0, 9, 0, 10, 0, 12, 0}
+	if !bytes.Equal(req.RequestData, want) {
+		t.Errorf("RequestData = % X, want % X", req.RequestData, want)
+	}
+}
+
+func wallClockReply(stamp time.Time, tzStatus uint16) []byte {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, uint16(4))
+	binary.Write(buf, binary.LittleEndian, []uint16{6, 0})
+	binary.Write(buf, binary.LittleEndian, stamp.UnixMicro())
+	binary.Write(buf, binary.LittleEndian, []uint16{9, tzStatus})
+	if tzStatus == 0 {
+		binary.Write(buf, binary.LittleEndian, int16(-300))
+	}
+	binary.Write(buf, binary.LittleEndian, []uint16{10, 0})
+	binary.Write(buf, binary.LittleEndian, int16(60))
+	binary.Write(buf, binary.LittleEndian, []uint16{12, 0})
+	buf.WriteByte(1)
+	return buf.Bytes()
+}
+
+func TestDecodeWallClockResponse(t *testing.T) {
+	stamp := time.Date(2024, 7, 1, 16, 30, 0, 123000, time.UTC)
+
+	w, err := DecodeWallClockResponse(wallClockReply(stamp, 0))
+	if err != nil {
+		t.Fatalf("DecodeWallClockResponse failed: %v", err)
+	}
+	if !w.UTC.Equal(stamp) || w.TimeZone != -5*time.Hour || w.DSTAdjustment != time.Hour || !w.ApplyDST {
+		t.Errorf("got %+v", w)
+	}
+	local := w.Local()
+	if local.Hour() != 12 || !local.Equal(stamp) {
+		t.Errorf("Local() = %v, want 12:30 at UTC-4", local)
+	}
+
+	// A missing time zone attribute is left zero
+	w, err = DecodeWallClockResponse(wallClockReply(stamp, uint16(StatusAttributeNotSupported)))
+	if err != nil {
+		t.Fatalf("DecodeWallClockResponse failed: %v", err)
+	}
+	if w.TimeZone != 0 || w.DSTAdjustment != time.Hour {
+		t.Errorf("got %+v", w)
+	}
+
+	// The current value is required
+	data := []byte{1, 0, 6, 0, byte(StatusPrivilegeViolation), 0}
+	if _, err := DecodeWallClockResponse(data); !errors.Is(err, ErrPrivilegeViolation) {
+		t.Errorf("error = %v, want privilege violation", err)
+	}
+	if _, err := DecodeWallClockResponse(wallClockReply(stamp, 0)[:8]); err == nil {
+		t.Error("expected error for short reply")
+	}
+}
+
+func TestNewSetWallClockRequest(t *testing.T) {
+	stamp := time.Date(2024, 7, 1, 16, 30, 0, 0, time.UTC)
+	req, err := NewSetWallClockRequest(stamp)
+	if err != nil {
+		t.Fatalf("NewSetWallClockRequest failed: %v", err)
+	}
+	if req.Service != ServiceSetAttributeSingle {
+		t.Errorf("Service = 0x%02X, want 0x10", req.Service)
+	}
+	if want := BuildPath(ClassWallClockTime, 1, WallClockAttrCurrentValue); !bytes.Equal(req.RequestPath, want) {
+		t.Errorf("RequestPath = % X, want % X", req.RequestPath, want)
+	}
+	if got := int64(binary.LittleEndian.Uint64(req.RequestData)); got != stamp.UnixMicro() {
+		t.Errorf("RequestData = %d, want %d", got, stamp.UnixMicro())
+	}
+}
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+func TestClient_ReadTagArray(t *testing.T) {
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		// Symbolic "Data" followed by element 300
+		wantPath := []byte{0x91, 0x04, 'D', 'a', 't', 'a', 0x29, 0x00, 0x2C, 0x01}
+		if !bytes.Equal(req.RequestPath, wantPath) {
+			t.Errorf("path = %X, want %X", []byte(req.RequestPath), wantPath)
+		}
+		if n := binary.LittleEndian.Uint16(req.RequestData); n != 3 {
+			t.Errorf("elements = %d, want 3", n)
+		}
+		return &cip.MessageRouterResponse{
+			ResponseData: []byte{0xC4, 0x00, 1, 0, 0, 0, 2, 0, 0, 0, 3, 0, 0, 0},
+		}
+	})
+
+	data, err := c.ReadTagArray("Data", 300, 3)
+	if err != nil {
+		t.Fatalf("ReadTagArray() error = %v", err)
+	}
+
+	vals := make([]int32, 3)
+	if err := cip.Unmarshal(data[2:], &vals); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if vals[0] != 1 || vals[1] != 2 || vals[2] != 3 {
+		t.Errorf("values = %v, want [1 2 3]", vals)
+	}
+}
+
+func TestClient_WriteTag_Slice(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		wantType cip.DataType
+		wantN    uint16
+		wantData []byte
+	}{
+		{
+			name:     "[]int32",
+			value:    []int32{1, 2},
+			wantType: cip.TypeDINT,
+			wantN:    2,
+			wantData: []byte{1, 0, 0, 0, 2, 0, 0, 0},
+		},
+		{
+			name:     "[]float32",
+			value:    []float32{1.0},
+			wantType: cip.TypeREAL,
+			wantN:    1,
+			wantData: []byte{0x00, 0x00, 0x80, 0x3F},
+		},
+		{
+			name:     "[]bool",
+			value:    append(make([]bool, 31), true),
+			wantType: cip.TypeDWORD,
+			wantN:    1,
+			wantData: []byte{0x00, 0x00, 0x00, 0x80},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+				if req.Service != cip.ServiceWriteTag {
+					t.Errorf("service = 0x%02X, want 0x4D", req.Service)
+				}
+				if dt := cip.DataType(binary.LittleEndian.Uint16(req.RequestData[0:2])); dt != tt.wantType {
+					t.Errorf("type = %s, want %s", dt, tt.wantType)
+				}
+				if n := binary.LittleEndian.Uint16(req.RequestData[2:4]); n != tt.wantN {
+					t.Errorf("elements = %d, want %d", n, tt.wantN)
+				}
+				if !bytes.Equal(req.RequestData[4:], tt.wantData) {
+					t.Errorf("data = %X, want %X", req.RequestData[4:], tt.wantData)
+				}
+				return &cip.MessageRouterResponse{}
+			})
+
+			if err := c.WriteTag("Buf", tt.value); err != nil {
+				t.Fatalf("WriteTag() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestClient_WriteTag_LargeSliceUsesFragments(t *testing.T) {
+	value := make([]float32, 2000)
+	for i := range value {
+		value[i] = float32(i)
+	}
+
+	var written []byte
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		if req.Service != cip.ServiceWriteTagFragmented {
+			t.Errorf("service = 0x%02X, want 0x53", req.Service)
+			return &cip.MessageRouterResponse{GeneralStatus: cip.StatusServiceNotSupported}
+		}
+		if n := binary.LittleEndian.Uint16(req.RequestData[2:4]); n != 2000 {
+			t.Errorf("elements = %d, want 2000", n)
+		}
+		written = append(written, req.RequestData[8:]...)
+		return &cip.MessageRouterResponse{}
+	})
+
+	if err := c.WriteTag("Recipe", value); err != nil {
+		t.Fatalf("WriteTag() error = %v", err)
+	}
+
+	want, _ := cip.Marshal(value)
+	if !bytes.Equal(written, want) {
+		t.Errorf("written %d bytes, want %d", len(written), len(want))
+	}
+}
@@ -0,0 +1,293 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// TagResult is the outcome of reading one tag in a batch.
+type TagResult struct {
+	Name string
+	// Data has the same layout as the result of ReadTag.
+	Data []byte
+	Err  error
+}
+
+// ReadTags reads several tags using Multiple Service Packet requests (0x0A),
+// packing as many reads into each packet as the packet size allows.
+// Results are returned in the order of names. Per-tag failures are reported
+// in TagResult.Err; the returned error is only set when the transport fails.
+func (c *Client) ReadTags(names ...string) ([]TagResult, error) {
+	return c.ReadTagsContext(context.Background(), names...)
+}
+
+// ReadTagsContext is like ReadTags but gives up when ctx is done.
+func (c *Client) ReadTagsContext(ctx context.Context, names ...string) ([]TagResult, error) {
+	results := make([]TagResult, len(names))
+	var reqs []*cip.MessageRouterRequest
+	var index []int
+	var tagPaths []*cip.TagPath
+
+	for i, name := range names {
+		results[i].Name = name
+		tp, err := cip.ParseTagPath(name)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		reqs = append(reqs, cip.NewReadTagRequest(c.tagPath(ctx, tp), 1))
+		index = append(index, i)
+		tagPaths = append(tagPaths, tp)
+	}
+
+	resps, errs, err := c.sendBatch(ctx, reqs)
+	if err != nil {
+		return nil, err
+	}
+
+	for j, resp := range resps {
+		i := index[j]
+		tp := tagPaths[j]
+		if errs[j] != nil {
+			results[i].Err = errs[j]
+			continue
+		}
+
+		data := resp.ResponseData
+		if resp.GeneralStatus == cip.StatusPartialTransfer {
+			// Too large for one reply, fetch it in fragments
+			data, err = c.readFragmented(ctx, c.tagPath(ctx, tp), 1)
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+		} else if err := resp.Error(); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		if tp.HasBit() {
+			data, err = extractBit(data, tp.Bit)
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+		}
+		results[i].Data = data
+	}
+
+	return results, nil
+}
+
+// WriteTags writes several tags using Multiple Service Packet requests (0x0A).
+// Values follow the same rules as WriteTag. The returned map holds an entry
+// for every tag that failed; the returned error is only set when the
+// transport fails.
+func (c *Client) WriteTags(values map[string]any) (map[string]error, error) {
+	return c.WriteTagsContext(context.Background(), values)
+}
+
+// WriteTagsContext is like WriteTags but gives up when ctx is done.
+func (c *Client) WriteTagsContext(ctx context.Context, values map[string]any) (map[string]error, error) {
+	failures := make(map[string]error)
+
+	// Sort for a deterministic packing order
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var reqs []*cip.MessageRouterRequest
+	var batched []string
+
+	for _, name := range names {
+		// Bit writes cannot share a packet, they need the tag's type first
+		if b, ok := values[name].(bool); ok {
+			tp, err := cip.ParseTagPath(name)
+			if err != nil {
+				failures[name] = err
+				continue
+			}
+			if handled, err := c.writeBool(ctx, tp, b); handled {
+				if err != nil {
+					failures[name] = err
+				}
+				continue
+			}
+		}
+
+		w, err := c.newTagWrite(ctx, name, values[name])
+		if err != nil {
+			failures[name] = err
+			continue
+		}
+
+		// Writes too large to share a packet go through the fragmented path
+		req := w.request()
+		if 2+len(req.RequestPath)+len(req.RequestData) > c.packetSize()-multipleServiceReserve {
+			if err := c.writeFragmented(ctx, w); err != nil {
+				failures[name] = err
+			}
+			continue
+		}
+
+		reqs = append(reqs, req)
+		batched = append(batched, name)
+	}
+
+	resps, errs, err := c.sendBatch(ctx, reqs)
+	if err != nil {
+		return nil, err
+	}
+
+	for j, resp := range resps {
+		name := batched[j]
+		if errs[j] != nil {
+			failures[name] = errs[j]
+			continue
+		}
+		if err := resp.Error(); err != nil {
+			failures[name] = err
+		}
+	}
+
+	return failures, nil
+}
+
+// multipleServiceReserve is the room a single request needs inside a packet
+// besides its own encoding: the Multiple Service Packet header and one offset.
+const multipleServiceReserve = cip.MultipleServiceOverhead + 2
+
+// sendBatch sends reqs packed into as few Multiple Service Packets as fit the
+// packet size, or one by one if the controller has no Multiple Service
+// Packet service. It returns one reply per request; when a whole packet
+// fails, the packet's error is reported for each of its requests instead.
+func (c *Client) sendBatch(ctx context.Context, reqs []*cip.MessageRouterRequest) ([]*cip.MessageRouterResponse, []error, error) {
+	resps := make([]*cip.MessageRouterResponse, len(reqs))
+	errs := make([]error, len(reqs))
+	packed := c.has(FeatureMultipleService)
+
+	start := 0
+	for start < len(reqs) {
+		size := cip.MultipleServiceOverhead
+		end := start
+		for end < len(reqs) {
+			enc, err := reqs[end].Encode()
+			if err != nil {
+				return nil, nil, err
+			}
+			if end > start && (!packed || size+2+len(enc) > c.packetSize()) {
+				break
+			}
+			size += 2 + len(enc)
+			end++
+		}
+
+		if err := c.sendGroup(ctx, reqs[start:end], resps[start:end], errs[start:end]); err != nil {
+			return nil, nil, err
+		}
+		start = end
+	}
+
+	return resps, errs, nil
+}
+
+// sendGroup sends one packet of requests. Packets are sized by their
+// requests, but the replies can be larger than the requests: the target then
+// fails the whole packet, or the members that did not fit, with Reply Data
+// Too Large (0x11) or Partial Transfer (0x06). Those requests are sent again
+// in halves until each goes on its own, where a partial transfer is left for
+// the caller to handle.
+func (c *Client) sendGroup(ctx context.Context, reqs []*cip.MessageRouterRequest, resps []*cip.MessageRouterResponse, errs []error) error {
+	if err := c.sendPacket(ctx, reqs, resps, errs); err != nil {
+		return err
+	}
+	if len(reqs) == 1 {
+		return nil
+	}
+
+	var retry []int
+	for i := range reqs {
+		if replyOverflowed(resps[i], errs[i]) {
+			retry = append(retry, i)
+		}
+	}
+
+	half := (len(retry) + 1) / 2
+	for _, part := range [][]int{retry[:half], retry[half:]} {
+		if len(part) == 0 {
+			continue
+		}
+		subReqs := make([]*cip.MessageRouterRequest, len(part))
+		for j, i := range part {
+			subReqs[j] = reqs[i]
+		}
+		subResps := make([]*cip.MessageRouterResponse, len(part))
+		subErrs := make([]error, len(part))
+		if err := c.sendGroup(ctx, subReqs, subResps, subErrs); err != nil {
+			return err
+		}
+		for j, i := range part {
+			resps[i], errs[i] = subResps[j], subErrs[j]
+		}
+	}
+	return nil
+}
+
+// replyOverflowed reports whether a request in a Multiple Service Packet
+// failed only because its reply did not fit the reply packet.
+func replyOverflowed(resp *cip.MessageRouterResponse, err error) bool {
+	if err != nil {
+		return errors.Is(err, cip.ErrReplyDataTooLarge)
+	}
+	return resp.GeneralStatus == cip.StatusReplyDataTooLarge || resp.GeneralStatus == cip.StatusPartialTransfer
+}
+
+// sendPacket sends one group of requests, unwrapped when there is only one.
+func (c *Client) sendPacket(ctx context.Context, reqs []*cip.MessageRouterRequest, resps []*cip.MessageRouterResponse, errs []error) error {
+	if len(reqs) == 1 {
+		resp, err := c.send(ctx, reqs[0])
+		if err != nil {
+			return err
+		}
+		resps[0] = resp
+		return nil
+	}
+
+	msp, err := cip.NewMultipleServiceRequest(reqs)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.send(ctx, msp)
+	if err != nil {
+		return err
+	}
+
+	// Embedded Service Error means at least one service failed; the
+	// individual replies still follow.
+	if resp.GeneralStatus != cip.StatusSuccess && resp.GeneralStatus != cip.StatusEmbeddedServiceError {
+		for i := range errs {
+			errs[i] = resp.Error()
+		}
+		return nil
+	}
+
+	replies, err := cip.DecodeMultipleServiceResponse(resp.ResponseData)
+	if err != nil {
+		return err
+	}
+
+	for i := range reqs {
+		if i >= len(replies) {
+			errs[i] = cip.Error{Status: cip.StatusNotEnoughData}
+			continue
+		}
+		resps[i] = replies[i]
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// decodeMSPRequest splits the embedded requests of a Multiple Service Packet.
+func decodeMSPRequest(t *testing.T, data []byte) []*cip.MessageRouterRequest {
+	t.Helper()
+	count := int(binary.LittleEndian.Uint16(data))
+	reqs := make([]*cip.MessageRouterRequest, count)
+	for i := range reqs {
+		start := int(binary.LittleEndian.Uint16(data[2+2*i:]))
+		end := len(data)
+		if i+1 < count {
+			end = int(binary.LittleEndian.Uint16(data[4+2*i:]))
+		}
+		b := data[start:end]
+		pathLen := int(b[1]) * 2
+		reqs[i] = &cip.MessageRouterRequest{
+			Service:     cip.USINT(b[0]),
+			RequestPath: cip.Path(b[2 : 2+pathLen]),
+			RequestData: b[2+pathLen:],
+		}
+	}
+	return reqs
+}
+
+// encodeMSPResponse packs replies into Multiple Service Packet reply data.
+func encodeMSPResponse(replies []*cip.MessageRouterResponse) []byte {
+	var body []byte
+	offsets := make([]uint16, len(replies))
+	header := 2 + 2*len(replies)
+	for i, r := range replies {
+		offsets[i] = uint16(header + len(body))
+		body = append(body, byte(r.Service), 0, byte(r.GeneralStatus), byte(len(r.ExtStatus)))
+		for _, ext := range r.ExtStatus {
+			body = binary.LittleEndian.AppendUint16(body, uint16(ext))
+		}
+		body = append(body, r.ResponseData...)
+	}
+
+	out := binary.LittleEndian.AppendUint16(nil, uint16(len(replies)))
+	for _, o := range offsets {
+		out = binary.LittleEndian.AppendUint16(out, o)
+	}
+	return append(out, body...)
+}
+
+// tagNameOf returns the first symbolic segment of a path.
+func tagNameOf(p cip.Path) string {
+	if len(p) < 2 || p[0] != 0x91 {
+		return ""
+	}
+	return string(p[2 : 2+int(p[1])])
+}
+
+func TestClient_ReadTags(t *testing.T) {
+	packets := 0
+	maxPerPacket := 0
+
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		packets++
+		if req.Service != cip.ServiceMultipleServicePacket {
+			t.Errorf("service = 0x%02X, want 0x0A", req.Service)
+			return &cip.MessageRouterResponse{GeneralStatus: cip.StatusServiceNotSupported}
+		}
+		embedded := decodeMSPRequest(t, req.RequestData)
+		maxPerPacket = max(maxPerPacket, len(embedded))
+
+		enc, _ := req.Encode()
+		if len(enc) > defaultPacketSize {
+			t.Errorf("packet size %d exceeds %d", len(enc), defaultPacketSize)
+		}
+
+		status := cip.StatusSuccess
+		replies := make([]*cip.MessageRouterResponse, len(embedded))
+		for i, r := range embedded {
+			name := tagNameOf(r.RequestPath)
+			if name == "Missing" {
+				status = cip.StatusEmbeddedServiceError
+				replies[i] = &cip.MessageRouterResponse{Service: 0xCC, GeneralStatus: cip.StatusPathDestinationUnknown}
+				continue
+			}
+			var n int32
+			fmt.Sscanf(name, "Tag%d", &n)
+			replies[i] = &cip.MessageRouterResponse{
+				Service:      0xCC,
+				ResponseData: binary.LittleEndian.AppendUint32([]byte{0xC4, 0x00}, uint32(n)),
+			}
+		}
+		return &cip.MessageRouterResponse{GeneralStatus: status, ResponseData: encodeMSPResponse(replies)}
+	})
+
+	names := make([]string, 400)
+	for i := range names {
+		names[i] = fmt.Sprintf("Tag%d", i)
+	}
+	names[7] = "Missing"
+
+	results, err := c.ReadTags(names...)
+	if err != nil {
+		t.Fatalf("ReadTags() error = %v", err)
+	}
+	if len(results) != len(names) {
+		t.Fatalf("len(results) = %d, want %d", len(results), len(names))
+	}
+
+	for i, r := range results {
+		if r.Name != names[i] {
+			t.Errorf("results[%d].Name = %q, want %q", i, r.Name, names[i])
+		}
+		if i == 7 {
+			if r.Err == nil {
+				t.Error("expected error for missing tag")
+			}
+			continue
+		}
+		if r.Err != nil {
+			t.Errorf("results[%d].Err = %v", i, r.Err)
+			continue
+		}
+		if got := int32(binary.LittleEndian.Uint32(r.Data[2:])); got != int32(i) {
+			t.Errorf("results[%d] = %d, want %d", i, got, i)
+		}
+	}
+
+	if packets >= len(names)/2 {
+		t.Errorf("packets = %d, expected reads to be batched", packets)
+	}
+	if maxPerPacket < 10 {
+		t.Errorf("max reads per packet = %d, expected more", maxPerPacket)
+	}
+}
+
+func TestClient_ReadTags_PacketError(t *testing.T) {
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		return &cip.MessageRouterResponse{GeneralStatus: cip.StatusServiceNotSupported}
+	})
+
+	results, err := c.ReadTags("A", "B", "Bad[")
+	if err != nil {
+		t.Fatalf("ReadTags() error = %v", err)
+	}
+	for _, r := range results {
+		if r.Err == nil {
+			t.Errorf("%s: expected error", r.Name)
+		}
+	}
+}
+
+func TestClient_ReadTags_ReplyTooLarge(t *testing.T) {
+	// Each read is small but its reply is 102 bytes, so a packet sized by
+	// the requests asks for far more than one reply can carry. Like a
+	// Logix controller, the mock truncates the member that crosses the
+	// limit with a partial transfer and fails the rest with 0x11.
+	value := func(n int) []byte {
+		data := []byte{0xC4, 0x00}
+		for range 25 {
+			data = binary.LittleEndian.AppendUint32(data, uint32(n))
+		}
+		return data
+	}
+
+	wholePacket := true
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		if req.Service == cip.ServiceReadTag {
+			var n int
+			fmt.Sscanf(tagNameOf(req.RequestPath), "Tag%d", &n)
+			return &cip.MessageRouterResponse{Service: 0xCC, ResponseData: value(n)}
+		}
+
+		embedded := decodeMSPRequest(t, req.RequestData)
+		if wholePacket {
+			// First packet: refuse it outright
+			wholePacket = false
+			return &cip.MessageRouterResponse{GeneralStatus: cip.StatusReplyDataTooLarge}
+		}
+
+		room := defaultPacketSize - 4 - 2 - 2*len(embedded)
+		replies := make([]*cip.MessageRouterResponse, len(embedded))
+		for i, r := range embedded {
+			var n int
+			fmt.Sscanf(tagNameOf(r.RequestPath), "Tag%d", &n)
+			data := value(n)
+			switch {
+			case room >= 4+len(data):
+				replies[i] = &cip.MessageRouterResponse{Service: 0xCC, ResponseData: data}
+			case room > 4:
+				replies[i] = &cip.MessageRouterResponse{Service: 0xCC, GeneralStatus: cip.StatusPartialTransfer, ResponseData: data[:room-4]}
+			default:
+				replies[i] = &cip.MessageRouterResponse{Service: 0xCC, GeneralStatus: cip.StatusReplyDataTooLarge}
+			}
+			room -= 4 + len(replies[i].ResponseData)
+		}
+		return &cip.MessageRouterResponse{GeneralStatus: cip.StatusEmbeddedServiceError, ResponseData: encodeMSPResponse(replies)}
+	})
+
+	names := make([]string, 40)
+	for i := range names {
+		names[i] = fmt.Sprintf("Tag%d", i)
+	}
+
+	results, err := c.ReadTags(names...)
+	if err != nil {
+		t.Fatalf("ReadTags() error = %v", err)
+	}
+	for i, r := range results {
+		if r.Err != nil {
+			t.Errorf("results[%d].Err = %v", i, r.Err)
+			continue
+		}
+		if !bytes.Equal(r.Data, value(i)) {
+			t.Errorf("results[%d] = % X", i, r.Data)
+		}
+	}
+}
+
+func TestClient_WriteTags(t *testing.T) {
+	written := make(map[string][]byte)
+
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		embedded := []*cip.MessageRouterRequest{req}
+		if req.Service == cip.ServiceMultipleServicePacket {
+			embedded = decodeMSPRequest(t, req.RequestData)
+		}
+
+		replies := make([]*cip.MessageRouterResponse, len(embedded))
+		status := cip.StatusSuccess
+		for i, r := range embedded {
+			name := tagNameOf(r.RequestPath)
+			if name == "ReadOnly" {
+				status = cip.StatusEmbeddedServiceError
+				replies[i] = &cip.MessageRouterResponse{Service: 0xCD, GeneralStatus: cip.StatusPrivilegeViolation}
+				continue
+			}
+			written[name] = append([]byte(nil), r.RequestData...)
+			replies[i] = &cip.MessageRouterResponse{Service: 0xCD}
+		}
+
+		if req.Service != cip.ServiceMultipleServicePacket {
+			return replies[0]
+		}
+		return &cip.MessageRouterResponse{GeneralStatus: status, ResponseData: encodeMSPResponse(replies)}
+	})
+
+	failures, err := c.WriteTags(map[string]any{
+		"Speed":    float32(1.0),
+		"Count":    int32(7),
+		"ReadOnly": int32(1),
+		"Bad":      struct{}{},
+	})
+	if err != nil {
+		t.Fatalf("WriteTags() error = %v", err)
+	}
+
+	if len(failures) != 2 || failures["ReadOnly"] == nil || failures["Bad"] == nil {
+		t.Errorf("failures = %v, want ReadOnly and Bad", failures)
+	}
+	if !bytes.Equal(written["Count"], []byte{0xC4, 0x00, 0x01, 0x00, 0x07, 0x00, 0x00, 0x00}) {
+		t.Errorf("Count write = %X", written["Count"])
+	}
+	if !bytes.Equal(written["Speed"], []byte{0xCA, 0x00, 0x01, 0x00, 0x00, 0x00, 0x80, 0x3F}) {
+		t.Errorf("Speed write = %X", written["Speed"])
+	}
+}
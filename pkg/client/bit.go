@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// WriteBit sets or clears one bit of an integer tag (SINT, INT, DINT, LINT
+// or their unsigned and bit string counterparts) with the Read-Modify-Write
+// Tag service (0x4E). The controller changes the bit in place, so other bits
+// written by the program in the meantime are preserved.
+// The tag is read once to learn the size of its data type.
+func (c *Client) WriteBit(tagName string, bit int, value bool) error {
+	return c.WriteBitContext(context.Background(), tagName, bit, value)
+}
+
+// WriteBitContext is like WriteBit but gives up when ctx is done.
+func (c *Client) WriteBitContext(ctx context.Context, tagName string, bit int, value bool) error {
+	p, err := c.valuePath(ctx, tagName)
+	if err != nil {
+		return err
+	}
+	return c.writeBit(ctx, p, bit, value)
+}
+
+func (c *Client) writeBit(ctx context.Context, p cip.Path, bit int, value bool) error {
+	if err := c.supports(FeatureReadModifyWrite, "write bit"); err != nil {
+		return err
+	}
+
+	dataType, err := c.readType(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	size := cip.AtomicSize(dataType)
+	if size == 0 || dataType == cip.TypeBOOL || dataType == cip.TypeREAL || dataType == cip.TypeLREAL {
+		return fmt.Errorf("cannot write a bit of a %s tag", dataType)
+	}
+
+	req, err := cip.NewWriteBitRequest(p, size, bit, value)
+	if err != nil {
+		return err
+	}
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return err
+	}
+	return resp.Error()
+}
+
+// readType reads one element at p and returns its data type.
+func (c *Client) readType(ctx context.Context, p cip.Path) (cip.DataType, error) {
+	data, err := c.readTag(ctx, p, 1)
+	if err != nil {
+		return 0, err
+	}
+	typeInfo, _, err := cip.SplitReadTagResponse(data)
+	if err != nil {
+		return 0, err
+	}
+	return cip.DataType(binary.LittleEndian.Uint16(typeInfo)), nil
+}
+
+// writeBool handles writes of a bool that must not touch neighbouring
+// bits: bit paths ("Tag.5") and elements of BOOL arrays ("Flags[37]"),
+// which Logix stores packed in DWORDs. It reports false when tp is an
+// ordinary value that the regular Write Tag service can write.
+func (c *Client) writeBool(ctx context.Context, tp *cip.TagPath, value bool) (bool, error) {
+	if tp.HasBit() {
+		return true, c.writeBit(ctx, c.tagPath(ctx, tp), tp.Bit, value)
+	}
+
+	p, bit, ok, err := c.boolElement(ctx, tp)
+	if err != nil || !ok {
+		return ok, err
+	}
+	return true, c.writeBit(ctx, p, bit, value)
+}
+
+// boolElement reports whether tp addresses one element of a BOOL array. If
+// so it returns the path of the DWORD holding the element ("Flags[37]" is
+// in "Flags[1]") and the element's bit within it.
+func (c *Client) boolElement(ctx context.Context, tp *cip.TagPath) (cip.Path, int, bool, error) {
+	last := tp.Segments[len(tp.Segments)-1]
+	if len(last.Indices) != 1 || !c.has(FeaturePackedBoolArrays) {
+		return nil, 0, false, nil
+	}
+
+	// Look at the array itself: BOOL arrays read back as DWORDs
+	base := *tp
+	base.Segments = append([]cip.TagSegment(nil), tp.Segments...)
+	base.Segments[len(base.Segments)-1].Indices = nil
+
+	dataType, err := c.arrayType(ctx, &base)
+	if err != nil {
+		return nil, 0, true, err
+	}
+	if dataType != cip.TypeDWORD {
+		return nil, 0, false, nil
+	}
+
+	index := last.Indices[0]
+	base.Segments[len(base.Segments)-1].Indices = []uint32{index / 32}
+	return c.tagPath(ctx, &base), int(index % 32), true, nil
+}
+
+// arrayType returns the data type an array reads back as. Types are cached
+// like symbol instance IDs, so only the first access to an array costs an
+// extra read; without change counters to detect a download every access
+// reads the type again.
+func (c *Client) arrayType(ctx context.Context, tp *cip.TagPath) (cip.DataType, error) {
+	c.refreshSymbols(ctx)
+
+	key := string(tp.Path())
+	sc := &c.symbols
+	sc.mu.Lock()
+	dataType, ok := sc.types[key]
+	sc.mu.Unlock()
+	if ok {
+		return dataType, nil
+	}
+
+	c.trackChanges(ctx)
+	dataType, err := c.readType(ctx, c.tagPath(ctx, tp))
+	if err != nil {
+		return 0, err
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if sc.counters != nil {
+		if sc.types == nil {
+			sc.types = make(map[string]cip.DataType)
+		}
+		sc.types[key] = dataType
+	}
+	return dataType, nil
+}
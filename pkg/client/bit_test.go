@@ -0,0 +1,226 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// rmwRequest is a Read-Modify-Write Tag request seen by the mock.
+type rmwRequest struct {
+	path []byte
+	data []byte
+}
+
+func TestClient_WriteTag_Bits(t *testing.T) {
+	tests := []struct {
+		name      string
+		tag       string
+		value     bool
+		types     map[string]cip.DataType // Reply type by symbolic path
+		wantRMW   *rmwRequest
+		wantPlain bool // Whether a plain Write Tag is expected instead
+		wantErr   bool
+	}{
+		{
+			name:  "DINT bit",
+			tag:   "Status.5",
+			value: true,
+			types: map[string]cip.DataType{"Status": cip.TypeDINT},
+			wantRMW: &rmwRequest{
+				path: []byte{0x91, 0x06, 'S', 't', 'a', 't', 'u', 's'},
+				data: []byte{0x04, 0x00, 0x20, 0x00, 0x00, 0x00, 0xFF, 0xFF, 0xFF, 0xFF},
+			},
+		},
+		{
+			name:  "INT bit cleared",
+			tag:   "Word.15",
+			value: false,
+			types: map[string]cip.DataType{"Word": cip.TypeINT},
+			wantRMW: &rmwRequest{
+				path: []byte{0x91, 0x04, 'W', 'o', 'r', 'd'},
+				data: []byte{0x02, 0x00, 0x00, 0x00, 0xFF, 0x7F},
+			},
+		},
+		{
+			name:  "BOOL array element",
+			tag:   "Flags[37]",
+			value: true,
+			types: map[string]cip.DataType{"Flags": cip.TypeDWORD},
+			wantRMW: &rmwRequest{
+				path: []byte{0x91, 0x05, 'F', 'l', 'a', 'g', 's', 0x00, 0x28, 0x01},
+				data: []byte{0x04, 0x00, 0x20, 0x00, 0x00, 0x00, 0xFF, 0xFF, 0xFF, 0xFF},
+			},
+		},
+		{
+			name:      "element of a non-BOOL array",
+			tag:       "Modes[2]",
+			value:     true,
+			types:     map[string]cip.DataType{"Modes": cip.TypeSINT},
+			wantPlain: true,
+		},
+		{
+			name:    "bit of REAL",
+			tag:     "Speed.1",
+			value:   true,
+			types:   map[string]cip.DataType{"Speed": cip.TypeREAL},
+			wantErr: true,
+		},
+		{
+			name:    "bit out of range",
+			tag:     "Small.8",
+			value:   true,
+			types:   map[string]cip.DataType{"Small": cip.TypeSINT},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rmw *rmwRequest
+			var plain bool
+
+			c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+				switch req.Service {
+				case cip.ServiceReadTag:
+					dt, ok := tt.types[tagNameOf(req.RequestPath)]
+					if !ok {
+						return &cip.MessageRouterResponse{GeneralStatus: cip.StatusPathDestinationUnknown}
+					}
+					value := make([]byte, max(cip.AtomicSize(dt), 1))
+					return &cip.MessageRouterResponse{ResponseData: append([]byte{byte(dt), byte(dt >> 8)}, value...)}
+				case cip.ServiceReadModifyWriteTag:
+					rmw = &rmwRequest{path: req.RequestPath, data: req.RequestData}
+					return &cip.MessageRouterResponse{}
+				case cip.ServiceWriteTag:
+					plain = true
+					return &cip.MessageRouterResponse{}
+				case cip.ServiceGetAttributeList:
+					// No change counters
+					return &cip.MessageRouterResponse{GeneralStatus: cip.StatusPathDestinationUnknown}
+				}
+				t.Errorf("unexpected service 0x%02X", req.Service)
+				return &cip.MessageRouterResponse{GeneralStatus: cip.StatusServiceNotSupported}
+			})
+
+			err := c.WriteTag(tt.tag, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WriteTag() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if rmw != nil || plain {
+					t.Error("write sent despite error")
+				}
+				return
+			}
+
+			if plain != tt.wantPlain {
+				t.Errorf("plain Write Tag sent = %v, want %v", plain, tt.wantPlain)
+			}
+			if tt.wantRMW == nil {
+				if rmw != nil {
+					t.Errorf("unexpected Read-Modify-Write request")
+				}
+				return
+			}
+			if rmw == nil {
+				t.Fatal("no Read-Modify-Write request sent")
+			}
+			if !bytes.Equal(rmw.path, tt.wantRMW.path) {
+				t.Errorf("path = % X, want % X", rmw.path, tt.wantRMW.path)
+			}
+			if !bytes.Equal(rmw.data, tt.wantRMW.data) {
+				t.Errorf("data = % X, want % X", rmw.data, tt.wantRMW.data)
+			}
+		})
+	}
+}
+
+func TestClient_WriteBit(t *testing.T) {
+	var got []byte
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		if req.Service == cip.ServiceReadTag {
+			return &cip.MessageRouterResponse{ResponseData: []byte{0xC2, 0x00, 0x00}}
+		}
+		got = req.RequestData
+		return &cip.MessageRouterResponse{}
+	})
+
+	if err := c.WriteBit("Mode", 3, true); err != nil {
+		t.Fatalf("WriteBit() error = %v", err)
+	}
+	want := []byte{0x01, 0x00, 0x08, 0xFF}
+	if !bytes.Equal(got, want) {
+		t.Errorf("request data = % X, want % X", got, want)
+	}
+}
+
+func TestClient_BoolArrayRoundTrip(t *testing.T) {
+	// Flags is a BOOL[64], stored as two DWORDs
+	var dwords [2]uint32
+	types := 0
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		if bytes.Equal(req.RequestPath, cip.BuildPath(cip.ClassController, 1, 0)) {
+			return &cip.MessageRouterResponse{ResponseData: []byte{0x01, 0x02}}
+		}
+		if tagNameOf(req.RequestPath) != "Flags" {
+			return &cip.MessageRouterResponse{GeneralStatus: cip.StatusPathDestinationUnknown}
+		}
+		var index uint32
+		if elem := req.RequestPath[8:]; len(elem) > 0 {
+			index = uint32(elem[1])
+		} else {
+			types++
+		}
+		if index >= uint32(len(dwords)) {
+			return &cip.MessageRouterResponse{GeneralStatus: cip.StatusPathSegmentError}
+		}
+
+		switch req.Service {
+		case cip.ServiceReadTag:
+			reply := binary.LittleEndian.AppendUint16(nil, uint16(cip.TypeDWORD))
+			return &cip.MessageRouterResponse{ResponseData: binary.LittleEndian.AppendUint32(reply, dwords[index])}
+		case cip.ServiceReadModifyWriteTag:
+			or := binary.LittleEndian.Uint32(req.RequestData[2:6])
+			and := binary.LittleEndian.Uint32(req.RequestData[6:10])
+			dwords[index] = (dwords[index] | or) & and
+			return &cip.MessageRouterResponse{}
+		}
+		t.Errorf("unexpected service 0x%02X", req.Service)
+		return &cip.MessageRouterResponse{GeneralStatus: cip.StatusServiceNotSupported}
+	})
+
+	if err := c.WriteTag("Flags[37]", true); err != nil {
+		t.Fatalf("WriteTag() error = %v", err)
+	}
+	if dwords != [2]uint32{0, 1 << 5} {
+		t.Fatalf("DWORDs after write = %08X", dwords)
+	}
+
+	for _, tt := range []struct {
+		tag  string
+		want bool
+	}{
+		{"Flags[37]", true},
+		{"Flags[36]", false},
+		{"Flags[5]", false},
+	} {
+		data, err := c.ReadTag(tt.tag)
+		if err != nil {
+			t.Fatalf("ReadTag(%q) error = %v", tt.tag, err)
+		}
+		if len(data) != 3 || cip.DataType(binary.LittleEndian.Uint16(data)) != cip.TypeBOOL {
+			t.Fatalf("ReadTag(%q) = % X, want a BOOL", tt.tag, data)
+		}
+		if got := data[2] != 0; got != tt.want {
+			t.Errorf("ReadTag(%q) = %v, want %v", tt.tag, got, tt.want)
+		}
+	}
+
+	// The array's type is read once and then cached
+	if types != 1 {
+		t.Errorf("array type reads = %d, want 1", types)
+	}
+}
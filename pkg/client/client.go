@@ -1,39 +1,151 @@
 package client
 
 import (
+	"context"
+	"encoding/binary"
 	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/iceisfun/goeip/internal"
 	"github.com/iceisfun/goeip/pkg/cip"
+	"github.com/iceisfun/goeip/pkg/objects/connmgr"
 	"github.com/iceisfun/goeip/pkg/session"
 	"github.com/iceisfun/goeip/pkg/transport"
 )
 
-// Client is a high-level EIP client
+// Client is a high-level EIP client.
+// A Client is safe for concurrent use; requests from several goroutines share
+// one connection.
 type Client struct {
 	session *session.Session
 	logger  internal.Logger
+
+	// maxPacketSize bounds the size of a single CIP request.
+	// Zero selects defaultPacketSize.
+	maxPacketSize int
+
+	// connected selects connected explicit messaging, see
+	// WithConnectedMessaging. conn is the open class 3 connection.
+	connected bool
+	conn      atomic.Pointer[messageConnection]
+	// keepaliveInterval is how long the connection may stay idle before
+	// a keepalive request. Zero selects connectedKeepalive.
+	keepaliveInterval time.Duration
+
+	// route is the path from the device at the client's address to the
+	// target, see WithRoutePath. Unconnected requests are wrapped in
+	// Unconnected Send with routeTick and routeTicks as their timeout.
+	route      cip.Path
+	routeErr   error
+	routeTick  cip.BYTE
+	routeTicks cip.USINT
+
+	// profile describes the controller, see WithProfile. Nil uses all
+	// services. connectionSize is the size requested for connected
+	// messaging; zero selects the Large_Forward_Open maximum.
+	profile        *Profile
+	connectionSize int
+
+	// symbols caches symbol instance IDs, see ListTags.
+	symbols             symbolCache
+	symbolCheckInterval time.Duration
+
+	// templates caches Logix templates and variableTypes Omron
+	// structure types, see ReadTemplate and ReadVariableType.
+	templateMu    sync.Mutex
+	templates     map[uint16]*cip.Template
+	variableTypes map[uint32]*cip.VariableType
+}
+
+// ClientOption configures a Client.
+type ClientOption func(*Client)
+
+// WithConnectedMessaging sends all requests over a class 3 connection to the
+// Message Router, opened with Large_Forward_Open (or Forward_Open) when the
+// client connects and closed with Forward_Close by Close. Logix controllers serve connected
+// requests at a higher priority than unconnected ones.
+// Default is false.
+func WithConnectedMessaging(b bool) ClientOption {
+	return func(c *Client) {
+		c.connected = b
+	}
+}
+
+// WithRoutePath routes all requests from the device at the client's address
+// to a target behind it, such as a controller in another chassis slot
+// ("1,3": backplane, slot 3) or behind a bridge module
+// ("1,0,2,192.168.2.10,1,0"). See cip.ParseRoutePath for the format.
+// Unconnected requests are sent with Unconnected Send; with connected
+// messaging the route is part of the connection path.
+func WithRoutePath(route string) ClientOption {
+	return func(c *Client) {
+		c.route, c.routeErr = cip.ParseRoutePath(route)
+	}
+}
+
+// WithRouteTimeout sets how long the devices along the route wait for the
+// target to answer a routed unconnected request.
+// Default is about 5 seconds.
+func WithRouteTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.routeTick, c.routeTicks = connmgr.UnconnectedSendTimeout(d)
+	}
 }
 
 // NewClient creates a new client
-func NewClient(address string, logger internal.Logger) (*Client, error) {
-	t, err := transport.NewTCPTransport(address)
+func NewClient(address string, logger internal.Logger, opts ...ClientOption) (*Client, error) {
+	return NewClientContext(context.Background(), address, logger, opts...)
+}
+
+// NewClientContext creates a new client, giving up on connecting and
+// registering the session when ctx is done.
+func NewClientContext(ctx context.Context, address string, logger internal.Logger, opts ...ClientOption) (*Client, error) {
+	if logger == nil {
+		logger = internal.NopLogger()
+	}
+
+	c := &Client{
+		logger:     logger,
+		routeTick:  connectedPriorityTimeTick,
+		routeTicks: connectedTimeoutTicks,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.routeErr != nil {
+		return nil, c.routeErr
+	}
+
+	t, err := transport.NewTCPTransportContext(ctx, address)
 	if err != nil {
 		return nil, err
 	}
 
 	s := session.NewSession(t, logger)
-	if err := s.Register(); err != nil {
+	if err := s.RegisterContext(ctx); err != nil {
 		t.Close()
 		return nil, err
 	}
+	c.session = s
 
-	return &Client{session: s, logger: logger}, nil
+	if c.connected {
+		if err := c.openConnection(ctx); err != nil {
+			s.Unregister()
+			t.Close()
+			return nil, err
+		}
+	}
+
+	return c, nil
 }
 
-// Close closes the client connection
 // Close closes the client connection
 func (c *Client) Close() error {
+	if err := c.closeConnection(context.Background()); err != nil {
+		c.logger.Errorf("Failed to close connection: %v", err)
+	}
 	if err := c.session.Unregister(); err != nil {
 		// Log error but continue to close transport
 		c.logger.Errorf("Failed to unregister session: %v", err)
@@ -41,21 +153,111 @@ func (c *Client) Close() error {
 	return c.session.Close()
 }
 
-// ReadTag reads a tag from the PLC
+// ReadTag reads a tag from the PLC.
+// tagName is a Logix tag expression (see cip.ParseTagPath). When it ends
+// with a bit selector such as "Status.5" the containing value is read and
+// the result is returned as a BOOL. Elements of BOOL arrays ("Flags[37]")
+// are read the same way from the DWORD holding them, which takes an extra
+// read of the array's type the first time an array is indexed.
 func (c *Client) ReadTag(tagName string) ([]byte, error) {
+	return c.ReadTagContext(context.Background(), tagName)
+}
+
+// ReadTagContext is like ReadTag but gives up when ctx is done.
+func (c *Client) ReadTagContext(ctx context.Context, tagName string) ([]byte, error) {
 	// Build Path
-	p := cip.NewPath()
-	p.AddSymbolicSegment(tagName)
+	tp, err := cip.ParseTagPath(tagName)
+	if err != nil {
+		return nil, err
+	}
+
+	p, bit := c.tagPath(ctx, tp), tp.Bit
+	if !tp.HasBit() {
+		dword, b, ok, err := c.boolElement(ctx, tp)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			p, bit = dword, b
+		}
+	}
+
+	// Read 1 element
+	data, err := c.readTag(ctx, p, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if bit >= 0 {
+		return extractBit(data, bit)
+	}
+	return data, nil
+}
+
+// ReadTagArray reads count consecutive elements of an array tag starting at
+// index start. The returned data has the same layout as ReadTag: the type
+// code followed by the packed element values.
+func (c *Client) ReadTagArray(tagName string, start uint32, count uint16) ([]byte, error) {
+	return c.ReadTagArrayContext(context.Background(), tagName, start, count)
+}
+
+// ReadTagArrayContext is like ReadTagArray but gives up when ctx is done.
+func (c *Client) ReadTagArrayContext(ctx context.Context, tagName string, start uint32, count uint16) ([]byte, error) {
+	p, err := c.valuePath(ctx, tagName)
+	if err != nil {
+		return nil, err
+	}
+	p.AddElement(start)
+
+	return c.readTag(ctx, p, count)
+}
+
+// parseValueTag parses a tag expression that must address a whole value,
+// i.e. one without a bit selector.
+func parseValueTag(tagName string) (*cip.TagPath, error) {
+	tp, err := cip.ParseTagPath(tagName)
+	if err != nil {
+		return nil, err
+	}
+	if tp.HasBit() {
+		return nil, fmt.Errorf("tag %q addresses a bit, not a value", tagName)
+	}
+	return tp, nil
+}
+
+// extractBit converts a Read Tag reply into a BOOL reply holding the
+// selected bit of the value.
+func extractBit(data []byte, bit int) ([]byte, error) {
+	_, value, err := cip.SplitReadTagResponse(data)
+	if err != nil {
+		return nil, err
+	}
+	if bit/8 >= len(value) {
+		return nil, fmt.Errorf("bit %d out of range for %d-byte value", bit, len(value))
+	}
 
-	// Create Request (Read 1 element)
-	req := cip.NewReadTagRequest(p, 1)
+	result := []byte{byte(cip.TypeBOOL), byte(cip.TypeBOOL >> 8), 0x00}
+	if value[bit/8]&(1<<(bit%8)) != 0 {
+		result[2] = 0x01
+	}
+	return result, nil
+}
+
+func (c *Client) readTag(ctx context.Context, p cip.Path, elements uint16) ([]byte, error) {
+	// Create Request
+	req := cip.NewReadTagRequest(p, elements)
 
 	// Send Request
-	resp, err := c.session.SendCIPRequest(req)
+	resp, err := c.send(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
+	// The value does not fit in one reply, fetch it in fragments instead
+	if resp.GeneralStatus == cip.StatusPartialTransfer {
+		return c.readFragmented(ctx, p, elements)
+	}
+
 	if err := resp.Error(); err != nil {
 		return nil, err
 	}
@@ -69,31 +271,49 @@ func (c *Client) ReadTag(tagName string) ([]byte, error) {
 }
 
 // WriteTag writes a value to a tag on the PLC.
-// The value must be a basic Go type (int, float, etc.) or implement cip.Marshaler.
+// The value must be a basic Go type (int, float, etc.), a string, a slice or
+// array of basic types, or implement cip.Marshaler.
+// Slices write one element per entry starting at the tag's first element;
+// []bool and [N]bool are written as packed 32-bit words and must be a
+// multiple of 32 long.
+// Strings are written in the tag's own string type (STRING, a user-defined
+// string type, SHORT_STRING or STRING2), which costs one extra read.
+// A bool written to a bit ("Tag.5") or to an element of a BOOL array
+// ("Flags[37]") uses Read-Modify-Write so that other bits are untouched.
 func (c *Client) WriteTag(tagName string, value any) error {
-	// Build Path
-	p := cip.NewPath()
-	p.AddSymbolicSegment(tagName)
+	return c.WriteTagContext(context.Background(), tagName, value)
+}
 
-	// Determine Data Type
-	dataType, err := cip.GoTypeToCIPType(value)
-	if err != nil {
-		return err
+// WriteTagContext is like WriteTag but gives up when ctx is done.
+func (c *Client) WriteTagContext(ctx context.Context, tagName string, value any) error {
+	if b, ok := value.(bool); ok {
+		tp, err := cip.ParseTagPath(tagName)
+		if err != nil {
+			return err
+		}
+		if handled, err := c.writeBool(ctx, tp, b); handled {
+			return err
+		}
 	}
 
-	// Marshaling Data
-	data, err := cip.Marshal(value)
+	w, err := c.newTagWrite(ctx, tagName, value)
 	if err != nil {
 		return err
 	}
+	return c.writeTag(ctx, w)
+}
 
-	// Create Request (Write 1 element)
-	// TODO: Support arrays (element count > 1) if value is slice?
-	// For now, assume 1 element.
-	req := cip.NewWriteTagRequest(p, dataType, 1, data)
+// writeTag sends w with Write Tag, or with Write Tag Fragmented when it
+// does not fit in one packet.
+func (c *Client) writeTag(ctx context.Context, w *tagWrite) error {
+	// Service (1) + Path Size (1) + Path + Type + Elements (2) + Data
+	req := w.request()
+	if 2+len(req.RequestPath)+len(req.RequestData) > c.packetSize() {
+		return c.writeFragmented(ctx, w)
+	}
 
 	// Send Request
-	resp, err := c.session.SendCIPRequest(req)
+	resp, err := c.send(ctx, req)
 	if err != nil {
 		return err
 	}
@@ -105,45 +325,111 @@ func (c *Client) WriteTag(tagName string, value any) error {
 	return nil
 }
 
+// tagWrite is a value resolved and encoded for writing to a tag.
+type tagWrite struct {
+	path     cip.Path
+	dataType cip.DataType
+	handle   uint16 // Structure handle when dataType is TypeSTRUCT
+	elements uint16
+	data     []byte
+}
+
+// newTagWrite parses the tag path and encodes value for a Write Tag request.
+func (c *Client) newTagWrite(ctx context.Context, tagName string, value any) (*tagWrite, error) {
+	if s, ok := value.(string); ok {
+		return c.newStringWrite(ctx, tagName, s)
+	}
+
+	// Build Path
+	p, err := c.valuePath(ctx, tagName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Determine Data Type
+	dataType, err := cip.GoTypeToCIPType(value)
+	if err != nil {
+		return nil, err
+	}
+
+	elements, err := cip.ElementCount(value)
+	if err != nil {
+		return nil, err
+	}
+
+	// Marshaling Data
+	data, err := cip.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tagWrite{path: p, dataType: dataType, elements: elements, data: data}, nil
+}
+
+// request builds the Write Tag request for w.
+func (w *tagWrite) request() *cip.MessageRouterRequest {
+	if w.dataType == cip.TypeSTRUCT {
+		return cip.NewWriteStructTagRequest(w.path, w.handle, w.elements, w.data)
+	}
+	return cip.NewWriteTagRequest(w.path, w.dataType, w.elements, w.data)
+}
+
+// fragment builds the Write Tag Fragmented request for the part of w's data
+// starting at offset.
+func (w *tagWrite) fragment(offset int, data []byte) *cip.MessageRouterRequest {
+	if w.dataType == cip.TypeSTRUCT {
+		return cip.NewWriteStructTagFragmentedRequest(w.path, w.handle, w.elements, uint32(offset), data)
+	}
+	return cip.NewWriteTagFragmentedRequest(w.path, w.dataType, w.elements, uint32(offset), data)
+}
+
 // ReadTagInto reads a tag from the PLC and unmarshals it into dst.
 // dst must be a pointer to a type that can be unmarshaled (basic type, struct, or Unmarshaler).
+// Structs are decoded with the Logix member layout, see cip.Marshal.
+// A *string accepts any string tag: STRING, user-defined string types,
+// SHORT_STRING and STRING2.
 func (c *Client) ReadTagInto(tagName string, dst any) error {
-	data, err := c.ReadTag(tagName)
+	return c.ReadTagIntoContext(context.Background(), tagName, dst)
+}
+
+// ReadTagIntoContext is like ReadTagInto but gives up when ctx is done.
+func (c *Client) ReadTagIntoContext(ctx context.Context, tagName string, dst any) error {
+	data, err := c.ReadTagContext(ctx, tagName)
 	if err != nil {
 		return err
 	}
 
-	// The ReadTag response includes the data type code (UINT) at the beginning.
-	// We need to skip it to get to the actual data.
-	// Response format: [Type:UINT] [Data...]
-	if len(data) < 2 {
-		return fmt.Errorf("response too short to contain type code")
+	// Skip the type information: the type code, plus the structure handle
+	// for structures
+	typeInfo, value, err := cip.SplitReadTagResponse(data)
+	if err != nil {
+		return err
 	}
 
-	// Skip the first 2 bytes (Type)
-	return cip.Unmarshal(data[2:], dst)
+	if s, ok := dst.(*string); ok {
+		dataType := cip.DataType(binary.LittleEndian.Uint16(typeInfo))
+		*s, err = cip.DecodeString(dataType, value)
+		return err
+	}
+
+	return cip.Unmarshal(value, dst)
 }
 
 // ReadTimer reads a Timer tag from the PLC and decodes it.
 func (c *Client) ReadTimer(tagName string) (*cip.Timer, error) {
-	data, err := c.ReadTag(tagName)
+	return c.ReadTimerContext(context.Background(), tagName)
+}
+
+// ReadTimerContext is like ReadTimer but gives up when ctx is done.
+func (c *Client) ReadTimerContext(ctx context.Context, tagName string) (*cip.Timer, error) {
+	data, err := c.ReadTagContext(ctx, tagName)
 	if err != nil {
 		return nil, err
 	}
 
-	// The ReadTag response includes the data type code (UINT) at the beginning.
-	// We need to skip it to get to the actual Timer structure data.
-	// Response format: [Type:UINT] [Data...]
-	if len(data) < 2 {
-		return nil, fmt.Errorf("response too short to contain type code")
+	_, value, err := cip.SplitReadTagResponse(data)
+	if err != nil {
+		return nil, err
 	}
-
-	// Check if the type is a structure (0x02A0) or just raw bytes?
-	// Actually, for a TIMER, it might return the raw bytes of the structure.
-	// The type code for a structure is typically 0x02A0 (Structure).
-	// But let's just skip the first 2 bytes (Type) and pass the rest to DecodeTimer.
-	// Note: DecodeTimer expects 14 bytes.
-	// So we need at least 2 + 14 = 16 bytes.
-
-	return cip.DecodeTimer(data[2:])
+	return cip.DecodeTimer(value)
 }
@@ -1,10 +1,14 @@
 package client
 
 import (
+	"context"
 	"encoding/binary"
+	"errors"
 	"io"
 	"net"
 	"testing"
+
+	"github.com/iceisfun/goeip/pkg/cip"
 )
 
 // MockLogger implements internal.Logger for testing
@@ -46,6 +50,7 @@ func TestNewClient(t *testing.T) {
 		binary.LittleEndian.PutUint16(resp[2:4], 4)          // Length
 		binary.LittleEndian.PutUint32(resp[4:8], 0x01020304) // Session Handle
 		binary.LittleEndian.PutUint16(resp[24:26], 1)        // Protocol Version
+		copy(resp[12:20], buf[12:20])                        // Echo Sender Context
 		conn.Write(resp)
 	}()
 
@@ -81,6 +86,7 @@ func TestClient_ReadTag(t *testing.T) {
 		binary.LittleEndian.PutUint16(resp[2:4], 4)
 		binary.LittleEndian.PutUint32(resp[4:8], 0x01020304)
 		binary.LittleEndian.PutUint16(resp[24:26], 1)
+		copy(resp[12:20], buf[12:20]) // Echo Sender Context
 		conn.Write(resp)
 
 		// Read SendRRData (ReadTag)
@@ -132,6 +138,7 @@ func TestClient_ReadTag(t *testing.T) {
 		binary.LittleEndian.PutUint16(encap[2:4], uint16(6+len(cpf))) // Interface Handle(4) + Timeout(2) + CPF
 
 		// Write Response
+		copy(encap[12:20], headerBuf[12:20]) // Echo Sender Context
 		conn.Write(encap)
 		// Interface Handle + Timeout
 		conn.Write([]byte{0, 0, 0, 0, 0, 0})
@@ -191,6 +198,7 @@ func TestClient_ReadTagInto(t *testing.T) {
 		binary.LittleEndian.PutUint16(resp[2:4], 4)
 		binary.LittleEndian.PutUint32(resp[4:8], 0x01020304)
 		binary.LittleEndian.PutUint16(resp[24:26], 1)
+		copy(resp[12:20], buf[12:20]) // Echo Sender Context
 		if _, err := conn.Write(resp); err != nil {
 			t.Logf("MockServer: Write Register Session Response failed: %v", err)
 			return
@@ -232,6 +240,7 @@ func TestClient_ReadTagInto(t *testing.T) {
 		copy(cpf[10:], cipData)
 
 		binary.LittleEndian.PutUint16(encap[2:4], uint16(6+len(cpf)))
+		copy(encap[12:20], headerBuf[12:20]) // Echo Sender Context
 		conn.Write(encap)
 		conn.Write([]byte{0, 0, 0, 0, 0, 0})
 		conn.Write(cpf)
@@ -278,6 +287,7 @@ func TestClient_ReadTimer(t *testing.T) {
 		binary.LittleEndian.PutUint16(resp[2:4], 4)
 		binary.LittleEndian.PutUint32(resp[4:8], 0x01020304)
 		binary.LittleEndian.PutUint16(resp[24:26], 1)
+		copy(resp[12:20], buf[12:20]) // Echo Sender Context
 		conn.Write(resp)
 
 		// Read SendRRData (ReadTimer)
@@ -299,7 +309,7 @@ func TestClient_ReadTimer(t *testing.T) {
 		binary.LittleEndian.PutUint32(encap[4:8], 0x01020304)
 
 		// CIP Response: Service(0xCC), Status(0)
-		// Data: Type(0xA002 = Struct?), Value(Timer Data)
+		// Data: Type(0x02A0 = Struct), Handle(0x0F83), Value(Timer Data)
 		// Timer Data: 14 bytes
 		timerData := make([]byte, 14)
 		// Status: EN(bit 31)
@@ -309,7 +319,7 @@ func TestClient_ReadTimer(t *testing.T) {
 		// ACC: 2500
 		binary.LittleEndian.PutUint32(timerData[10:14], 2500)
 
-		cipData := []byte{0xCC, 0x00, 0x00, 0x00, 0x02, 0xA0} // Type 0xA002 (Little Endian)
+		cipData := []byte{0xCC, 0x00, 0x00, 0x00, 0xA0, 0x02, 0x83, 0x0F} // Type 0x02A0, Handle 0x0F83 (Little Endian)
 		cipData = append(cipData, timerData...)
 
 		cpf := make([]byte, 2+4+4+len(cipData))
@@ -321,6 +331,7 @@ func TestClient_ReadTimer(t *testing.T) {
 		copy(cpf[10:], cipData)
 
 		binary.LittleEndian.PutUint16(encap[2:4], uint16(6+len(cpf)))
+		copy(encap[12:20], headerBuf[12:20]) // Echo Sender Context
 		conn.Write(encap)
 		conn.Write([]byte{0, 0, 0, 0, 0, 0})
 		conn.Write(cpf)
@@ -347,3 +358,20 @@ func TestClient_ReadTimer(t *testing.T) {
 		t.Errorf("Timer ACC = %d, want 2500", timer.ACC)
 	}
 }
+
+func TestClient_ReadTagContext_Canceled(t *testing.T) {
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		t.Errorf("unexpected request 0x%02X after cancellation", req.Service)
+		return &cip.MessageRouterResponse{}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := c.ReadTagContext(ctx, "MyTag"); !errors.Is(err, context.Canceled) {
+		t.Errorf("ReadTagContext() error = %v, want canceled", err)
+	}
+	if err := c.WriteTagContext(ctx, "MyTag", int32(1)); !errors.Is(err, context.Canceled) {
+		t.Errorf("WriteTagContext() error = %v, want canceled", err)
+	}
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// ReadWallClock reads the controller clock from the WallClockTime object
+// (class 0x8B), with its time zone and daylight saving time settings.
+func (c *Client) ReadWallClock() (*cip.WallClock, error) {
+	return c.ReadWallClockContext(context.Background())
+}
+
+// ReadWallClockContext is like ReadWallClock but gives up when ctx is done.
+func (c *Client) ReadWallClockContext(ctx context.Context) (*cip.WallClock, error) {
+	resp, err := c.send(ctx, cip.NewGetWallClockRequest())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wall clock: %w", err)
+	}
+	// Status 0x0A means some attributes failed, such as the time zone on
+	// controllers without one; the reply still lists every attribute
+	if resp.GeneralStatus != cip.StatusAttributeListError {
+		if err := resp.Error(); err != nil {
+			return nil, fmt.Errorf("failed to read wall clock: %w", err)
+		}
+	}
+	return cip.DecodeWallClockResponse(resp.ResponseData)
+}
+
+// SetWallClock sets the controller clock to t. Time zone and daylight
+// saving time settings are left unchanged. A controller that refuses the
+// change returns a cip.Error, e.g. one matching cip.ErrPrivilegeViolation.
+func (c *Client) SetWallClock(t time.Time) error {
+	return c.SetWallClockContext(context.Background(), t)
+}
+
+// SetWallClockContext is like SetWallClock but gives up when ctx is done.
+func (c *Client) SetWallClockContext(ctx context.Context, t time.Time) error {
+	req, err := cip.NewSetWallClockRequest(t)
+	if err != nil {
+		return err
+	}
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to set wall clock: %w", err)
+	}
+	if err := resp.Error(); err != nil {
+		return fmt.Errorf("failed to set wall clock: %w", err)
+	}
+	return nil
+}
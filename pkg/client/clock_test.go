@@ -0,0 +1,83 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+func TestReadSetWallClock(t *testing.T) {
+	stamp := time.Date(2024, 7, 1, 16, 30, 0, 0, time.UTC)
+	var set []byte
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		switch req.Service {
+		case cip.ServiceGetAttributeList:
+			data := []byte{1, 0, 6, 0, 0, 0}
+			data = binary.LittleEndian.AppendUint64(data, uint64(stamp.UnixMicro()))
+			return &cip.MessageRouterResponse{ResponseData: data}
+		case cip.ServiceSetAttributeSingle:
+			if set != nil {
+				return &cip.MessageRouterResponse{GeneralStatus: cip.StatusPrivilegeViolation}
+			}
+			set = req.RequestData
+			return &cip.MessageRouterResponse{}
+		}
+		return &cip.MessageRouterResponse{GeneralStatus: cip.StatusServiceNotSupported}
+	})
+
+	w, err := c.ReadWallClock()
+	if err != nil {
+		t.Fatalf("ReadWallClock() error = %v", err)
+	}
+	if !w.UTC.Equal(stamp) {
+		t.Errorf("UTC = %v, want %v", w.UTC, stamp)
+	}
+
+	if err := c.SetWallClock(stamp.Add(time.Second)); err != nil {
+		t.Fatalf("SetWallClock() error = %v", err)
+	}
+	want, _ := cip.EncodeTime(cip.TypeLINT, stamp.Add(time.Second))
+	if !bytes.Equal(set, want) {
+		t.Errorf("request data = % X, want % X", set, want)
+	}
+
+	if err := c.SetWallClock(stamp); !errors.Is(err, cip.ErrPrivilegeViolation) {
+		t.Errorf("SetWallClock() error = %v, want privilege violation", err)
+	}
+}
+
+func TestReadWallClock_AttributeListError(t *testing.T) {
+	stamp := time.Date(2024, 7, 1, 16, 30, 0, 0, time.UTC)
+	timeFails := false
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		// The controller has no time zone or daylight saving time attributes
+		data := []byte{4, 0}
+		if timeFails {
+			data = append(data, 6, 0, byte(cip.StatusAttributeNotSupported), 0)
+		} else {
+			data = append(data, 6, 0, 0, 0)
+			data = binary.LittleEndian.AppendUint64(data, uint64(stamp.UnixMicro()))
+		}
+		for _, id := range []byte{9, 10, 12} {
+			data = append(data, id, 0, byte(cip.StatusAttributeNotSupported), 0)
+		}
+		return &cip.MessageRouterResponse{GeneralStatus: cip.StatusAttributeListError, ResponseData: data}
+	})
+
+	w, err := c.ReadWallClock()
+	if err != nil {
+		t.Fatalf("ReadWallClock() error = %v", err)
+	}
+	if !w.UTC.Equal(stamp) || w.TimeZone != 0 || w.DSTAdjustment != 0 || w.ApplyDST {
+		t.Errorf("ReadWallClock() = %+v", w)
+	}
+
+	timeFails = true
+	if _, err := c.ReadWallClock(); err == nil {
+		t.Error("expected error when the current value fails")
+	}
+}
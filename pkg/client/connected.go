@@ -0,0 +1,196 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+	"github.com/iceisfun/goeip/pkg/objects/connmgr"
+	"github.com/iceisfun/goeip/pkg/session"
+)
+
+// Originator identification sent in Forward_Open and Forward_Close.
+// Together with the connection serial number it forms the connection triad.
+const (
+	originatorVendorID     = 0x1337
+	originatorSerialNumber = 0x47454950
+)
+
+// Forward_Open parameters for class 3 connections. The RPI of an explicit
+// connection only sets its inactivity timeout: RPI * 4 << multiplier,
+// about a minute here.
+const (
+	connectedPriorityTimeTick = 0x0A    // Tick 1024 ms
+	connectedTimeoutTicks     = 0x05    // Unconnected timeout for Forward_Open
+	connectedRPI              = 2000000 // µs
+	connectedTimeoutMult      = 3       // x32
+	connectedTransport        = 0xA3    // Server, application triggered, class 3
+)
+
+// connectedKeepalive is how long a class 3 connection may stay idle before
+// a no-op request is sent to keep the target from timing it out.
+const connectedKeepalive = 15 * time.Second
+
+// messageConnection is a class 3 connection to the Message Router.
+type messageConnection struct {
+	conn   *session.Connection
+	params connmgr.OpenParams // As opened, for Forward_Close
+
+	lastUsed atomic.Int64  // Unix nanoseconds of the last request
+	done     chan struct{} // Closed by closeConnection to stop keepAlive
+}
+
+// size returns the connection size, including the 2-byte sequence count.
+func (mc *messageConnection) size() int {
+	return min(mc.params.OT.Size, mc.params.TO.Size)
+}
+
+// messageRouterPath is the connection path of explicit message connections:
+// the route to the target followed by its Message Router.
+func (c *Client) messageRouterPath() cip.Path {
+	p := append(cip.NewPath(), c.route...)
+	return append(p, cip.BuildPath(cip.ClassMessageRouter, 1, 0)...)
+}
+
+// openConnection opens a class 3 connection to the Message Router with
+// Large_Forward_Open, or Forward_Open on targets without it. Subsequent
+// requests are sent over it with SendUnitData.
+func (c *Client) openConnection(ctx context.Context) error {
+	size := c.connectionSize
+	if size == 0 {
+		size = connmgr.MaxLargeForwardOpenSize
+	}
+	network := connmgr.NetworkParams{
+		Size:     size,
+		Variable: true,
+		Priority: connmgr.PriorityLow,
+		Type:     connmgr.ConnectionTypePointToPoint,
+	}
+	params := connmgr.OpenParams{
+		PriorityTimeTick:            connectedPriorityTimeTick,
+		TimeoutTicks:                connectedTimeoutTicks,
+		TOConnectionID:              cip.UDINT(rand.Uint32() | 1),
+		ConnectionSerialNumber:      cip.UINT(rand.Uint32()),
+		VendorID:                    originatorVendorID,
+		OriginatorSerialNumber:      originatorSerialNumber,
+		ConnectionTimeoutMultiplier: connectedTimeoutMult,
+		OTRPI:                       connectedRPI,
+		TORPI:                       connectedRPI,
+		OT:                          network,
+		TO:                          network,
+		TransportTypeTrigger:        connectedTransport,
+		ConnectionPath:              c.messageRouterPath(),
+	}
+
+	fo, params, err := connmgr.Open(func(req *cip.MessageRouterRequest) (*cip.MessageRouterResponse, error) {
+		return c.session.SendCIPRequestContext(ctx, req)
+	}, params)
+	if err != nil {
+		return err
+	}
+
+	mc := &messageConnection{
+		conn: &session.Connection{
+			OTConnectionID: uint32(fo.OTConnectionID),
+			TOConnectionID: uint32(params.TOConnectionID),
+		},
+		params: params,
+		done:   make(chan struct{}),
+	}
+	mc.lastUsed.Store(time.Now().UnixNano())
+	c.conn.Store(mc)
+	c.logger.Infof("Connected messaging opened (O->T 0x%08X, T->O 0x%08X, %d bytes)",
+		fo.OTConnectionID, params.TOConnectionID, mc.size())
+
+	interval := c.keepaliveInterval
+	if interval == 0 {
+		interval = connectedKeepalive
+	}
+	go c.keepAlive(mc, interval)
+	return nil
+}
+
+// keepAlive sends a Get_Attribute_Single of the Identity Object's vendor ID
+// over mc whenever it has been idle for interval, so that the target does
+// not close the connection after its inactivity timeout (about a minute).
+func (c *Client) keepAlive(mc *messageConnection, interval time.Duration) {
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-mc.done:
+			return
+		case <-ticker.C:
+		}
+
+		if time.Since(time.Unix(0, mc.lastUsed.Load())) < interval {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		req := cip.NewGetAttributeSingleRequest(cip.BuildPath(cip.ClassIdentity, 1, 1))
+		_, err := c.sendConnected(ctx, mc, req)
+		cancel()
+		if err != nil {
+			c.logger.Warnf("Connected messaging keepalive failed: %v", err)
+		}
+	}
+}
+
+// closeConnection closes the class 3 connection with Forward_Close.
+func (c *Client) closeConnection(ctx context.Context) error {
+	mc := c.conn.Swap(nil)
+	if mc == nil {
+		return nil
+	}
+	close(mc.done)
+
+	resp, err := c.session.SendCIPRequestContext(ctx, mc.params.CloseRequest())
+	if err != nil {
+		return fmt.Errorf("forward close: %w", err)
+	}
+	if err := resp.Error(); err != nil {
+		return fmt.Errorf("forward close: %w", err)
+	}
+	return nil
+}
+
+// send sends a CIP request over the class 3 connection when one is open,
+// and as an unconnected message otherwise. Unconnected messages to a routed
+// target are wrapped in Unconnected Send.
+func (c *Client) send(ctx context.Context, req *cip.MessageRouterRequest) (*cip.MessageRouterResponse, error) {
+	if mc := c.conn.Load(); mc != nil {
+		return c.sendConnected(ctx, mc, req)
+	}
+	if len(c.route) == 0 {
+		return c.session.SendCIPRequestContext(ctx, req)
+	}
+
+	routed, err := connmgr.UnconnectedSend(req, c.route, c.routeTick, c.routeTicks)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.session.SendCIPRequestContext(ctx, routed)
+	if err != nil {
+		return nil, err
+	}
+	return connmgr.UnwrapUnconnectedSend(resp)
+}
+
+// sendConnected sends a request over the class 3 connection mc.
+func (c *Client) sendConnected(ctx context.Context, mc *messageConnection, req *cip.MessageRouterRequest) (*cip.MessageRouterResponse, error) {
+	mc.lastUsed.Store(time.Now().UnixNano())
+	return c.session.SendConnectedCIPRequestContext(ctx, mc.conn, req)
+}
+
+// unconnectedSendOverhead is the number of bytes Unconnected Send adds to a
+// request routed along route: the request header addressing the Connection
+// Manager, the timeout, the embedded message size and pad byte, and the
+// route path with its size.
+func unconnectedSendOverhead(route cip.Path) int {
+	return 6 + 4 + 1 + 2 + len(route) + len(route)%2
+}
@@ -0,0 +1,226 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/iceisfun/goeip/internal"
+	"github.com/iceisfun/goeip/pkg/cip"
+	"github.com/iceisfun/goeip/pkg/eip"
+	"github.com/iceisfun/goeip/pkg/objects/connmgr"
+	"github.com/iceisfun/goeip/pkg/session"
+)
+
+// mockTarget answers Forward_Open and Forward_Close as a target would and
+// passes all other requests, connected or not, to handler.
+type mockTarget struct {
+	t       *testing.T
+	handler cipHandler
+
+	mu          sync.Mutex
+	pending     []mockPacket
+	toConnID    uint32
+	opened      bool
+	closed      bool
+	connected   int // Requests received over the connection
+	unconnected int // Requests received as unconnected messages
+}
+
+type mockPacket struct {
+	cmd  eip.Command
+	data []byte
+}
+
+const mockOTConnID = 0x11223344
+
+func newMockConnectedClient(t *testing.T, handler cipHandler) (*Client, *mockTarget) {
+	t.Helper()
+
+	m := &mockTarget{t: t, handler: handler}
+	mt := &MockTransport{
+		sendFunc: func(cmd eip.Command, data []byte, sessionHandle eip.SessionHandle) error {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			if cmd == eip.CommandSendRRData || cmd == eip.CommandSendUnitData {
+				m.pending = append(m.pending, mockPacket{cmd, data})
+			}
+			return nil
+		},
+		receiveFunc: m.receive,
+	}
+
+	s := session.NewSession(mt, internal.NopLogger())
+	c := &Client{session: s, logger: internal.NopLogger(), connected: true}
+	if err := c.openConnection(context.Background()); err != nil {
+		t.Fatalf("openConnection() error = %v", err)
+	}
+	return c, m
+}
+
+func (m *mockTarget) receive() (*eip.EncapsulationHeader, []byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.pending) == 0 {
+		return nil, nil, fmt.Errorf("no pending request")
+	}
+	p := m.pending[0]
+	m.pending = m.pending[1:]
+
+	header := &eip.EncapsulationHeader{Command: p.cmd, Status: eip.StatusSuccess}
+
+	if p.cmd == eip.CommandSendUnitData {
+		cpf, err := eip.DecodeCommonPacketFormat(p.data[6:])
+		if err != nil {
+			return nil, nil, err
+		}
+		addr := cpf.FindItemByType(eip.ItemIDConnectedAddress)
+		item := cpf.FindItemByType(eip.ItemIDConnectedData)
+		if binary.LittleEndian.Uint32(addr.Data) != mockOTConnID {
+			m.t.Errorf("mock: request on connection 0x%08X, want 0x%08X", binary.LittleEndian.Uint32(addr.Data), mockOTConnID)
+		}
+		req, err := decodeMockCIPRequest(item.Data[2:])
+		if err != nil {
+			return nil, nil, err
+		}
+		m.connected++
+
+		connAddr := binary.LittleEndian.AppendUint32(nil, m.toConnID)
+		payload := append(append([]byte{}, item.Data[:2]...), encodeMockCIPResponse(m.handlerReply(req))...)
+		cpfData, _ := eip.NewCommonPacketFormat(
+			eip.NewCPFItem(eip.ItemIDConnectedAddress, connAddr),
+			eip.NewCPFItem(eip.ItemIDConnectedData, payload),
+		).Encode()
+		return header, append(make([]byte, 6), cpfData...), nil
+	}
+
+	req, err := decodeMockRequest(p.data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var resp *cip.MessageRouterResponse
+	switch req.Service {
+	case connmgr.ServiceForwardOpen, connmgr.ServiceLargeForwardOpen:
+		m.toConnID = binary.LittleEndian.Uint32(req.RequestData[6:10])
+		m.opened = true
+		data := binary.LittleEndian.AppendUint32(nil, mockOTConnID)
+		data = binary.LittleEndian.AppendUint32(data, m.toConnID)
+		data = append(data, req.RequestData[10:18]...) // Connection triad
+		data = binary.LittleEndian.AppendUint32(data, 2000000)
+		data = binary.LittleEndian.AppendUint32(data, 2000000)
+		data = append(data, 0, 0)
+		resp = &cip.MessageRouterResponse{Service: req.Service | 0x80, ResponseData: data}
+	case connmgr.ServiceForwardClose:
+		m.closed = true
+		data := append([]byte{}, req.RequestData[2:10]...)
+		resp = &cip.MessageRouterResponse{Service: req.Service | 0x80, ResponseData: append(data, 0, 0)}
+	default:
+		m.unconnected++
+		return header, encodeMockResponse(m.handlerReply(req)), nil
+	}
+	return header, encodeMockResponse(resp), nil
+}
+
+func (m *mockTarget) handlerReply(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+	resp := m.handler(req)
+	if resp.Service == 0 {
+		resp.Service = req.Service | 0x80
+	}
+	return resp
+}
+
+func TestClient_ConnectedMessaging(t *testing.T) {
+	c, m := newMockConnectedClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		if req.Service != cip.ServiceReadTag {
+			t.Errorf("service = 0x%02X, want Read Tag", req.Service)
+		}
+		return &cip.MessageRouterResponse{ResponseData: []byte{0xC4, 0x00, 0x2A, 0x00, 0x00, 0x00}}
+	})
+
+	if !m.opened {
+		t.Fatal("Forward_Open not sent")
+	}
+
+	for range 3 {
+		data, err := c.ReadTag("Counter")
+		if err != nil {
+			t.Fatalf("ReadTag() error = %v", err)
+		}
+		if len(data) != 6 || data[2] != 0x2A {
+			t.Fatalf("ReadTag() = % X", data)
+		}
+	}
+
+	if m.connected != 3 || m.unconnected != 0 {
+		t.Errorf("connected = %d, unconnected = %d, want 3 and 0", m.connected, m.unconnected)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if !m.closed {
+		t.Error("Forward_Close not sent")
+	}
+}
+
+func TestClient_ConnectedMessaging_Keepalive(t *testing.T) {
+	var keepalives atomic.Int32
+	c, m := newMockConnectedClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		if req.Service != cip.ServiceGetAttributeSingle || !bytes.Equal(req.RequestPath, cip.BuildPath(cip.ClassIdentity, 1, 1)) {
+			t.Errorf("unexpected request 0x%02X % X", req.Service, req.RequestPath)
+		}
+		keepalives.Add(1)
+		return &cip.MessageRouterResponse{ResponseData: []byte{0x01, 0x00}}
+	})
+
+	// Reopen with a short interval
+	if err := c.closeConnection(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	c.keepaliveInterval = 20 * time.Millisecond
+	if err := c.openConnection(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for keepalives.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := keepalives.Load(); n < 2 {
+		t.Fatalf("keepalives = %d, want at least 2", n)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	time.Sleep(30 * time.Millisecond) // Let a keepalive in flight finish
+	n := keepalives.Load()
+	time.Sleep(60 * time.Millisecond)
+	if got := keepalives.Load(); got != n {
+		t.Errorf("keepalives after Close = %d, want %d", got, n)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.unconnected != 0 {
+		t.Errorf("unconnected = %d, want 0", m.unconnected)
+	}
+}
+
+func TestClient_ConnectedMessaging_LargePackets(t *testing.T) {
+	c, _ := newMockConnectedClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		return &cip.MessageRouterResponse{}
+	})
+
+	// Large_Forward_Open negotiated a 4002-byte connection
+	if got, want := c.packetSize(), connmgr.MaxLargeForwardOpenSize-2; got != want {
+		t.Errorf("packetSize() = %d, want %d", got, want)
+	}
+}
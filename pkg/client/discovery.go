@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"github.com/iceisfun/goeip/pkg/cip"
 	"github.com/iceisfun/goeip/pkg/eip"
@@ -8,20 +9,165 @@ import (
 
 // ListIdentity lists the identity of the target
 func (c *Client) ListIdentity() ([]eip.ListIdentityItem, error) {
-	return c.session.ListIdentity()
+	return c.ListIdentityContext(context.Background())
+}
+
+// ListIdentityContext is like ListIdentity but gives up when ctx is done.
+func (c *Client) ListIdentityContext(ctx context.Context) ([]eip.ListIdentityItem, error) {
+	return c.session.ListIdentityContext(ctx)
 }
 
 // ListServices lists the services supported by the target
 func (c *Client) ListServices() ([]eip.ListServicesItem, error) {
-	return c.session.ListServices()
+	return c.ListServicesContext(context.Background())
+}
+
+// ListServicesContext is like ListServices but gives up when ctx is done.
+func (c *Client) ListServicesContext(ctx context.Context) ([]eip.ListServicesItem, error) {
+	return c.session.ListServicesContext(ctx)
 }
 
-// ListTags lists all tags on the PLC by iterating the Symbol Object
-func (c *Client) ListTags() ([]cip.SymbolInstance, error) {
+// ListTagsOption configures ListTags.
+type ListTagsOption func(*listTags)
+
+type listTags struct {
+	program  string
+	programs bool
+	system   bool
+}
+
+// InProgram lists the tags of a program instead of the controller-scoped
+// tags.
+func InProgram(name string) ListTagsOption {
+	return func(l *listTags) {
+		l.program = name
+	}
+}
+
+// IncludeProgramTags lists the tags of every program after the
+// controller-scoped tags. Use SymbolInstance.FullName to address them.
+func IncludeProgramTags() ListTagsOption {
+	return func(l *listTags) {
+		l.programs = true
+	}
+}
+
+// IncludeSystemTags keeps controller-internal symbols such as "__" tags,
+// modules ("Local:1:I"), tasks, maps and programs, see
+// cip.SymbolInstance.IsSystem. They are left out by default.
+func IncludeSystemTags() ListTagsOption {
+	return func(l *listTags) {
+		l.system = true
+	}
+}
+
+// ListTags lists the tags on the PLC by paging through the Symbol Object
+// with Get Instance Attribute List (0x55). Controllers without that
+// service are listed one instance at a time instead, without array
+// dimensions and external access. With ProfileOmronNJ the published
+// variables are listed instead, see TagListingTagNameServer.
+//
+// The instance IDs of the listed tags are remembered: later requests for
+// these tags address them by instance (class 0x6B, instance N) instead of
+// by name. The IDs are dropped when the controller's change counters show
+// that tags were changed or downloaded.
+func (c *Client) ListTags(opts ...ListTagsOption) ([]cip.SymbolInstance, error) {
+	return c.ListTagsContext(context.Background(), opts...)
+}
+
+// ListTagsContext is like ListTags but gives up when ctx is done.
+func (c *Client) ListTagsContext(ctx context.Context, opts ...ListTagsOption) ([]cip.SymbolInstance, error) {
+	if c.profile != nil && c.profile.TagListing == TagListingNone {
+		return nil, fmt.Errorf("list tags: %w (%s)", ErrNotSupported, c.profile.Name)
+	}
+
+	var l listTags
+	for _, opt := range opts {
+		opt(&l)
+	}
+
+	if c.variableObjects() {
+		return c.listVariables(ctx, l)
+	}
+
+	symbols, err := c.listSymbols(ctx, l.program)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []cip.SymbolInstance
+	var programs []string
+	for _, s := range symbols {
+		if l.programs && l.program == "" && s.IsProgram() {
+			programs = append(programs, s.ProgramName())
+		}
+		if l.system || !s.IsSystem() {
+			tags = append(tags, s)
+		}
+	}
+
+	for _, program := range programs {
+		symbols, err := c.listSymbols(ctx, program)
+		if err != nil {
+			return nil, fmt.Errorf("list tags of program %s: %w", program, err)
+		}
+		for _, s := range symbols {
+			if l.system || !s.IsSystem() {
+				tags = append(tags, s)
+			}
+		}
+	}
+
+	c.rememberSymbols(ctx, tags)
+	return tags, nil
+}
+
+// listSymbols returns all symbols of the controller, or of a program.
+func (c *Client) listSymbols(ctx context.Context, program string) ([]cip.SymbolInstance, error) {
+	var symbols []cip.SymbolInstance
+	start := uint32(0)
+
+	for {
+		resp, err := c.send(ctx, cip.NewGetInstanceAttributeListRequest(program, start))
+		if err != nil {
+			return nil, err
+		}
+
+		if start == 0 && program == "" && resp.GeneralStatus == cip.StatusServiceNotSupported {
+			c.logger.Infof("Get Instance Attribute List not supported, listing instances one by one")
+			return c.listSymbolsByInstance(ctx)
+		}
+
+		partial := resp.GeneralStatus == cip.StatusPartialTransfer
+		if !partial {
+			if err := resp.Error(); err != nil {
+				return nil, err
+			}
+		}
+
+		page, err := cip.DecodeGetInstanceAttributeListResponse(program, resp.ResponseData)
+		if err != nil {
+			return nil, err
+		}
+		symbols = append(symbols, page...)
+
+		if !partial {
+			return symbols, nil
+		}
+		if len(page) == 0 {
+			return nil, fmt.Errorf("list tags: partial transfer without symbols")
+		}
+		start = page[len(page)-1].InstanceID + 1
+	}
+}
+
+// listSymbolsByInstance lists the controller-scoped symbols by reading the
+// name and type of every instance up to the highest instance ID.
+func (c *Client) listSymbolsByInstance(ctx context.Context) ([]cip.SymbolInstance, error) {
 	// Step 1: Get Max Instance ID from Symbol Class (Class 0x6B, Instance 0, Attr 2)
 	// We also get Revision (Attr 1) just in case.
 	reqClass := cip.NewGetSymbolClassAttributesRequest()
-	respClass, err := c.session.SendCIPRequest(reqClass)
+	respClass, err := c.send(ctx, reqClass)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get symbol class attributes: %w", err)
 	}
@@ -49,8 +195,11 @@ func (c *Client) ListTags() ([]cip.SymbolInstance, error) {
 
 	for id := uint32(1); id <= uint32(maxInstance); id++ {
 		req := cip.NewGetSymbolAttributesRequest(id)
-		resp, err := c.session.SendCIPRequest(req)
+		resp, err := c.send(ctx, req)
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
 			// Network error, abort? or continue?
 			c.logger.Warnf("Failed to fetch attributes for instance %d: %v", id, err)
 			continue
@@ -0,0 +1,161 @@
+package client
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+type mockSymbol struct {
+	id       uint32
+	name     string
+	typeCode uint16
+}
+
+// newMockSymbolClient returns a client whose controller holds the given
+// controller-scoped and program-scoped symbols, answering Get Instance
+// Attribute List with at most pageSize symbols per reply.
+func newMockSymbolClient(t *testing.T, scopes map[string][]mockSymbol, pageSize int, requests *int) *Client {
+	return newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		if req.Service != cip.ServiceGetInstanceAttributeList {
+			return &cip.MessageRouterResponse{GeneralStatus: cip.StatusServiceNotSupported}
+		}
+		*requests++
+
+		p := req.RequestPath
+		program := ""
+		if p[0] == 0x91 {
+			n := int(p[1])
+			program = string(p[2+len("Program:") : 2+n])
+			p = p[2+n+n%2:]
+		}
+		var start uint32
+		switch p[2] {
+		case 0x24:
+			start = uint32(p[3])
+		case 0x25:
+			start = uint32(binary.LittleEndian.Uint16(p[4:]))
+		case 0x26:
+			start = binary.LittleEndian.Uint32(p[4:])
+		}
+
+		var data []byte
+		count := 0
+		status := cip.StatusSuccess
+		for _, s := range scopes[program] {
+			if s.id < start {
+				continue
+			}
+			if count == pageSize {
+				status = cip.StatusPartialTransfer
+				break
+			}
+			data = binary.LittleEndian.AppendUint32(data, s.id)
+			data = binary.LittleEndian.AppendUint16(data, uint16(len(s.name)))
+			data = append(data, s.name...)
+			data = binary.LittleEndian.AppendUint16(data, s.typeCode)
+			data = append(data, make([]byte, 12)...) // Dimensions
+			data = append(data, byte(cip.ExternalAccessReadWrite))
+			count++
+		}
+		return &cip.MessageRouterResponse{GeneralStatus: status, ResponseData: data}
+	})
+}
+
+var mockSymbols = map[string][]mockSymbol{
+	"": {
+		{1, "Speed", uint16(cip.TypeREAL)},
+		{2, "__Internal", uint16(cip.TypeDINT)},
+		{3, "Program:Main", 0x1068},
+		{9, "Count", uint16(cip.TypeDINT)},
+		{300, "Map:Local", 0x1069},
+		{301, "Total", uint16(cip.TypeLINT)},
+	},
+	"Main": {
+		{1, "Step", uint16(cip.TypeDINT)},
+	},
+}
+
+func TestClient_ListTags_Paging(t *testing.T) {
+	requests := 0
+	c := newMockSymbolClient(t, mockSymbols, 2, &requests)
+
+	tags, err := c.ListTags()
+	if err != nil {
+		t.Fatalf("ListTags() error = %v", err)
+	}
+
+	want := []string{"Speed", "Count", "Total"}
+	if len(tags) != len(want) {
+		t.Fatalf("ListTags() = %v, want %v", tags, want)
+	}
+	for i, name := range want {
+		if tags[i].FullName() != name {
+			t.Errorf("tag %d = %s, want %s", i, tags[i].FullName(), name)
+		}
+	}
+	if requests != 3 {
+		t.Errorf("%d requests, want 3 pages", requests)
+	}
+}
+
+func TestClient_ListTags_Programs(t *testing.T) {
+	requests := 0
+	c := newMockSymbolClient(t, mockSymbols, 100, &requests)
+
+	tags, err := c.ListTags(IncludeProgramTags())
+	if err != nil {
+		t.Fatalf("ListTags() error = %v", err)
+	}
+	if last := tags[len(tags)-1]; last.FullName() != "Program:Main.Step" {
+		t.Errorf("last tag = %s, want Program:Main.Step", last.FullName())
+	}
+
+	tags, err = c.ListTags(InProgram("Main"))
+	if err != nil {
+		t.Fatalf("ListTags(InProgram) error = %v", err)
+	}
+	if len(tags) != 1 || tags[0].Program != "Main" {
+		t.Errorf("ListTags(InProgram) = %+v", tags)
+	}
+
+	tags, err = c.ListTags(IncludeSystemTags())
+	if err != nil {
+		t.Fatalf("ListTags(IncludeSystemTags) error = %v", err)
+	}
+	if len(tags) != len(mockSymbols[""]) {
+		t.Errorf("ListTags(IncludeSystemTags) = %d tags, want %d", len(tags), len(mockSymbols[""]))
+	}
+}
+
+func TestClient_ListTags_FallbackPerInstance(t *testing.T) {
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		switch {
+		case req.Service == cip.ServiceGetInstanceAttributeList:
+			return &cip.MessageRouterResponse{GeneralStatus: cip.StatusServiceNotSupported}
+		case req.RequestPath[3] == 0: // Class attributes: max instance 2
+			return &cip.MessageRouterResponse{ResponseData: []byte{
+				0x02, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x00, 0x02, 0x00, 0x00, 0x00, 0x02, 0x00,
+			}}
+		default:
+			name := "A"
+			if req.RequestPath[3] == 2 {
+				name = "B"
+			}
+			return &cip.MessageRouterResponse{ResponseData: []byte{
+				0x02, 0x00,
+				0x01, 0x00, 0x00, 0x00, 0x01, 0x00, name[0],
+				0x02, 0x00, 0x00, 0x00, 0xC4, 0x00,
+			}}
+		}
+	})
+
+	tags, err := c.ListTags()
+	if err != nil {
+		t.Fatalf("ListTags() error = %v", err)
+	}
+	if len(tags) != 2 || tags[0].Name != "A" || tags[1].Name != "B" {
+		t.Errorf("ListTags() = %+v", tags)
+	}
+}
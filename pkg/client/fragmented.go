@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// defaultPacketSize is the largest CIP message a Logix controller accepts
+// over an unconnected (UCMM) request.
+const defaultPacketSize = 504
+
+// packetSize returns the maximum CIP message size usable for a request.
+func (c *Client) packetSize() int {
+	if c.maxPacketSize > 0 {
+		return c.maxPacketSize
+	}
+	if mc := c.conn.Load(); mc != nil {
+		return mc.size() - 2
+	}
+	size := defaultPacketSize
+	if c.profile != nil && c.profile.PacketSize > 0 {
+		size = c.profile.PacketSize
+	}
+	if len(c.route) > 0 {
+		return size - unconnectedSendOverhead(c.route)
+	}
+	return size
+}
+
+// ReadTagFragmented reads a tag with the Read Tag Fragmented service (0x52),
+// issuing as many requests as needed until the controller stops reporting a
+// partial transfer. The returned data has the same layout as ReadTag:
+// the type information followed by the reassembled value bytes.
+func (c *Client) ReadTagFragmented(tagName string, elements uint16) ([]byte, error) {
+	return c.ReadTagFragmentedContext(context.Background(), tagName, elements)
+}
+
+// ReadTagFragmentedContext is like ReadTagFragmented but gives up when ctx is done.
+func (c *Client) ReadTagFragmentedContext(ctx context.Context, tagName string, elements uint16) ([]byte, error) {
+	p, err := c.valuePath(ctx, tagName)
+	if err != nil {
+		return nil, err
+	}
+	return c.readFragmented(ctx, p, elements)
+}
+
+func (c *Client) readFragmented(ctx context.Context, p cip.Path, elements uint16) ([]byte, error) {
+	if err := c.supports(FeatureFragmented, "read tag fragmented"); err != nil {
+		return nil, err
+	}
+
+	var result []byte
+	var offset uint32
+
+	for {
+		req := cip.NewReadTagFragmentedRequest(p, elements, offset)
+		resp, err := c.send(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		partial := resp.GeneralStatus == cip.StatusPartialTransfer
+		if !partial {
+			if err := resp.Error(); err != nil {
+				return nil, err
+			}
+		}
+
+		typeInfo, value, err := cip.SplitReadTagResponse(resp.ResponseData)
+		if err != nil {
+			return nil, err
+		}
+
+		if result == nil {
+			result = append(result, typeInfo...)
+		}
+		result = append(result, value...)
+		offset += uint32(len(value))
+
+		if !partial {
+			return result, nil
+		}
+		if len(value) == 0 {
+			return nil, fmt.Errorf("read tag fragmented: partial transfer returned no data at offset %d", offset)
+		}
+	}
+}
+
+// WriteTagFragmented writes data to a tag with the Write Tag Fragmented
+// service (0x53), splitting it into chunks that fit in a single request.
+// data must hold the encoded value of all elements.
+func (c *Client) WriteTagFragmented(tagName string, dataType cip.DataType, elements uint16, data []byte) error {
+	return c.WriteTagFragmentedContext(context.Background(), tagName, dataType, elements, data)
+}
+
+// WriteTagFragmentedContext is like WriteTagFragmented but gives up when ctx is done.
+func (c *Client) WriteTagFragmentedContext(ctx context.Context, tagName string, dataType cip.DataType, elements uint16, data []byte) error {
+	if dataType == cip.TypeSTRUCT {
+		return fmt.Errorf("write tag fragmented %s: structures need their handle, use WriteTagFragmentedStruct", tagName)
+	}
+	p, err := c.valuePath(ctx, tagName)
+	if err != nil {
+		return err
+	}
+	return c.writeFragmented(ctx, &tagWrite{path: p, dataType: dataType, elements: elements, data: data})
+}
+
+// WriteTagFragmentedStruct writes a structure tag with the Write Tag
+// Fragmented service like WriteTagFragmented. handle is the structure
+// handle of the tag's template (see cip.Template.Handle), which the
+// controller checks against the tag before accepting the data.
+func (c *Client) WriteTagFragmentedStruct(tagName string, handle uint16, elements uint16, data []byte) error {
+	return c.WriteTagFragmentedStructContext(context.Background(), tagName, handle, elements, data)
+}
+
+// WriteTagFragmentedStructContext is like WriteTagFragmentedStruct but gives
+// up when ctx is done.
+func (c *Client) WriteTagFragmentedStructContext(ctx context.Context, tagName string, handle uint16, elements uint16, data []byte) error {
+	p, err := c.valuePath(ctx, tagName)
+	if err != nil {
+		return err
+	}
+	return c.writeFragmented(ctx, &tagWrite{path: p, dataType: cip.TypeSTRUCT, handle: handle, elements: elements, data: data})
+}
+
+func (c *Client) writeFragmented(ctx context.Context, w *tagWrite) error {
+	if err := c.supports(FeatureFragmented, "write tag fragmented"); err != nil {
+		return err
+	}
+
+	// Service (1) + Path Size (1) + Path + Type (2) + Elements (2) + Offset (4)
+	chunk := c.packetSize() - 2 - len(w.path) - 8
+	if w.dataType == cip.TypeSTRUCT {
+		chunk -= 2 // Structure handle
+	}
+	// Keep fragments aligned so no element straddles two requests
+	chunk -= chunk % 8
+	if chunk <= 0 {
+		return fmt.Errorf("write tag fragmented: path too long for packet size %d", c.packetSize())
+	}
+
+	for offset := 0; offset < len(w.data) || offset == 0; offset += chunk {
+		end := min(offset+chunk, len(w.data))
+
+		resp, err := c.send(ctx, w.fragment(offset, w.data[offset:end]))
+		if err != nil {
+			return err
+		}
+		if err := resp.Error(); err != nil {
+			return err
+		}
+
+		if end == len(w.data) {
+			break
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+func TestClient_ReadTagFragmented(t *testing.T) {
+	// 2000 REALs = 8000 bytes, served 480 bytes at a time
+	value := make([]byte, 8000)
+	for i := range value {
+		value[i] = byte(i)
+	}
+
+	requests := 0
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		requests++
+		if req.Service != cip.ServiceReadTagFragmented {
+			t.Errorf("service = 0x%02X, want 0x52", req.Service)
+		}
+		elements := binary.LittleEndian.Uint16(req.RequestData[0:2])
+		if elements != 2000 {
+			t.Errorf("elements = %d, want 2000", elements)
+		}
+		offset := int(binary.LittleEndian.Uint32(req.RequestData[2:6]))
+
+		end := min(offset+480, len(value))
+		status := cip.StatusSuccess
+		if end < len(value) {
+			status = cip.StatusPartialTransfer
+		}
+		return &cip.MessageRouterResponse{
+			GeneralStatus: status,
+			ResponseData:  append([]byte{0xCA, 0x00}, value[offset:end]...),
+		}
+	})
+
+	data, err := c.ReadTagFragmented("Recipe", 2000)
+	if err != nil {
+		t.Fatalf("ReadTagFragmented() error = %v", err)
+	}
+
+	if !bytes.Equal(data[:2], []byte{0xCA, 0x00}) {
+		t.Errorf("type = %X, want CA00", data[:2])
+	}
+	if !bytes.Equal(data[2:], value) {
+		t.Errorf("reassembled data mismatch (len %d, want %d)", len(data)-2, len(value))
+	}
+	if requests != 17 {
+		t.Errorf("requests = %d, want 17", requests)
+	}
+}
+
+func TestClient_ReadTagFragmented_Struct(t *testing.T) {
+	value := make([]byte, 600)
+	for i := range value {
+		value[i] = byte(i * 3)
+	}
+
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		offset := int(binary.LittleEndian.Uint32(req.RequestData[2:6]))
+		end := min(offset+400, len(value))
+		status := cip.StatusSuccess
+		if end < len(value) {
+			status = cip.StatusPartialTransfer
+		}
+		// Structure type: 0x02A0 followed by the structure handle
+		return &cip.MessageRouterResponse{
+			GeneralStatus: status,
+			ResponseData:  append([]byte{0xA0, 0x02, 0x34, 0x12}, value[offset:end]...),
+		}
+	})
+
+	data, err := c.ReadTagFragmented("BigUDT", 1)
+	if err != nil {
+		t.Fatalf("ReadTagFragmented() error = %v", err)
+	}
+	if !bytes.Equal(data[:4], []byte{0xA0, 0x02, 0x34, 0x12}) {
+		t.Errorf("type info = %X, want A0023412", data[:4])
+	}
+	if !bytes.Equal(data[4:], value) {
+		t.Error("reassembled structure data mismatch")
+	}
+}
+
+func TestClient_ReadTag_FallsBackToFragmented(t *testing.T) {
+	value := make([]byte, 700)
+	for i := range value {
+		value[i] = byte(i)
+	}
+
+	var services []cip.USINT
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		services = append(services, req.Service)
+		offset := 0
+		if req.Service == cip.ServiceReadTagFragmented {
+			offset = int(binary.LittleEndian.Uint32(req.RequestData[2:6]))
+		}
+		end := min(offset+480, len(value))
+		status := cip.StatusSuccess
+		if end < len(value) {
+			status = cip.StatusPartialTransfer
+		}
+		return &cip.MessageRouterResponse{
+			GeneralStatus: status,
+			ResponseData:  append([]byte{0xC4, 0x00}, value[offset:end]...),
+		}
+	})
+
+	data, err := c.ReadTag("Big")
+	if err != nil {
+		t.Fatalf("ReadTag() error = %v", err)
+	}
+	if !bytes.Equal(data[2:], value) {
+		t.Error("ReadTag() did not reassemble fragmented data")
+	}
+	if len(services) != 3 || services[0] != cip.ServiceReadTag || services[1] != cip.ServiceReadTagFragmented {
+		t.Errorf("services = %X, want [4C 52 52]", services)
+	}
+}
+
+func TestClient_ReadTagFragmented_Error(t *testing.T) {
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		return &cip.MessageRouterResponse{GeneralStatus: cip.StatusPathDestinationUnknown}
+	})
+
+	if _, err := c.ReadTagFragmented("Missing", 1); err == nil {
+		t.Fatal("ReadTagFragmented() expected error")
+	}
+}
+
+func TestClient_WriteTagFragmented(t *testing.T) {
+	value := make([]byte, 1200)
+	for i := range value {
+		value[i] = byte(i * 7)
+	}
+
+	written := make([]byte, len(value))
+	requests := 0
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		requests++
+		if req.Service != cip.ServiceWriteTagFragmented {
+			t.Errorf("service = 0x%02X, want 0x53", req.Service)
+		}
+		if dt := cip.DataType(binary.LittleEndian.Uint16(req.RequestData[0:2])); dt != cip.TypeREAL {
+			t.Errorf("data type = %s, want REAL", dt)
+		}
+		if n := binary.LittleEndian.Uint16(req.RequestData[2:4]); n != 300 {
+			t.Errorf("elements = %d, want 300", n)
+		}
+		offset := binary.LittleEndian.Uint32(req.RequestData[4:8])
+		if offset%8 != 0 {
+			t.Errorf("offset %d not aligned", offset)
+		}
+		copy(written[offset:], req.RequestData[8:])
+		return &cip.MessageRouterResponse{}
+	})
+
+	if err := c.WriteTagFragmented("Recipe", cip.TypeREAL, 300, value); err != nil {
+		t.Fatalf("WriteTagFragmented() error = %v", err)
+	}
+	if !bytes.Equal(written, value) {
+		t.Error("written data mismatch")
+	}
+	if requests < 3 {
+		t.Errorf("requests = %d, want at least 3", requests)
+	}
+}
+
+func TestClient_WriteTagFragmentedStruct(t *testing.T) {
+	value := make([]byte, 600)
+	for i := range value {
+		value[i] = byte(i * 3)
+	}
+
+	written := make([]byte, len(value))
+	requests := 0
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		requests++
+		if req.Service != cip.ServiceWriteTagFragmented {
+			t.Errorf("service = 0x%02X, want 0x53", req.Service)
+		}
+		if want := []byte{0xA0, 0x02, 0xCE, 0x0F}; !bytes.Equal(req.RequestData[0:4], want) {
+			t.Errorf("type info = % X, want % X", req.RequestData[0:4], want)
+		}
+		if n := binary.LittleEndian.Uint16(req.RequestData[4:6]); n != 1 {
+			t.Errorf("elements = %d, want 1", n)
+		}
+		offset := binary.LittleEndian.Uint32(req.RequestData[6:10])
+		copy(written[offset:], req.RequestData[10:])
+		return &cip.MessageRouterResponse{}
+	})
+
+	if err := c.WriteTagFragmentedStruct("Recipe", 0x0FCE, 1, value); err != nil {
+		t.Fatalf("WriteTagFragmentedStruct() error = %v", err)
+	}
+	if !bytes.Equal(written, value) {
+		t.Error("written data mismatch")
+	}
+	if requests < 2 {
+		t.Errorf("requests = %d, want at least 2", requests)
+	}
+
+	if err := c.WriteTagFragmented("Recipe", cip.TypeSTRUCT, 1, value); err == nil {
+		t.Error("expected error for a structure written without its handle")
+	}
+}
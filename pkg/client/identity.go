@@ -0,0 +1,29 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/iceisfun/goeip/pkg/objects/identity"
+)
+
+// Identity reads the Identity Object (class 0x01) of the target with
+// Get_Attributes_All. Unlike ListIdentity it goes through the route path,
+// so it describes the controller rather than the Ethernet module, and its
+// status word tells whether the controller is faulted and, on Logix, the
+// key switch position.
+func (c *Client) Identity() (*identity.Identity, error) {
+	return c.IdentityContext(context.Background())
+}
+
+// IdentityContext is like Identity but gives up when ctx is done.
+func (c *Client) IdentityContext(ctx context.Context) (*identity.Identity, error) {
+	resp, err := c.send(ctx, identity.NewGetAttributesAllRequest())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read identity: %w", err)
+	}
+	if err := resp.Error(); err != nil {
+		return nil, fmt.Errorf("failed to read identity: %w", err)
+	}
+	return identity.DecodeGetAttributesAllResponse(resp.ResponseData)
+}
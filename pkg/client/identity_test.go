@@ -0,0 +1,29 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+	"github.com/iceisfun/goeip/pkg/objects/identity"
+)
+
+func TestIdentity(t *testing.T) {
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		if req.Service != cip.ServiceGetAttributeAll {
+			return &cip.MessageRouterResponse{GeneralStatus: cip.StatusServiceNotSupported}
+		}
+		return &cip.MessageRouterResponse{ResponseData: []byte{
+			0x01, 0x00, 0x0E, 0x00, 0xA6, 0x00, 0x21, 0x0B, 0x70, 0x24,
+			0x78, 0x56, 0x34, 0x12, 0x04, 'L', '8', '3', 'E',
+		}}
+	})
+
+	id, err := c.Identity()
+	if err != nil {
+		t.Fatalf("Identity() error = %v", err)
+	}
+	if id.ProductName != "L83E" || id.Status.KeySwitch() != identity.KeySwitchProgram ||
+		id.Status.Extended() != identity.ExtStatusIOIdle || !id.Status.MajorFault() {
+		t.Errorf("got %+v", id)
+	}
+}
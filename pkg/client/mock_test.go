@@ -0,0 +1,128 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/iceisfun/goeip/internal"
+	"github.com/iceisfun/goeip/pkg/cip"
+	"github.com/iceisfun/goeip/pkg/eip"
+	"github.com/iceisfun/goeip/pkg/session"
+)
+
+// cipHandler answers a decoded CIP request with a CIP reply.
+type cipHandler func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse
+
+// newMockCIPClient returns a Client whose transport answers every SendRRData
+// with the reply produced by handler.
+func newMockCIPClient(t *testing.T, handler cipHandler) *Client {
+	t.Helper()
+
+	var mu sync.Mutex
+	var pending [][]byte
+
+	mt := &MockTransport{
+		sendFunc: func(cmd eip.Command, data []byte, sessionHandle eip.SessionHandle) error {
+			if cmd != eip.CommandSendRRData {
+				return nil
+			}
+			mu.Lock()
+			pending = append(pending, data)
+			mu.Unlock()
+			return nil
+		},
+		receiveFunc: func() (*eip.EncapsulationHeader, []byte, error) {
+			mu.Lock()
+			if len(pending) == 0 {
+				mu.Unlock()
+				return nil, nil, fmt.Errorf("no pending request")
+			}
+			data := pending[0]
+			pending = pending[1:]
+			mu.Unlock()
+
+			req, err := decodeMockRequest(data)
+			if err != nil {
+				t.Errorf("mock: failed to decode request: %v", err)
+				return nil, nil, err
+			}
+
+			resp := handler(req)
+			if resp.Service == 0 {
+				resp.Service = req.Service | 0x80
+			}
+
+			respData := encodeMockResponse(resp)
+			return &eip.EncapsulationHeader{
+				Command: eip.CommandSendRRData,
+				Length:  uint16(len(respData)),
+				Status:  eip.StatusSuccess,
+			}, respData, nil
+		},
+	}
+
+	s := session.NewSession(mt, internal.NopLogger())
+	return &Client{session: s, logger: internal.NopLogger()}
+}
+
+// decodeMockRequest extracts the Message Router request from SendRRData data.
+func decodeMockRequest(data []byte) (*cip.MessageRouterRequest, error) {
+	if len(data) < 6 {
+		return nil, fmt.Errorf("short RRData")
+	}
+	cpf, err := eip.DecodeCommonPacketFormat(data[6:])
+	if err != nil {
+		return nil, err
+	}
+	item := cpf.FindItemByType(eip.ItemIDUnconnectedMessage)
+	if item == nil {
+		return nil, fmt.Errorf("missing unconnected message item")
+	}
+	return decodeMockCIPRequest(item.Data)
+}
+
+// decodeMockCIPRequest decodes an encoded Message Router request.
+func decodeMockCIPRequest(data []byte) (*cip.MessageRouterRequest, error) {
+	if len(data) < 2 {
+		return nil, fmt.Errorf("short CIP request")
+	}
+	pathLen := int(data[1]) * 2
+	if len(data) < 2+pathLen {
+		return nil, fmt.Errorf("short CIP request path")
+	}
+	return &cip.MessageRouterRequest{
+		Service:     cip.USINT(data[0]),
+		RequestPath: cip.Path(data[2 : 2+pathLen]),
+		RequestData: data[2+pathLen:],
+	}, nil
+}
+
+// encodeMockResponse wraps a Message Router reply into SendRRData data.
+func encodeMockResponse(resp *cip.MessageRouterResponse) []byte {
+	cpf := eip.NewCommonPacketFormat(
+		eip.NewCPFItem(eip.ItemIDNullAddress, nil),
+		eip.NewCPFItem(eip.ItemIDUnconnectedMessage, encodeMockCIPResponse(resp)),
+	)
+	cpfData, _ := cpf.Encode()
+
+	out := make([]byte, 6+len(cpfData))
+	copy(out[6:], cpfData)
+	return out
+}
+
+// encodeMockCIPResponse encodes a Message Router reply.
+func encodeMockCIPResponse(resp *cip.MessageRouterResponse) []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(byte(resp.Service))
+	buf.WriteByte(0)
+	buf.WriteByte(byte(resp.GeneralStatus))
+	buf.WriteByte(byte(len(resp.ExtStatus)))
+	for _, ext := range resp.ExtStatus {
+		binary.Write(buf, binary.LittleEndian, ext)
+	}
+	buf.Write(resp.ResponseData)
+	return buf.Bytes()
+}
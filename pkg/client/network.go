@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+	"github.com/iceisfun/goeip/pkg/objects/ethlink"
+	"github.com/iceisfun/goeip/pkg/objects/tcpip"
+)
+
+// ReadTCPIPInterface reads the IP configuration, host name and address
+// conflict detection state of the target from the TCP/IP Interface Object
+// (class 0xF5).
+func (c *Client) ReadTCPIPInterface() (*tcpip.Interface, error) {
+	return c.ReadTCPIPInterfaceContext(context.Background())
+}
+
+// ReadTCPIPInterfaceContext is like ReadTCPIPInterface but gives up when
+// ctx is done.
+func (c *Client) ReadTCPIPInterfaceContext(ctx context.Context) (*tcpip.Interface, error) {
+	iface := &tcpip.Interface{}
+	err := c.getAttributes(ctx, tcpip.NewGetAttributeRequest,
+		[]cip.UINT{tcpip.AttrStatus, tcpip.AttrInterfaceConfig},
+		[]cip.UINT{tcpip.AttrConfigCapability, tcpip.AttrConfigControl, tcpip.AttrHostName, tcpip.AttrSelectACD},
+		func(attr cip.UINT, data []byte) error { return tcpip.DecodeAttribute(iface, attr, data) })
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TCP/IP interface: %w", err)
+	}
+	return iface, nil
+}
+
+// ReadEthernetLink reads the speed, duplex and counters of an Ethernet port
+// from the Ethernet Link Object (class 0xF6). Ports are numbered from 1.
+// Counters the device does not support are left nil.
+func (c *Client) ReadEthernetLink(instance uint16) (*ethlink.Link, error) {
+	return c.ReadEthernetLinkContext(context.Background(), instance)
+}
+
+// ReadEthernetLinkContext is like ReadEthernetLink but gives up when ctx
+// is done.
+func (c *Client) ReadEthernetLinkContext(ctx context.Context, instance uint16) (*ethlink.Link, error) {
+	link := &ethlink.Link{Instance: instance}
+	newReq := func(attr cip.UINT) *cip.MessageRouterRequest {
+		return ethlink.NewGetAttributeRequest(instance, attr)
+	}
+	err := c.getAttributes(ctx, newReq,
+		[]cip.UINT{ethlink.AttrInterfaceSpeed, ethlink.AttrInterfaceFlags, ethlink.AttrPhysicalAddress},
+		[]cip.UINT{ethlink.AttrInterfaceCounters, ethlink.AttrMediaCounters, ethlink.AttrInterfaceLabel},
+		func(attr cip.UINT, data []byte) error { return ethlink.DecodeAttribute(link, attr, data) })
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Ethernet link %d: %w", instance, err)
+	}
+	return link, nil
+}
+
+// getAttributes reads attributes with Get_Attribute_Single requests, sent
+// as one batch, and passes each value to decode. Optional attributes the
+// target does not return are skipped; a missing required one is an error.
+func (c *Client) getAttributes(ctx context.Context, newReq func(cip.UINT) *cip.MessageRouterRequest,
+	required, optional []cip.UINT, decode func(cip.UINT, []byte) error) error {
+	attrs := append(append([]cip.UINT{}, required...), optional...)
+	reqs := make([]*cip.MessageRouterRequest, len(attrs))
+	for i, attr := range attrs {
+		reqs[i] = newReq(attr)
+	}
+
+	resps, errs, err := c.sendBatch(ctx, reqs)
+	if err != nil {
+		return err
+	}
+
+	for i, attr := range attrs {
+		err := errs[i]
+		if err == nil {
+			err = resps[i].Error()
+		}
+		if err == nil {
+			err = decode(attr, resps[i].ResponseData)
+		}
+		if err != nil {
+			if i >= len(required) {
+				continue
+			}
+			return fmt.Errorf("attribute %d: %w", attr, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+	"github.com/iceisfun/goeip/pkg/objects/ethlink"
+)
+
+// attributeHandler answers Get_Attribute_Single requests, packed in Multiple
+// Service Packets, from values keyed by class and attribute.
+func attributeHandler(t *testing.T, values map[[2]byte][]byte) cipHandler {
+	return func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		if req.Service != cip.ServiceMultipleServicePacket {
+			return &cip.MessageRouterResponse{GeneralStatus: cip.StatusServiceNotSupported}
+		}
+		embedded := decodeMSPRequest(t, req.RequestData)
+		status := cip.StatusSuccess
+		replies := make([]*cip.MessageRouterResponse, len(embedded))
+		for i, r := range embedded {
+			p := r.RequestPath // 0x20 class 0x24 instance 0x30 attribute
+			value, ok := values[[2]byte{p[1], p[5]}]
+			if !ok {
+				status = cip.StatusEmbeddedServiceError
+				replies[i] = &cip.MessageRouterResponse{Service: 0x8E, GeneralStatus: cip.StatusAttributeNotSupported}
+				continue
+			}
+			replies[i] = &cip.MessageRouterResponse{Service: 0x8E, ResponseData: value}
+		}
+		return &cip.MessageRouterResponse{GeneralStatus: status, ResponseData: encodeMSPResponse(replies)}
+	}
+}
+
+func TestReadTCPIPInterface(t *testing.T) {
+	config := []byte{
+		0x0A, 0x01, 0xA8, 0xC0, 0x00, 0xFF, 0xFF, 0xFF, 0x01, 0x01, 0xA8, 0xC0,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	}
+	values := map[[2]byte][]byte{
+		{0xF5, 1}: {0x01, 0, 0, 0},
+		{0xF5, 5}: config,
+		{0xF5, 6}: {0x03, 0x00, 'p', 'l', 'c', 0x00},
+	}
+	c := newMockCIPClient(t, attributeHandler(t, values))
+
+	iface, err := c.ReadTCPIPInterface()
+	if err != nil {
+		t.Fatalf("ReadTCPIPInterface() error = %v", err)
+	}
+	if iface.Config.IP.String() != "192.168.1.10" || iface.HostName != "plc" || iface.HasACD {
+		t.Errorf("got %+v", iface)
+	}
+
+	delete(values, [2]byte{0xF5, 5})
+	if _, err := c.ReadTCPIPInterface(); !errors.Is(err, cip.ErrAttributeNotSupported) {
+		t.Errorf("ReadTCPIPInterface() error = %v, want attribute not supported", err)
+	}
+}
+
+func TestReadEthernetLink(t *testing.T) {
+	media := make([]byte, 48)
+	binary.LittleEndian.PutUint32(media[4:], 17)
+	c := newMockCIPClient(t, attributeHandler(t, map[[2]byte][]byte{
+		{0xF6, 1}: {100, 0, 0, 0},
+		{0xF6, 2}: {0x0F, 0, 0, 0},
+		{0xF6, 3}: {0x00, 0x00, 0xBC, 0x12, 0x34, 0x56},
+		{0xF6, 5}: media,
+	}))
+
+	link, err := c.ReadEthernetLink(1)
+	if err != nil {
+		t.Fatalf("ReadEthernetLink() error = %v", err)
+	}
+	if link.Speed != 100 || !link.Flags.FullDuplex() || link.Flags.Negotiation() != ethlink.NegotiationSuccess {
+		t.Errorf("got %+v", link)
+	}
+	if link.Interface != nil || link.Media == nil || link.Media.FCSErrors != 17 {
+		t.Errorf("Interface = %+v, Media = %+v", link.Interface, link.Media)
+	}
+}
@@ -0,0 +1,165 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/iceisfun/goeip/pkg/objects/connmgr"
+)
+
+// ErrNotSupported is returned for operations the controller profile of the
+// client does not support.
+var ErrNotSupported = errors.New("not supported by the controller profile")
+
+// TagListing selects how ListTags enumerates the tags of a controller.
+type TagListing int
+
+const (
+	// TagListingSymbolObject pages through the instances of the Logix
+	// Symbol Object (class 0x6B).
+	TagListingSymbolObject TagListing = iota
+	// TagListingNone means the controller cannot list its tags.
+	TagListingNone
+	// TagListingTagNameServer lists the variables registered with the
+	// Omron Tag Name Server (class 0x6A) and reads their types from the
+	// Variable Object (class 0x6B). Structures are described by the
+	// Variable Type Object (class 0x6C) instead of the Template Object.
+	TagListingTagNameServer
+)
+
+// Feature is a set of optional services and storage conventions a
+// controller supports.
+type Feature uint32
+
+const (
+	// FeatureFragmented is Read/Write Tag Fragmented (0x52/0x53), used for
+	// values that do not fit in one packet.
+	FeatureFragmented Feature = 1 << iota
+	// FeatureReadModifyWrite is Read-Modify-Write Tag (0x4E), used to write
+	// single bits.
+	FeatureReadModifyWrite
+	// FeatureMultipleService is Multiple Service Packet (0x0A), used by
+	// ReadTags and WriteTags to pack requests. Without it the requests
+	// are sent one by one.
+	FeatureMultipleService
+	// FeaturePackedBoolArrays means BOOL arrays are stored as DWORDs, 32
+	// elements each, so "Flags[37]" is bit 5 of the array's second DWORD.
+	// Without it arrays of DWORD type are ordinary DWORD arrays.
+	FeaturePackedBoolArrays
+)
+
+// Profile holds the defaults and capabilities of a controller family.
+// Start from one of the built-in profiles and adjust it rather than filling
+// in a Profile from scratch; the zero value supports no optional features.
+type Profile struct {
+	Name string
+
+	// RoutePath is the route from the device at the client's address to the
+	// controller, see WithRoutePath. Empty for controllers with their own
+	// Ethernet port.
+	RoutePath string
+
+	// ConnectedMessaging opens a class 3 connection, see
+	// WithConnectedMessaging, of ConnectionSize bytes. Sizes above 511
+	// bytes need Large_Forward_Open.
+	ConnectedMessaging bool
+	ConnectionSize     int
+
+	// PacketSize is the largest unconnected request the controller accepts.
+	// Zero selects 504 bytes.
+	PacketSize int
+
+	TagListing TagListing
+	Features   Feature
+}
+
+// Has reports whether the profile supports all of the features f.
+func (p *Profile) Has(f Feature) bool {
+	return p.Features&f == f
+}
+
+// logixFeatures are the optional services of Logix 5000 controllers.
+const logixFeatures = FeatureFragmented | FeatureReadModifyWrite | FeatureMultipleService | FeaturePackedBoolArrays
+
+// Built-in controller profiles.
+var (
+	// ProfileControlLogix is a ControlLogix controller in slot 0 of the
+	// chassis of the Ethernet module the client connects to.
+	ProfileControlLogix = Profile{
+		Name:               "ControlLogix",
+		RoutePath:          "1,0",
+		ConnectedMessaging: true,
+		ConnectionSize:     connmgr.MaxLargeForwardOpenSize,
+		TagListing:         TagListingSymbolObject,
+		Features:           logixFeatures,
+	}
+
+	// ProfileCompactLogix is a CompactLogix controller. The embedded
+	// Ethernet port reaches the controller over the virtual backplane.
+	// Older models without Large_Forward_Open fall back to 511 bytes.
+	ProfileCompactLogix = Profile{
+		Name:               "CompactLogix",
+		RoutePath:          "1,0",
+		ConnectedMessaging: true,
+		ConnectionSize:     connmgr.MaxLargeForwardOpenSize,
+		TagListing:         TagListingSymbolObject,
+		Features:           logixFeatures,
+	}
+
+	// ProfileMicro800 is a Micro800 controller. It is addressed directly,
+	// lists controller-scope tags only and has no Read-Modify-Write Tag
+	// service.
+	ProfileMicro800 = Profile{
+		Name:           "Micro800",
+		ConnectionSize: connmgr.MaxForwardOpenSize,
+		TagListing:     TagListingSymbolObject,
+		Features:       FeatureFragmented | FeatureMultipleService,
+	}
+
+	// ProfileOmronNJ is an Omron NJ/NX controller, addressed directly over
+	// a connection of up to 1994 bytes. Published variables are listed
+	// through the Tag Name Server, and structure variables are read and
+	// written member by member with the layout from the Variable Type
+	// Object (see ReadVariableType).
+	ProfileOmronNJ = Profile{
+		Name:               "Omron NJ/NX",
+		ConnectedMessaging: true,
+		ConnectionSize:     1994,
+		PacketSize:         502,
+		TagListing:         TagListingTagNameServer,
+		Features:           FeatureMultipleService,
+	}
+)
+
+// WithProfile applies the defaults of a controller profile: its route,
+// connected messaging and packet sizes. Operations the profile does not
+// support fail with ErrNotSupported instead of being sent. Options given
+// after WithProfile override its defaults.
+func WithProfile(p Profile) ClientOption {
+	return func(c *Client) {
+		c.profile = &p
+		c.connected = p.ConnectedMessaging
+		c.connectionSize = p.ConnectionSize
+		WithRoutePath(p.RoutePath)(c)
+	}
+}
+
+// Profile returns the controller profile of the client, or nil if none was
+// set, in which case all services are used.
+func (c *Client) Profile() *Profile {
+	return c.profile
+}
+
+// has reports whether the controller supports feature f.
+func (c *Client) has(f Feature) bool {
+	return c.profile == nil || c.profile.Has(f)
+}
+
+// supports returns an ErrNotSupported error naming op if the controller
+// lacks feature f.
+func (c *Client) supports(f Feature, op string) error {
+	if c.has(f) {
+		return nil
+	}
+	return fmt.Errorf("%s: %w (%s)", op, ErrNotSupported, c.profile.Name)
+}
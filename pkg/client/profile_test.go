@@ -0,0 +1,87 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+func TestWithProfile(t *testing.T) {
+	c := &Client{}
+	WithProfile(ProfileControlLogix)(c)
+
+	if !c.connected || c.connectionSize != 4002 {
+		t.Errorf("connected = %v, connectionSize = %d, want true, 4002", c.connected, c.connectionSize)
+	}
+	if !bytes.Equal(c.route, []byte{0x01, 0x00}) {
+		t.Errorf("route = % X, want 01 00", []byte(c.route))
+	}
+
+	// Later options override the profile
+	WithRoutePath("1,4")(c)
+	if !bytes.Equal(c.route, []byte{0x01, 0x04}) {
+		t.Errorf("route = % X, want 01 04", []byte(c.route))
+	}
+
+	c = &Client{}
+	WithProfile(ProfileMicro800)(c)
+	if c.connected || len(c.route) != 0 {
+		t.Errorf("Micro800: connected = %v, route = % X, want direct unconnected", c.connected, []byte(c.route))
+	}
+}
+
+func TestProfile_Unsupported(t *testing.T) {
+	sent := 0
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		sent++
+		return &cip.MessageRouterResponse{}
+	})
+	c.profile = &ProfileOmronNJ
+
+	if _, err := c.ReadTemplate(0x0100); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("ReadTemplate() error = %v, want ErrNotSupported", err)
+	}
+	if err := c.WriteBit("Status", 3, true); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("WriteBit() error = %v, want ErrNotSupported", err)
+	}
+	if _, err := c.ReadTagFragmented("Recipe", 100); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("ReadTagFragmented() error = %v, want ErrNotSupported", err)
+	}
+	if sent != 0 {
+		t.Errorf("%d requests sent, want none", sent)
+	}
+	if got := c.packetSize(); got != 502 {
+		t.Errorf("packetSize() = %d, want 502", got)
+	}
+}
+
+func TestProfile_NoMultipleService(t *testing.T) {
+	var services []cip.USINT
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		services = append(services, req.Service)
+		return &cip.MessageRouterResponse{ResponseData: []byte{0xC4, 0x00, 0x01, 0x00, 0x00, 0x00}}
+	})
+	p := ProfileMicro800
+	p.Features &^= FeatureMultipleService
+	c.profile = &p
+
+	results, err := c.ReadTags("A", "B", "C")
+	if err != nil {
+		t.Fatalf("ReadTags() error = %v", err)
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("%s: %v", r.Name, r.Err)
+		}
+	}
+	if len(services) != 3 {
+		t.Fatalf("%d requests sent, want 3", len(services))
+	}
+	for _, s := range services {
+		if s != cip.ServiceReadTag {
+			t.Errorf("service = 0x%02X, want Read Tag", s)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
@@ -18,6 +19,7 @@ type ReconnectingClient struct {
 	address string
 	logger  internal.Logger
 	factory ClientFactory
+	options []ClientOption
 
 	mu     sync.RWMutex
 	client *Client
@@ -56,6 +58,14 @@ func WithAutoConnect(b bool) ReconnectOption {
 	}
 }
 
+// WithClientOptions sets the options of the clients created by the default
+// client factory.
+func WithClientOptions(opts ...ClientOption) ReconnectOption {
+	return func(rc *ReconnectingClient) {
+		rc.options = opts
+	}
+}
+
 // WithClientFactory sets a custom client factory (mainly for testing).
 func WithClientFactory(f ClientFactory) ReconnectOption {
 	return func(rc *ReconnectingClient) {
@@ -72,12 +82,15 @@ func NewReconnectingClient(address string, logger internal.Logger, opts ...Recon
 	rc := &ReconnectingClient{
 		address:     address,
 		logger:      logger,
-		factory:     NewClient, // Default to standard NewClient
 		maxRetries:  3,
 		retryDelay:  1 * time.Second,
 		autoConnect: true,
 	}
 
+	rc.factory = func(address string, logger internal.Logger) (*Client, error) {
+		return NewClient(address, logger, rc.options...) // Default to standard NewClient
+	}
+
 	for _, opt := range opts {
 		opt(rc)
 	}
@@ -156,31 +169,74 @@ func (rc *ReconnectingClient) Close() error {
 
 // ReadTag reads a tag with automatic reconnection.
 func (rc *ReconnectingClient) ReadTag(name string) ([]byte, error) {
-	return rc.executeWithRetry(func(c *Client) ([]byte, error) {
-		return c.ReadTag(name)
+	return rc.ReadTagContext(context.Background(), name)
+}
+
+// ReadTagContext is like ReadTag but stops retrying when ctx is done.
+func (rc *ReconnectingClient) ReadTagContext(ctx context.Context, name string) ([]byte, error) {
+	return rc.executeWithRetry(ctx, func(c *Client) ([]byte, error) {
+		return c.ReadTagContext(ctx, name)
+	})
+}
+
+// ReadTagArray reads a range of array elements with automatic reconnection.
+func (rc *ReconnectingClient) ReadTagArray(name string, start uint32, count uint16) ([]byte, error) {
+	return rc.ReadTagArrayContext(context.Background(), name, start, count)
+}
+
+// ReadTagArrayContext is like ReadTagArray but stops retrying when ctx is done.
+func (rc *ReconnectingClient) ReadTagArrayContext(ctx context.Context, name string, start uint32, count uint16) ([]byte, error) {
+	return rc.executeWithRetry(ctx, func(c *Client) ([]byte, error) {
+		return c.ReadTagArrayContext(ctx, name, start, count)
 	})
 }
 
 // WriteTag writes a tag with automatic reconnection.
 func (rc *ReconnectingClient) WriteTag(name string, value any) error {
-	_, err := rc.executeWithRetry(func(c *Client) ([]byte, error) {
-		return nil, c.WriteTag(name, value)
+	return rc.WriteTagContext(context.Background(), name, value)
+}
+
+// WriteTagContext is like WriteTag but stops retrying when ctx is done.
+func (rc *ReconnectingClient) WriteTagContext(ctx context.Context, name string, value any) error {
+	_, err := rc.executeWithRetry(ctx, func(c *Client) ([]byte, error) {
+		return nil, c.WriteTagContext(ctx, name, value)
+	})
+	return err
+}
+
+// WriteBit sets or clears one bit of a tag with automatic reconnection.
+func (rc *ReconnectingClient) WriteBit(name string, bit int, value bool) error {
+	return rc.WriteBitContext(context.Background(), name, bit, value)
+}
+
+// WriteBitContext is like WriteBit but stops retrying when ctx is done.
+func (rc *ReconnectingClient) WriteBitContext(ctx context.Context, name string, bit int, value bool) error {
+	_, err := rc.executeWithRetry(ctx, func(c *Client) ([]byte, error) {
+		return nil, c.WriteBitContext(ctx, name, bit, value)
 	})
 	return err
 }
 
 // executeWithRetry runs an operation, reconnecting on failure.
-func (rc *ReconnectingClient) executeWithRetry(op func(*Client) ([]byte, error)) ([]byte, error) {
+// It gives up as soon as ctx is done.
+func (rc *ReconnectingClient) executeWithRetry(ctx context.Context, op func(*Client) ([]byte, error)) ([]byte, error) {
 	var lastErr error
 
 	for i := 0; rc.maxRetries < 0 || i <= rc.maxRetries; i++ {
+		if err := ctx.Err(); err != nil {
+			if lastErr == nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: %w", err, lastErr)
+		}
+
 		// 1. Get functional client
 		client, err := rc.getClient()
 		if err != nil {
 			lastErr = err
 			// Backoff before retry loop continues
 			if rc.maxRetries < 0 || i < rc.maxRetries {
-				time.Sleep(rc.retryDelay)
+				sleepContext(ctx, rc.retryDelay)
 			}
 			continue
 		}
@@ -192,6 +248,11 @@ func (rc *ReconnectingClient) executeWithRetry(op func(*Client) ([]byte, error))
 		}
 		lastErr = err
 
+		// The session survives an aborted request, keep the connection
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
 		// 3. Handle failure
 		limitStr := fmt.Sprintf("%d", rc.maxRetries+1)
 		if rc.maxRetries < 0 {
@@ -211,7 +272,7 @@ func (rc *ReconnectingClient) executeWithRetry(op func(*Client) ([]byte, error))
 		rc.mu.Unlock()
 
 		if rc.maxRetries < 0 || i < rc.maxRetries {
-			time.Sleep(rc.retryDelay)
+			sleepContext(ctx, rc.retryDelay)
 		}
 
 		// Prevent integer overflow for long-running infinite retries
@@ -222,3 +283,13 @@ func (rc *ReconnectingClient) executeWithRetry(op func(*Client) ([]byte, error))
 
 	return nil, fmt.Errorf("max retries exceeded: %w", lastErr)
 }
+
+// sleepContext pauses for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+	}
+}
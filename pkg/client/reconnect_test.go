@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -220,3 +221,28 @@ func TestReconnectingClient_Reconnect(t *testing.T) {
 		t.Errorf("Expected 3 client creations, got %d", clientCount)
 	}
 }
+
+func TestReconnectingClient_ContextStopsRetry(t *testing.T) {
+	factory := func(addr string, l internal.Logger) (*Client, error) {
+		mockT := &MockTransport{
+			sendFunc: func(cmd eip.Command, data []byte, sessionHandle eip.SessionHandle) error {
+				return fmt.Errorf("fail")
+			},
+		}
+		return &Client{session: session.NewSession(mockT, l), logger: l}, nil
+	}
+
+	rc, _ := NewReconnectingClient("addr", nil,
+		WithClientFactory(factory),
+		WithMaxRetries(-1),
+		WithRetryDelay(time.Hour),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := rc.ReadTagContext(ctx, "foo")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ReadTagContext() error = %v, want deadline exceeded", err)
+	}
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+	"github.com/iceisfun/goeip/pkg/objects/connmgr"
+)
+
+// unwrapMockUnconnectedSend splits Unconnected Send request data into the
+// embedded request and the route.
+func unwrapMockUnconnectedSend(t *testing.T, data []byte) (*cip.MessageRouterRequest, cip.Path) {
+	t.Helper()
+
+	size := int(binary.LittleEndian.Uint16(data[2:4]))
+	req, err := decodeMockCIPRequest(data[4 : 4+size])
+	if err != nil {
+		t.Fatalf("mock: embedded request: %v", err)
+	}
+	rest := data[4+size+size%2:]
+	return req, cip.Path(rest[2 : 2+int(rest[0])*2])
+}
+
+func TestClient_RoutePath(t *testing.T) {
+	var route cip.Path
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		if req.Service != connmgr.ServiceUnconnectedSend {
+			t.Errorf("service = 0x%02X, want Unconnected Send", req.Service)
+			return &cip.MessageRouterResponse{GeneralStatus: cip.StatusServiceNotSupported}
+		}
+		embedded, r := unwrapMockUnconnectedSend(t, req.RequestData)
+		route = r
+		if embedded.Service != cip.ServiceReadTag {
+			t.Errorf("embedded service = 0x%02X, want Read Tag", embedded.Service)
+		}
+		// Targets answer with the reply of the embedded request
+		return &cip.MessageRouterResponse{
+			Service:      embedded.Service | 0x80,
+			ResponseData: []byte{0xC4, 0x00, 0x2A, 0x00, 0x00, 0x00},
+		}
+	})
+	WithRoutePath("1,0,2,192.168.2.10,1,0")(c)
+
+	data, err := c.ReadTag("Counter")
+	if err != nil {
+		t.Fatalf("ReadTag() error = %v", err)
+	}
+	if len(data) != 6 || data[2] != 0x2A {
+		t.Errorf("ReadTag() = % X", data)
+	}
+
+	want := append(append([]byte{0x01, 0x00, 0x12, 0x0C}, "192.168.2.10"...), 0x01, 0x00)
+	if !bytes.Equal(route, want) {
+		t.Errorf("route = % X, want % X", []byte(route), want)
+	}
+}
+
+func TestClient_RoutePath_RouteError(t *testing.T) {
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		return &cip.MessageRouterResponse{
+			GeneralStatus: connmgr.StatusConnectionFailure,
+			ExtStatus:     []cip.UINT{connmgr.ExtStatusUnconnectedSendTimeout},
+			ResponseData:  []byte{0x01, 0x00},
+		}
+	})
+	WithRoutePath("1,3")(c)
+
+	_, err := c.ReadTag("Counter")
+	var routeErr *connmgr.RouteError
+	if !errors.As(err, &routeErr) {
+		t.Fatalf("ReadTag() error = %v, want *connmgr.RouteError", err)
+	}
+	if routeErr.RemainingPathSize != 1 {
+		t.Errorf("RemainingPathSize = %d, want 1", routeErr.RemainingPathSize)
+	}
+}
+
+func TestClient_RoutePath_Invalid(t *testing.T) {
+	if _, err := NewClient("127.0.0.1:1", nil, WithRoutePath("1")); err == nil {
+		t.Fatal("expected error for invalid route")
+	}
+}
+
+func TestClient_RoutePath_ConnectionPath(t *testing.T) {
+	c := &Client{}
+	WithRoutePath("1,3")(c)
+
+	want := []byte{0x01, 0x03, 0x20, 0x02, 0x24, 0x01}
+	if got := c.messageRouterPath(); !bytes.Equal(got, want) {
+		t.Errorf("messageRouterPath() = % X, want % X", []byte(got), want)
+	}
+	if got, want := c.packetSize(), defaultPacketSize-15; got != want {
+		t.Errorf("packetSize() = %d, want %d", got, want)
+	}
+}
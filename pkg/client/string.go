@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// newStringWrite encodes s in the string type of the tag. The tag is read
+// first to learn whether it is an elementary string or a string structure,
+// and which structure.
+func (c *Client) newStringWrite(ctx context.Context, tagName string, s string) (*tagWrite, error) {
+	tp, err := parseValueTag(tagName)
+	if err != nil {
+		return nil, err
+	}
+	p := c.tagPath(ctx, tp)
+
+	current, err := c.readTag(ctx, p, 1)
+	if err != nil {
+		return nil, err
+	}
+	typeInfo, value, err := cip.SplitReadTagResponse(current)
+	if err != nil {
+		return nil, err
+	}
+
+	dataType := cip.DataType(binary.LittleEndian.Uint16(typeInfo))
+	if cip.IsStringType(dataType) {
+		data, err := cip.EncodeString(dataType, s)
+		if err != nil {
+			return nil, err
+		}
+		return &tagWrite{path: p, dataType: dataType, elements: 1, data: data}, nil
+	}
+	if dataType != cip.TypeSTRUCT {
+		return nil, fmt.Errorf("tag %s is not a string: type %s", tagName, dataType)
+	}
+
+	if _, err := cip.DecodeString(cip.TypeSTRUCT, value); err != nil {
+		return nil, fmt.Errorf("tag %s does not hold a string: %w", tagName, err)
+	}
+	program, _, _ := tp.Symbol()
+	st, err := c.stringType(ctx, program, binary.LittleEndian.Uint16(typeInfo[2:]))
+	if err != nil {
+		return nil, fmt.Errorf("tag %s: %w", tagName, err)
+	}
+	data, err := st.Encode(s)
+	if err != nil {
+		return nil, err
+	}
+	return &tagWrite{path: p, dataType: cip.TypeSTRUCT, handle: st.Handle, elements: 1, data: data}, nil
+}
+
+// stringType resolves the string structure with the given handle: the
+// predefined STRING, or the string type described by its template.
+func (c *Client) stringType(ctx context.Context, program string, handle uint16) (cip.StringType, error) {
+	if handle == cip.StringHandle {
+		return cip.LogixString, nil
+	}
+
+	t, err := c.templateByHandle(ctx, program, handle)
+	if err != nil {
+		return cip.StringType{}, err
+	}
+	st, ok := cip.StringTypeFromTemplate(t)
+	if !ok {
+		return cip.StringType{}, fmt.Errorf("structure %s is not a string type", t.Name)
+	}
+	return st, nil
+}
+
+// templateByHandle finds the template of the structure with the given
+// handle. The handle is a checksum of the layout rather than the template
+// instance, so unless the template was read before, the structures used by
+// the tags of the program (or of the controller when program is empty) are
+// read, nested ones included, until one matches.
+func (c *Client) templateByHandle(ctx context.Context, program string, handle uint16) (*cip.Template, error) {
+	c.refreshSymbols(ctx)
+
+	c.templateMu.Lock()
+	for _, t := range c.templates {
+		if t.Handle == handle {
+			c.templateMu.Unlock()
+			return t, nil
+		}
+	}
+	c.templateMu.Unlock()
+
+	if c.profile != nil && c.profile.TagListing != TagListingSymbolObject {
+		return nil, fmt.Errorf("structure 0x%04X: find template: list tags %w (%s)", handle, ErrNotSupported, c.profile.Name)
+	}
+	symbols, err := c.listSymbols(ctx, program)
+	if err != nil {
+		return nil, fmt.Errorf("structure 0x%04X: %w", handle, err)
+	}
+
+	var queue []uint16
+	for _, s := range symbols {
+		if s.IsStructure() {
+			queue = append(queue, s.TemplateID())
+		}
+	}
+
+	seen := make(map[uint16]bool)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		t, err := c.ReadTemplateContext(ctx, id)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			c.logger.Debugf("Skipping template %d: %v", id, err)
+			continue
+		}
+		if t.Handle == handle {
+			return t, nil
+		}
+		for _, m := range t.Members {
+			if m.IsStructure() {
+				queue = append(queue, m.TemplateID())
+			}
+		}
+	}
+	return nil, fmt.Errorf("no template found for structure 0x%04X", handle)
+}
@@ -0,0 +1,201 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// logixStringReply encodes a Read Tag reply for a string structure.
+func logixStringReply(st cip.StringType, s string) []byte {
+	data, _ := st.Encode(s)
+	return append([]byte{0xA0, 0x02, byte(st.Handle), byte(st.Handle >> 8)}, data...)
+}
+
+func TestClient_WriteTag_String(t *testing.T) {
+	str20 := cip.StringType{Handle: 0x5A5A, Capacity: 20}
+
+	tests := []struct {
+		name      string
+		current   []byte
+		templates map[uint16]*cip.Template
+		value     string
+		wantType  []byte // Type information in the Write Tag request
+		wantData  []byte
+		wantErr   bool
+	}{
+		{
+			name:     "STRING",
+			current:  logixStringReply(cip.LogixString, "old"),
+			value:    "hello",
+			wantType: []byte{0xA0, 0x02, 0xCE, 0x0F},
+			wantData: func() []byte { b, _ := cip.LogixString.Encode("hello"); return b }(),
+		},
+		{
+			name:    "STRING20 from template",
+			current: logixStringReply(str20, ""),
+			templates: map[uint16]*cip.Template{0x0321: {
+				InstanceID: 0x0321,
+				Name:       "STRING20",
+				Handle:     0x5A5A,
+				Members: []cip.TemplateMember{
+					{Name: "LEN", TypeCode: uint16(cip.TypeDINT), Offset: 0},
+					{Name: "DATA", Info: 20, TypeCode: 0x2000 | uint16(cip.TypeSINT), Offset: 4},
+				},
+			}},
+			value:    "hi",
+			wantType: []byte{0xA0, 0x02, 0x5A, 0x5A},
+			wantData: func() []byte { b, _ := str20.Encode("hi"); return b }(),
+		},
+		{
+			name:    "unknown structure",
+			current: logixStringReply(str20, ""),
+			value:   "hi",
+			wantErr: true,
+		},
+		{
+			name:    "too long",
+			current: logixStringReply(str20, ""),
+			value:   string(make([]byte, 30)),
+			wantErr: true,
+		},
+		{
+			name:     "SHORT_STRING",
+			current:  []byte{0xDA, 0x00, 0x01, 'x'},
+			value:    "abc",
+			wantType: []byte{0xDA, 0x00},
+			wantData: []byte{0x03, 'a', 'b', 'c'},
+		},
+		{
+			name:    "not a string",
+			current: []byte{0xC4, 0x00, 0x01, 0x00, 0x00, 0x00},
+			value:   "abc",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var written []byte
+			c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+				switch req.Service {
+				case cip.ServiceReadTag:
+					return &cip.MessageRouterResponse{ResponseData: tt.current}
+				case cip.ServiceWriteTag:
+					written = req.RequestData
+					return &cip.MessageRouterResponse{}
+				case cip.ServiceGetInstanceAttributeList:
+					// No tags, so no templates to search
+					return &cip.MessageRouterResponse{}
+				}
+				t.Errorf("unexpected service 0x%02X", req.Service)
+				return &cip.MessageRouterResponse{GeneralStatus: cip.StatusServiceNotSupported}
+			})
+			c.templates = tt.templates
+
+			err := c.WriteTag("Msg", tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WriteTag() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			n := len(tt.wantType)
+			if !bytes.Equal(written[:n], tt.wantType) {
+				t.Errorf("type info = % X, want % X", written[:n], tt.wantType)
+			}
+			if elements := binary.LittleEndian.Uint16(written[n:]); elements != 1 {
+				t.Errorf("elements = %d, want 1", elements)
+			}
+			if !bytes.Equal(written[n+2:], tt.wantData) {
+				t.Errorf("data = % X, want % X", written[n+2:], tt.wantData)
+			}
+		})
+	}
+}
+
+func TestClient_WriteTag_StringTemplateSearch(t *testing.T) {
+	// The STRING20 template is not cached: it is found through the Recipe
+	// tag, whose structure holds a STRING20 member.
+	str20 := cip.StringType{Handle: 0x5A5A, Capacity: 20}
+	templates := map[uint16]mockTemplate{
+		0x0300: {
+			handle: 0x3333,
+			name:   "Recipe",
+			size:   28,
+			members: []cip.TemplateMember{
+				{Name: "Name", TypeCode: 0x8321, Offset: 0},
+			},
+		},
+		0x0321: {
+			handle: 0x5A5A,
+			name:   "STRING20",
+			size:   24,
+			members: []cip.TemplateMember{
+				{Name: "LEN", TypeCode: uint16(cip.TypeDINT), Offset: 0},
+				{Name: "DATA", Info: 20, TypeCode: 0x2000 | uint16(cip.TypeSINT), Offset: 4},
+			},
+		},
+	}
+
+	var written []byte
+	c := newMockCIPClient(t, templateHandler(t, templates, 200, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		switch req.Service {
+		case cip.ServiceGetInstanceAttributeList:
+			var data []byte
+			data = binary.LittleEndian.AppendUint32(data, 1)
+			data = binary.LittleEndian.AppendUint16(data, 6)
+			data = append(data, "Recipe"...)
+			data = binary.LittleEndian.AppendUint16(data, 0x8300)
+			data = append(data, make([]byte, 13)...)
+			return &cip.MessageRouterResponse{ResponseData: data}
+		case cip.ServiceReadTag:
+			return &cip.MessageRouterResponse{ResponseData: logixStringReply(str20, "")}
+		case cip.ServiceWriteTag:
+			written = req.RequestData
+			return &cip.MessageRouterResponse{}
+		}
+		t.Errorf("unexpected service 0x%02X", req.Service)
+		return &cip.MessageRouterResponse{GeneralStatus: cip.StatusServiceNotSupported}
+	}))
+
+	if err := c.WriteTag("Recipe.Name", "hi"); err != nil {
+		t.Fatalf("WriteTag() error = %v", err)
+	}
+	want, _ := str20.Encode("hi")
+	if !bytes.Equal(written[:4], []byte{0xA0, 0x02, 0x5A, 0x5A}) || !bytes.Equal(written[6:], want) {
+		t.Errorf("written = % X", written)
+	}
+}
+
+func TestClient_ReadTagInto_String(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply []byte
+		want  string
+	}{
+		{"STRING", logixStringReply(cip.LogixString, "hello"), "hello"},
+		{"STRING20", logixStringReply(cip.StringType{Handle: 0x5A5A, Capacity: 20}, "abc"), "abc"},
+		{"SHORT_STRING", []byte{0xDA, 0x00, 0x02, 'o', 'k'}, "ok"},
+		{"STRING2", []byte{0xD5, 0x00, 0x01, 0x00, 'z', 0x00}, "z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+				return &cip.MessageRouterResponse{ResponseData: tt.reply}
+			})
+
+			var s string
+			if err := c.ReadTagInto("Msg", &s); err != nil {
+				t.Fatalf("ReadTagInto() error = %v", err)
+			}
+			if s != tt.want {
+				t.Errorf("ReadTagInto() = %q, want %q", s, tt.want)
+			}
+		})
+	}
+}
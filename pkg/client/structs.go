@@ -0,0 +1,206 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// ReadControl reads a CONTROL tag from the PLC and decodes it.
+func (c *Client) ReadControl(tagName string) (*cip.Control, error) {
+	return c.ReadControlContext(context.Background(), tagName)
+}
+
+// ReadControlContext is like ReadControl but gives up when ctx is done.
+func (c *Client) ReadControlContext(ctx context.Context, tagName string) (*cip.Control, error) {
+	v := &cip.Control{}
+	if err := c.readPredefined(ctx, tagName, "CONTROL", v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// ReadSerialPortControl reads a SERIAL_PORT_CONTROL tag from the PLC and
+// decodes it.
+func (c *Client) ReadSerialPortControl(tagName string) (*cip.SerialPortControl, error) {
+	return c.ReadSerialPortControlContext(context.Background(), tagName)
+}
+
+// ReadSerialPortControlContext is like ReadSerialPortControl but gives up
+// when ctx is done.
+func (c *Client) ReadSerialPortControlContext(ctx context.Context, tagName string) (*cip.SerialPortControl, error) {
+	v := &cip.SerialPortControl{}
+	if err := c.readPredefined(ctx, tagName, "SERIAL_PORT_CONTROL", v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// ReadPID reads a PID tag from the PLC and decodes it.
+func (c *Client) ReadPID(tagName string) (*cip.PID, error) {
+	return c.ReadPIDContext(context.Background(), tagName)
+}
+
+// ReadPIDContext is like ReadPID but gives up when ctx is done.
+func (c *Client) ReadPIDContext(ctx context.Context, tagName string) (*cip.PID, error) {
+	v := &cip.PID{}
+	if err := c.readPredefined(ctx, tagName, "PID", v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// ReadMotionInstruction reads a MOTION_INSTRUCTION tag from the PLC and
+// decodes it.
+func (c *Client) ReadMotionInstruction(tagName string) (*cip.MotionInstruction, error) {
+	return c.ReadMotionInstructionContext(context.Background(), tagName)
+}
+
+// ReadMotionInstructionContext is like ReadMotionInstruction but gives up
+// when ctx is done.
+func (c *Client) ReadMotionInstructionContext(ctx context.Context, tagName string) (*cip.MotionInstruction, error) {
+	v := &cip.MotionInstruction{}
+	if err := c.readPredefined(ctx, tagName, "MOTION_INSTRUCTION", v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// readPredefined reads a tag holding the predefined structure typeName into
+// dst. The structure handle of the reply is checked against the template
+// of that name, found like string templates (see templateByHandle), so a
+// tag of another type returns an error wrapping a *cip.TypeMismatchError.
+func (c *Client) readPredefined(ctx context.Context, tagName, typeName string, dst cip.Unmarshaler) error {
+	tp, err := parseValueTag(tagName)
+	if err != nil {
+		return err
+	}
+	data, err := c.ReadTagContext(ctx, tagName)
+	if err != nil {
+		return err
+	}
+	typeInfo, value, err := cip.SplitReadTagResponse(data)
+	if err != nil {
+		return err
+	}
+
+	dataType, handle := cip.SplitTypeInfo(typeInfo)
+	mismatch := &cip.TypeMismatchError{DataType: dataType, Handle: handle, GoType: reflect.TypeOf(dst).Elem()}
+	if dataType != cip.TypeSTRUCT {
+		return fmt.Errorf("tag %q: %w", tagName, mismatch)
+	}
+	program, _, _ := tp.Symbol()
+	t, err := c.templateByHandle(ctx, program, handle)
+	if err != nil {
+		return fmt.Errorf("tag %q: %w", tagName, err)
+	}
+	if t.Name != typeName {
+		return fmt.Errorf("tag %q is a %s: %w", tagName, t.Name, mismatch)
+	}
+	return dst.UnmarshalCIP(value)
+}
+
+// ReadAlarmDigital reads an ALARM_DIGITAL tag from the PLC. Alarm
+// structures are decoded by member name with the layout of template
+// templateID (see cip.SymbolInstance.TemplateID), like ReadTagStruct.
+func (c *Client) ReadAlarmDigital(tagName string, templateID uint16) (*cip.AlarmDigital, error) {
+	return c.ReadAlarmDigitalContext(context.Background(), tagName, templateID)
+}
+
+// ReadAlarmDigitalContext is like ReadAlarmDigital but gives up when ctx
+// is done.
+func (c *Client) ReadAlarmDigitalContext(ctx context.Context, tagName string, templateID uint16) (*cip.AlarmDigital, error) {
+	v := &cip.AlarmDigital{}
+	if err := c.readMembers(ctx, tagName, templateID, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// ReadAlarmAnalog reads an ALARM_ANALOG tag from the PLC, decoded with the
+// layout of template templateID like ReadAlarmDigital.
+func (c *Client) ReadAlarmAnalog(tagName string, templateID uint16) (*cip.AlarmAnalog, error) {
+	return c.ReadAlarmAnalogContext(context.Background(), tagName, templateID)
+}
+
+// ReadAlarmAnalogContext is like ReadAlarmAnalog but gives up when ctx is
+// done.
+func (c *Client) ReadAlarmAnalogContext(ctx context.Context, tagName string, templateID uint16) (*cip.AlarmAnalog, error) {
+	v := &cip.AlarmAnalog{}
+	if err := c.readMembers(ctx, tagName, templateID, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// ReadMessage reads a MESSAGE tag from the PLC, decoded with the layout of
+// template templateID like ReadAlarmDigital.
+func (c *Client) ReadMessage(tagName string, templateID uint16) (*cip.Message, error) {
+	return c.ReadMessageContext(context.Background(), tagName, templateID)
+}
+
+// ReadMessageContext is like ReadMessage but gives up when ctx is done.
+func (c *Client) ReadMessageContext(ctx context.Context, tagName string, templateID uint16) (*cip.Message, error) {
+	v := &cip.Message{}
+	if err := c.readMembers(ctx, tagName, templateID, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// WriteAlarmDigital writes v to an ALARM_DIGITAL tag, member by member
+// with the layout of template templateID like WriteTagStruct. Every member
+// v has a field for is written, so v is usually a value read with
+// ReadAlarmDigital and then changed.
+func (c *Client) WriteAlarmDigital(tagName string, templateID uint16, v *cip.AlarmDigital) error {
+	return c.WriteAlarmDigitalContext(context.Background(), tagName, templateID, v)
+}
+
+// WriteAlarmDigitalContext is like WriteAlarmDigital but gives up when ctx
+// is done.
+func (c *Client) WriteAlarmDigitalContext(ctx context.Context, tagName string, templateID uint16, v *cip.AlarmDigital) error {
+	return c.writeMembers(ctx, tagName, templateID, v)
+}
+
+// WriteAlarmAnalog writes v to an ALARM_ANALOG tag like WriteAlarmDigital.
+func (c *Client) WriteAlarmAnalog(tagName string, templateID uint16, v *cip.AlarmAnalog) error {
+	return c.WriteAlarmAnalogContext(context.Background(), tagName, templateID, v)
+}
+
+// WriteAlarmAnalogContext is like WriteAlarmAnalog but gives up when ctx
+// is done.
+func (c *Client) WriteAlarmAnalogContext(ctx context.Context, tagName string, templateID uint16, v *cip.AlarmAnalog) error {
+	return c.writeMembers(ctx, tagName, templateID, v)
+}
+
+// WriteMessage writes v to a MESSAGE tag like WriteAlarmDigital.
+func (c *Client) WriteMessage(tagName string, templateID uint16, v *cip.Message) error {
+	return c.WriteMessageContext(context.Background(), tagName, templateID, v)
+}
+
+// WriteMessageContext is like WriteMessage but gives up when ctx is done.
+func (c *Client) WriteMessageContext(ctx context.Context, tagName string, templateID uint16, v *cip.Message) error {
+	return c.writeMembers(ctx, tagName, templateID, v)
+}
+
+// writeMembers writes the fields of src to the members of a structure tag
+// with template templateID.
+func (c *Client) writeMembers(ctx context.Context, tagName string, templateID uint16, src any) error {
+	members, err := cip.EncodeMembers(src)
+	if err != nil {
+		return err
+	}
+	return c.WriteTagStructContext(ctx, tagName, templateID, members)
+}
+
+// readMembers reads a structure tag with template templateID and fills
+// dst from its members by name.
+func (c *Client) readMembers(ctx context.Context, tagName string, templateID uint16, dst any) error {
+	members, err := c.ReadTagStructContext(ctx, tagName, templateID)
+	if err != nil {
+		return err
+	}
+	return cip.DecodeMembers(members, dst)
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+func TestClient_ReadPID(t *testing.T) {
+	value := make([]byte, 184)
+	binary.LittleEndian.PutUint32(value[0:4], 1<<cip.PIDStatusEN)
+	binary.LittleEndian.PutUint32(value[4:8], math.Float32bits(75))
+
+	templates := map[uint16]mockTemplate{
+		0x0300: {handle: 0x1234, name: "PID", size: 184, members: []cip.TemplateMember{
+			{Name: "CTL", TypeCode: uint16(cip.TypeDINT), Offset: 0},
+		}},
+		0x0301: {handle: 0x5678, name: "CONTROL", size: 12, members: []cip.TemplateMember{
+			{Name: "CTL", TypeCode: uint16(cip.TypeDINT), Offset: 0},
+		}},
+	}
+	// Loop1 is a PID, Loop2 a CONTROL and Loop3 a DINT
+	replies := map[string][]byte{
+		"Loop1": append([]byte{0xA0, 0x02, 0x34, 0x12}, value...),
+		"Loop2": append([]byte{0xA0, 0x02, 0x78, 0x56}, make([]byte, 12)...),
+		"Loop3": {0xC4, 0x00, 0, 0, 0, 0},
+	}
+	listings := 0
+	c := newMockCIPClient(t, templateHandler(t, templates, 200, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		switch req.Service {
+		case cip.ServiceGetInstanceAttributeList:
+			listings++
+			var data []byte
+			for i, name := range []string{"Loop1", "Loop2"} {
+				data = binary.LittleEndian.AppendUint32(data, uint32(i+1))
+				data = binary.LittleEndian.AppendUint16(data, uint16(len(name)))
+				data = append(data, name...)
+				data = binary.LittleEndian.AppendUint16(data, 0x8300+uint16(i))
+				data = append(data, make([]byte, 13)...)
+			}
+			return &cip.MessageRouterResponse{ResponseData: data}
+		case cip.ServiceReadTag:
+			return &cip.MessageRouterResponse{ResponseData: replies[tagNameOf(req.RequestPath)]}
+		}
+		t.Errorf("unexpected service 0x%02X", req.Service)
+		return &cip.MessageRouterResponse{GeneralStatus: cip.StatusServiceNotSupported}
+	}))
+
+	for range 2 {
+		pid, err := c.ReadPID("Loop1")
+		if err != nil {
+			t.Fatalf("ReadPID() error = %v", err)
+		}
+		if !pid.EN || pid.SP != 75 {
+			t.Errorf("ReadPID() = %+v", pid)
+		}
+	}
+	if listings != 1 {
+		t.Errorf("tag listings = %d, want 1: the PID template should be cached", listings)
+	}
+
+	for _, tag := range []string{"Loop2", "Loop3"} {
+		var mismatch *cip.TypeMismatchError
+		if _, err := c.ReadPID(tag); !errors.As(err, &mismatch) {
+			t.Errorf("ReadPID(%s) error = %v, want a type mismatch", tag, err)
+		}
+	}
+	if _, err := c.ReadControl("Loop2"); err != nil {
+		t.Errorf("ReadControl() error = %v", err)
+	}
+}
+
+func TestClient_ReadAlarmDigital(t *testing.T) {
+	templates := map[uint16]mockTemplate{
+		0x0300: {
+			handle: 0x3333,
+			name:   "ALARM_DIGITAL",
+			size:   8,
+			members: []cip.TemplateMember{
+				{Name: "ZZZZZZZZZZALARM_DIG0", TypeCode: uint16(cip.TypeDINT), Offset: 0},
+				{Name: "InAlarm", Info: 1, TypeCode: uint16(cip.TypeBOOL), Offset: 0},
+				{Name: "Acked", Info: 2, TypeCode: uint16(cip.TypeBOOL), Offset: 0},
+				{Name: "Severity", TypeCode: uint16(cip.TypeDINT), Offset: 4},
+			},
+		},
+	}
+	value := []byte{0x02, 0, 0, 0, 0xF4, 0x01, 0, 0}
+
+	c := newMockCIPClient(t, templateHandler(t, templates, 200, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		return &cip.MessageRouterResponse{ResponseData: append([]byte{0xA0, 0x02, 0x33, 0x33}, value...)}
+	}))
+
+	alarm, err := c.ReadAlarmDigital("HighLevel", 0x0300)
+	if err != nil {
+		t.Fatalf("ReadAlarmDigital() error = %v", err)
+	}
+	if !alarm.InAlarm || alarm.Acked || alarm.Severity != 500 {
+		t.Errorf("ReadAlarmDigital() = %+v", alarm)
+	}
+}
+
+func TestClient_WriteAlarmDigital(t *testing.T) {
+	templates := map[uint16]mockTemplate{
+		0x0300: {
+			handle: 0x3333,
+			name:   "ALARM_DIGITAL",
+			size:   12,
+			members: []cip.TemplateMember{
+				{Name: "ZZZZZZZZZZALARM_DIG0", TypeCode: uint16(cip.TypeDINT), Offset: 0},
+				{Name: "InAlarm", Info: 1, TypeCode: uint16(cip.TypeBOOL), Offset: 0},
+				{Name: "Acked", Info: 2, TypeCode: uint16(cip.TypeBOOL), Offset: 0},
+				{Name: "Severity", TypeCode: uint16(cip.TypeDINT), Offset: 4},
+				{Name: "Reserved", TypeCode: uint16(cip.TypeDINT), Offset: 8},
+			},
+		},
+	}
+	// Reserved has no field in AlarmDigital and must be written back as read
+	value := []byte{0x80, 0, 0, 0, 0xF4, 0x01, 0, 0, 0x11, 0x22, 0x33, 0x44}
+
+	var written []byte
+	c := newMockCIPClient(t, templateHandler(t, templates, 200, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		switch req.Service {
+		case cip.ServiceReadTag:
+			return &cip.MessageRouterResponse{ResponseData: append([]byte{0xA0, 0x02, 0x33, 0x33}, value...)}
+		case cip.ServiceWriteTag:
+			written = req.RequestData
+			return &cip.MessageRouterResponse{}
+		}
+		t.Errorf("unexpected service 0x%02X", req.Service)
+		return &cip.MessageRouterResponse{GeneralStatus: cip.StatusServiceNotSupported}
+	}))
+
+	alarm, err := c.ReadAlarmDigital("HighLevel", 0x0300)
+	if err != nil {
+		t.Fatalf("ReadAlarmDigital() error = %v", err)
+	}
+	alarm.Acked = true
+	alarm.Severity = 750
+	if err := c.WriteAlarmDigital("HighLevel", 0x0300, alarm); err != nil {
+		t.Fatalf("WriteAlarmDigital() error = %v", err)
+	}
+
+	want := []byte{0xA0, 0x02, 0x33, 0x33, 0x01, 0x00, 0x84, 0, 0, 0, 0xEE, 0x02, 0, 0, 0x11, 0x22, 0x33, 0x44}
+	if !bytes.Equal(written, want) {
+		t.Errorf("written = % X, want % X", written, want)
+	}
+}
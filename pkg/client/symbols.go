@@ -0,0 +1,214 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// defaultSymbolCheckInterval is how long cached symbol instance IDs and
+// templates are used before the controller's change counters are checked
+// again.
+const defaultSymbolCheckInterval = 5 * time.Second
+
+// symbolCache maps tag names to Symbol Object instance IDs, so requests can
+// address tags by instance instead of by name. It is filled by ListTags
+// and emptied when the controller's change counters change, as they do
+// after a download. The client's template cache is checked against the
+// same counters, since template IDs and layouts change with a download too.
+type symbolCache struct {
+	mu       sync.Mutex
+	ids      map[string]uint32       // Upper-case full tag name
+	types    map[string]cip.DataType // Array data types, by symbolic path
+	counters []byte                  // Change counters when ids, types or templates were read
+	checked  time.Time
+}
+
+// WithSymbolCheckInterval sets how often the controller's change counters
+// are read while instance IDs learned from ListTags or cached templates are
+// in use. Default is 5 seconds.
+func WithSymbolCheckInterval(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.symbolCheckInterval = d
+	}
+}
+
+func symbolKey(program, name string) string {
+	if program != "" {
+		return strings.ToUpper("Program:" + program + "." + name)
+	}
+	return strings.ToUpper(name)
+}
+
+// InvalidateSymbols forgets the symbol instance IDs learned from ListTags,
+// so tags are addressed by name until ListTags is called again, along with
+// the cached structure templates and array types.
+func (c *Client) InvalidateSymbols() {
+	c.symbols.mu.Lock()
+	defer c.symbols.mu.Unlock()
+	c.symbols.ids = nil
+	c.symbols.types = nil
+	c.symbols.counters = nil
+	c.dropTemplates()
+}
+
+// dropTemplates empties the template and variable type caches.
+func (c *Client) dropTemplates() {
+	c.templateMu.Lock()
+	defer c.templateMu.Unlock()
+	c.templates = nil
+	c.variableTypes = nil
+}
+
+// rememberSymbols adds the instance IDs of tags to the cache. Nothing is
+// cached if the change counters cannot be read, since stale IDs could not
+// be detected.
+func (c *Client) rememberSymbols(ctx context.Context, tags []cip.SymbolInstance) {
+	counters, err := c.readChangeCounters(ctx)
+	if err != nil {
+		c.logger.Debugf("Not caching symbol instances: %v", err)
+		return
+	}
+
+	sc := &c.symbols
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if sc.counters != nil && !bytes.Equal(sc.counters, counters) {
+		c.dropTemplates()
+		sc.ids = nil
+		sc.types = nil
+	}
+	if sc.ids == nil {
+		sc.ids = make(map[string]uint32, len(tags))
+	}
+	sc.counters = counters
+	for _, t := range tags {
+		sc.ids[symbolKey(t.Program, t.Name)] = t.InstanceID
+	}
+	sc.checked = time.Now()
+}
+
+// lookupSymbol returns the cached instance ID of a tag, checking the change
+// counters first if they have not been checked recently.
+func (c *Client) lookupSymbol(ctx context.Context, program, name string) (uint32, bool) {
+	sc := &c.symbols
+	sc.mu.Lock()
+	empty := len(sc.ids) == 0
+	sc.mu.Unlock()
+	if empty {
+		return 0, false
+	}
+
+	c.refreshSymbols(ctx)
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	id, ok := sc.ids[symbolKey(program, name)]
+	return id, ok
+}
+
+// trackChanges records the change counters the first template or array
+// type was read under, unless symbols or templates are already tracked.
+// Templates are still cached on controllers without change counters, but
+// are then only dropped by InvalidateSymbols.
+func (c *Client) trackChanges(ctx context.Context) {
+	sc := &c.symbols
+	sc.mu.Lock()
+	tracked := sc.counters != nil
+	sc.mu.Unlock()
+	if tracked {
+		return
+	}
+
+	counters, err := c.readChangeCounters(ctx)
+	if err != nil {
+		c.logger.Debugf("Not tracking tag database changes: %v", err)
+		return
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if sc.counters == nil {
+		sc.counters = counters
+		sc.checked = time.Now()
+	}
+}
+
+// refreshSymbols checks the change counters if the cached symbols and
+// templates have not been checked recently.
+func (c *Client) refreshSymbols(ctx context.Context) {
+	interval := c.symbolCheckInterval
+	if interval == 0 {
+		interval = defaultSymbolCheckInterval
+	}
+
+	sc := &c.symbols
+	sc.mu.Lock()
+	stale := sc.counters != nil && time.Since(sc.checked) >= interval
+	sc.mu.Unlock()
+
+	if stale {
+		c.checkSymbols(ctx)
+	}
+}
+
+// checkSymbols empties the symbol and template caches if the change
+// counters changed or cannot be read.
+func (c *Client) checkSymbols(ctx context.Context) {
+	counters, err := c.readChangeCounters(ctx)
+
+	sc := &c.symbols
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	switch {
+	case err != nil:
+		c.logger.Warnf("Dropping cached symbol instances and templates: %v", err)
+	case !bytes.Equal(counters, sc.counters):
+		c.logger.Infof("Tag database changed, dropping cached symbol instances and templates")
+	default:
+		sc.checked = time.Now()
+		return
+	}
+	sc.ids = nil
+	sc.types = nil
+	sc.counters = nil
+	c.dropTemplates()
+}
+
+func (c *Client) readChangeCounters(ctx context.Context) ([]byte, error) {
+	resp, err := c.send(ctx, cip.NewGetChangeCountersRequest())
+	if err != nil {
+		return nil, err
+	}
+	if err := resp.Error(); err != nil {
+		return nil, err
+	}
+	return resp.ResponseData, nil
+}
+
+// tagPath encodes a tag expression, addressing the tag by its instance ID
+// when it is known.
+func (c *Client) tagPath(ctx context.Context, tp *cip.TagPath) cip.Path {
+	if program, name, ok := tp.Symbol(); ok {
+		if id, ok := c.lookupSymbol(ctx, program, name); ok {
+			return tp.InstancePath(id)
+		}
+	}
+	return tp.Path()
+}
+
+// valuePath parses a tag expression that must address a whole value, i.e.
+// one without a bit selector, and encodes it with tagPath.
+func (c *Client) valuePath(ctx context.Context, tagName string) (cip.Path, error) {
+	tp, err := parseValueTag(tagName)
+	if err != nil {
+		return nil, err
+	}
+	return c.tagPath(ctx, tp), nil
+}
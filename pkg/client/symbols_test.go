@@ -0,0 +1,112 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// mockSymbolController answers tag listing, change counter and Read Tag
+// requests, recording the path of each Read Tag.
+type mockSymbolController struct {
+	counters  []byte // nil: change counters not supported
+	readPaths []cip.Path
+}
+
+func (m *mockSymbolController) handle(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+	switch {
+	case req.Service == cip.ServiceGetInstanceAttributeList:
+		var data []byte
+		for _, s := range mockSymbols[""] {
+			data = binary.LittleEndian.AppendUint32(data, s.id)
+			data = binary.LittleEndian.AppendUint16(data, uint16(len(s.name)))
+			data = append(data, s.name...)
+			data = binary.LittleEndian.AppendUint16(data, s.typeCode)
+			data = append(data, make([]byte, 13)...)
+		}
+		return &cip.MessageRouterResponse{ResponseData: data}
+	case bytes.Equal(req.RequestPath, cip.BuildPath(cip.ClassController, 1, 0)):
+		if m.counters == nil {
+			return &cip.MessageRouterResponse{GeneralStatus: cip.StatusPathDestinationUnknown}
+		}
+		return &cip.MessageRouterResponse{ResponseData: m.counters}
+	case req.Service == cip.ServiceReadTag:
+		m.readPaths = append(m.readPaths, req.RequestPath)
+		return &cip.MessageRouterResponse{ResponseData: []byte{0xC4, 0x00, 0x01, 0x00, 0x00, 0x00}}
+	}
+	return &cip.MessageRouterResponse{GeneralStatus: cip.StatusServiceNotSupported}
+}
+
+func TestClient_SymbolInstanceAddressing(t *testing.T) {
+	m := &mockSymbolController{counters: []byte{0x01, 0x02}}
+	c := newMockCIPClient(t, m.handle)
+	c.symbolCheckInterval = time.Hour
+
+	// Before ListTags tags are addressed by name
+	if _, err := c.ReadTag("Count"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ListTags(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ReadTag("count"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ReadTag("Unlisted"); err != nil {
+		t.Fatal(err)
+	}
+
+	if p := m.readPaths[0]; p[0] != 0x91 {
+		t.Errorf("path before ListTags = % X, want symbolic", []byte(p))
+	}
+	if p, want := m.readPaths[1], []byte{0x20, 0x6B, 0x24, 0x09}; !bytes.Equal(p, want) {
+		t.Errorf("path after ListTags = % X, want % X", []byte(p), want)
+	}
+	if p := m.readPaths[2]; p[0] != 0x91 {
+		t.Errorf("path of unlisted tag = % X, want symbolic", []byte(p))
+	}
+}
+
+func TestClient_SymbolInstanceAddressing_Download(t *testing.T) {
+	m := &mockSymbolController{counters: []byte{0x01, 0x02}}
+	c := newMockCIPClient(t, m.handle)
+	c.symbolCheckInterval = time.Nanosecond
+
+	if _, err := c.ListTags(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ReadTag("Count"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A download changes the counters
+	m.counters = []byte{0x01, 0x03}
+	if _, err := c.ReadTag("Count"); err != nil {
+		t.Fatal(err)
+	}
+
+	if p := m.readPaths[0]; p[0] != 0x20 {
+		t.Errorf("path before download = % X, want instance", []byte(p))
+	}
+	if p := m.readPaths[1]; p[0] != 0x91 {
+		t.Errorf("path after download = % X, want symbolic", []byte(p))
+	}
+}
+
+func TestClient_SymbolInstanceAddressing_NoCounters(t *testing.T) {
+	m := &mockSymbolController{}
+	c := newMockCIPClient(t, m.handle)
+
+	if _, err := c.ListTags(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ReadTag("Count"); err != nil {
+		t.Fatal(err)
+	}
+	if p := m.readPaths[0]; p[0] != 0x91 {
+		t.Errorf("path = % X, want symbolic without change counters", []byte(p))
+	}
+}
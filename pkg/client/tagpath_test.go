@@ -0,0 +1,74 @@
+package client
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+func TestClient_ReadTag_ParsesPath(t *testing.T) {
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		want := []byte{
+			0x91, 0x05, 'M', 'o', 't', 'o', 'r', 0x00,
+			0x28, 0x03,
+			0x91, 0x05, 'S', 'p', 'e', 'e', 'd', 0x00,
+		}
+		if !bytes.Equal(req.RequestPath, want) {
+			t.Errorf("path = %X, want %X", []byte(req.RequestPath), want)
+		}
+		return &cip.MessageRouterResponse{ResponseData: []byte{0xCA, 0x00, 0x00, 0x00, 0x80, 0x3F}}
+	})
+
+	if _, err := c.ReadTag("Motor[3].Speed"); err != nil {
+		t.Fatalf("ReadTag() error = %v", err)
+	}
+}
+
+func TestClient_ReadTag_Bit(t *testing.T) {
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		want := []byte{0x91, 0x06, 'S', 't', 'a', 't', 'u', 's'}
+		if !bytes.Equal(req.RequestPath, want) {
+			t.Errorf("path = %X, want %X", []byte(req.RequestPath), want)
+		}
+		// DINT 0x00010020: bits 5 and 16 set
+		return &cip.MessageRouterResponse{ResponseData: []byte{0xC4, 0x00, 0x20, 0x00, 0x01, 0x00}}
+	})
+
+	tests := []struct {
+		tag  string
+		want byte
+	}{
+		{"Status.5", 0x01},
+		{"Status.6", 0x00},
+		{"Status.16", 0x01},
+	}
+	for _, tt := range tests {
+		data, err := c.ReadTag(tt.tag)
+		if err != nil {
+			t.Fatalf("ReadTag(%q) error = %v", tt.tag, err)
+		}
+		want := []byte{0xC1, 0x00, tt.want}
+		if !bytes.Equal(data, want) {
+			t.Errorf("ReadTag(%q) = %X, want %X", tt.tag, data, want)
+		}
+	}
+
+	if _, err := c.ReadTag("Status.40"); err == nil {
+		t.Error("ReadTag() expected error for bit beyond value size")
+	}
+}
+
+func TestClient_InvalidTagPath(t *testing.T) {
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		t.Error("unexpected request for invalid tag")
+		return &cip.MessageRouterResponse{}
+	})
+
+	if _, err := c.ReadTag("Bad[x]"); err == nil {
+		t.Error("ReadTag() expected parse error")
+	}
+	if _, err := c.ReadTagArray("Flags.3", 0, 1); err == nil {
+		t.Error("ReadTagArray() expected error for bit path")
+	}
+}
@@ -0,0 +1,189 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// ReadTemplate reads the layout of a structure from the Template Object
+// (class 0x6C). Templates are cached per client, since they only change when
+// a new program is downloaded to the controller; the cache is dropped when
+// the controller's change counters show a download (see
+// WithSymbolCheckInterval). Omron NJ/NX controllers have no Template
+// Object, see ReadVariableType.
+func (c *Client) ReadTemplate(instanceID uint16) (*cip.Template, error) {
+	return c.ReadTemplateContext(context.Background(), instanceID)
+}
+
+// ReadTemplateContext is like ReadTemplate but gives up when ctx is done.
+func (c *Client) ReadTemplateContext(ctx context.Context, instanceID uint16) (*cip.Template, error) {
+	if c.variableObjects() {
+		return nil, fmt.Errorf("read template: %w (%s)", ErrNotSupported, c.profile.Name)
+	}
+	c.refreshSymbols(ctx)
+
+	c.templateMu.Lock()
+	t, ok := c.templates[instanceID]
+	c.templateMu.Unlock()
+	if ok {
+		return t, nil
+	}
+
+	c.trackChanges(ctx)
+	t, err := c.fetchTemplate(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.templateMu.Lock()
+	if c.templates == nil {
+		c.templates = make(map[uint16]*cip.Template)
+	}
+	c.templates[instanceID] = t
+	c.templateMu.Unlock()
+
+	return t, nil
+}
+
+func (c *Client) fetchTemplate(ctx context.Context, instanceID uint16) (*cip.Template, error) {
+	// Step 1: Attributes (handle, member count, definition and structure size)
+	resp, err := c.send(ctx, cip.NewGetTemplateAttributesRequest(instanceID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template %d attributes: %w", instanceID, err)
+	}
+	if err := resp.Error(); err != nil {
+		return nil, fmt.Errorf("failed to get template %d attributes: %w", instanceID, err)
+	}
+
+	t, err := cip.DecodeTemplateAttributesResponse(instanceID, resp.ResponseData)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: Member definitions, which may span several requests. Each
+	// request asks for at most one packet; the controller may return less
+	// with a partial transfer status, so keep reading until the whole
+	// definition is in.
+	total := t.DefinitionBytes()
+	var def []byte
+	for uint32(len(def)) < total {
+		offset := uint32(len(def))
+		length := min(total-offset, uint32(c.packetSize()-8))
+
+		resp, err := c.send(ctx, cip.NewReadTemplateRequest(instanceID, offset, uint16(length)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %d: %w", instanceID, err)
+		}
+		if resp.GeneralStatus != cip.StatusPartialTransfer {
+			if err := resp.Error(); err != nil {
+				return nil, fmt.Errorf("failed to read template %d: %w", instanceID, err)
+			}
+		}
+		if len(resp.ResponseData) == 0 {
+			return nil, fmt.Errorf("read template %d: no data at offset %d of %d", instanceID, offset, total)
+		}
+		def = append(def, resp.ResponseData...)
+	}
+
+	if err := cip.DecodeTemplateDefinition(t, def); err != nil {
+		return nil, err
+	}
+
+	c.logger.Debugf("Template %d: %s (%d members, %d bytes)", instanceID, t.Name, len(t.Members), t.StructureSize)
+	return t, nil
+}
+
+// ReadTagStruct reads a structure tag and decodes it into a tree of Go
+// values using the layout of template templateID (see
+// cip.SymbolInstance.TemplateID). Nested structures are resolved through the
+// Template Object as needed. On Omron NJ/NX controllers templateID is the
+// Variable Type instance from ListTags, and the members are read one by one
+// with the layout from ReadVariableType.
+func (c *Client) ReadTagStruct(tagName string, templateID uint16) (map[string]any, error) {
+	return c.ReadTagStructContext(context.Background(), tagName, templateID)
+}
+
+// ReadTagStructContext is like ReadTagStruct but gives up when ctx is done.
+func (c *Client) ReadTagStructContext(ctx context.Context, tagName string, templateID uint16) (map[string]any, error) {
+	if c.variableObjects() {
+		return c.readVariableStruct(ctx, tagName, uint32(templateID))
+	}
+	t, value, err := c.readStruct(ctx, tagName, templateID)
+	if err != nil {
+		return nil, err
+	}
+	return t.Decode(value, c.templateLookup(ctx))
+}
+
+// WriteTagStruct changes members of a structure tag with the layout of
+// template templateID. members is keyed by member name like the result of
+// ReadTagStruct; members left out keep their value, since the tag is read
+// first and written back whole. On Omron NJ/NX controllers only the given
+// members are written, one by one, so the structure does not change
+// atomically.
+func (c *Client) WriteTagStruct(tagName string, templateID uint16, members map[string]any) error {
+	return c.WriteTagStructContext(context.Background(), tagName, templateID, members)
+}
+
+// WriteTagStructContext is like WriteTagStruct but gives up when ctx is
+// done.
+func (c *Client) WriteTagStructContext(ctx context.Context, tagName string, templateID uint16, members map[string]any) error {
+	if c.variableObjects() {
+		return c.writeVariableStruct(ctx, tagName, uint32(templateID), members)
+	}
+	t, value, err := c.readStruct(ctx, tagName, templateID)
+	if err != nil {
+		return err
+	}
+	data := bytes.Clone(value[:t.StructureSize])
+	if err := t.Encode(data, members, c.templateLookup(ctx)); err != nil {
+		return err
+	}
+
+	p, err := c.valuePath(ctx, tagName)
+	if err != nil {
+		return err
+	}
+	return c.writeTag(ctx, &tagWrite{path: p, dataType: cip.TypeSTRUCT, handle: t.Handle, elements: 1, data: data})
+}
+
+// readStruct reads a structure tag and checks that it has the layout of
+// template templateID. It returns the template and the tag's value.
+func (c *Client) readStruct(ctx context.Context, tagName string, templateID uint16) (*cip.Template, []byte, error) {
+	t, err := c.ReadTemplateContext(ctx, templateID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := c.ReadTagContext(ctx, tagName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	typeInfo, value, err := cip.SplitReadTagResponse(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(typeInfo) != 4 {
+		return nil, nil, fmt.Errorf("tag %q is not a structure (type %s)", tagName, cip.DataType(binary.LittleEndian.Uint16(typeInfo)))
+	}
+	if handle := binary.LittleEndian.Uint16(typeInfo[2:4]); handle != t.Handle {
+		return nil, nil, fmt.Errorf("tag %q structure handle 0x%04X does not match template %s (0x%04X)", tagName, handle, t.Name, t.Handle)
+	}
+	if uint32(len(value)) < t.StructureSize {
+		return nil, nil, fmt.Errorf("tag %q: %s needs %d bytes, got %d", tagName, t.Name, t.StructureSize, len(value))
+	}
+	return t, value, nil
+}
+
+// templateLookup resolves nested structure templates through the client's
+// template cache.
+func (c *Client) templateLookup(ctx context.Context) cip.TemplateLookup {
+	return func(id uint16) (*cip.Template, error) {
+		return c.ReadTemplateContext(ctx, id)
+	}
+}
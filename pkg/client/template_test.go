@@ -0,0 +1,303 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// mockTemplate is a template served by templateHandler.
+type mockTemplate struct {
+	handle uint16
+	name   string
+	size   uint32
+	// members as Name, Info, TypeCode, Offset
+	members []cip.TemplateMember
+}
+
+func (m mockTemplate) definition() []byte {
+	var buf bytes.Buffer
+	for _, mem := range m.members {
+		binary.Write(&buf, binary.LittleEndian, mem.Info)
+		binary.Write(&buf, binary.LittleEndian, mem.TypeCode)
+		binary.Write(&buf, binary.LittleEndian, mem.Offset)
+	}
+	buf.WriteString(m.name + ";n\x00")
+	for _, mem := range m.members {
+		buf.WriteString(mem.Name + "\x00")
+	}
+	return buf.Bytes()
+}
+
+// templateHandler answers Template Object requests for templates. Read
+// Template replies hold at most the requested length, and at most chunk
+// bytes, with a partial transfer status if the controller split the reply.
+// Change counter reads fail, as on a controller without them. Other requests
+// go to next.
+func templateHandler(t *testing.T, templates map[uint16]mockTemplate, chunk int, next cipHandler) cipHandler {
+	return func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		p := req.RequestPath
+		if bytes.Equal(p, cip.BuildPath(cip.ClassController, 1, 0)) {
+			return &cip.MessageRouterResponse{GeneralStatus: cip.StatusPathDestinationUnknown}
+		}
+		if len(p) < 2 || p[0] != 0x20 || p[1] != byte(cip.ClassTemplate) {
+			return next(req)
+		}
+
+		var id uint16
+		if p[2] == 0x24 {
+			id = uint16(p[3])
+		} else {
+			id = binary.LittleEndian.Uint16(p[4:6])
+		}
+		tmpl, ok := templates[id]
+		if !ok {
+			return &cip.MessageRouterResponse{GeneralStatus: cip.StatusObjectDoesNotExist}
+		}
+		def := tmpl.definition()
+
+		switch req.Service {
+		case cip.ServiceGetAttributeList:
+			// Definition size in words includes the 23-byte header
+			words := (uint32(len(def)) + 23 + 3) / 4
+			var out []byte
+			out = binary.LittleEndian.AppendUint16(out, 4)
+			out = append(out, 0x01, 0x00, 0x00, 0x00)
+			out = binary.LittleEndian.AppendUint16(out, tmpl.handle)
+			out = append(out, 0x02, 0x00, 0x00, 0x00)
+			out = binary.LittleEndian.AppendUint16(out, uint16(len(tmpl.members)))
+			out = append(out, 0x04, 0x00, 0x00, 0x00)
+			out = binary.LittleEndian.AppendUint32(out, words)
+			out = append(out, 0x05, 0x00, 0x00, 0x00)
+			out = binary.LittleEndian.AppendUint32(out, tmpl.size)
+			return &cip.MessageRouterResponse{ResponseData: out}
+
+		case cip.ServiceReadTemplate:
+			// Pad the definition up to the advertised size
+			words := (uint32(len(def)) + 23 + 3) / 4
+			def = append(def, make([]byte, int(words*4-23)-len(def))...)
+
+			offset := int(binary.LittleEndian.Uint32(req.RequestData[0:4]))
+			want := min(offset+int(binary.LittleEndian.Uint16(req.RequestData[4:6])), len(def))
+			end := min(offset+chunk, want)
+			status := cip.StatusSuccess
+			if end < want {
+				status = cip.StatusPartialTransfer
+			}
+			return &cip.MessageRouterResponse{GeneralStatus: status, ResponseData: def[offset:end]}
+		}
+
+		t.Errorf("unexpected template service 0x%02X", req.Service)
+		return &cip.MessageRouterResponse{GeneralStatus: cip.StatusServiceNotSupported}
+	}
+}
+
+var testTemplates = map[uint16]mockTemplate{
+	0x0100: {
+		handle: 0x1111,
+		name:   "Point",
+		size:   8,
+		members: []cip.TemplateMember{
+			{Name: "X", TypeCode: uint16(cip.TypeDINT), Offset: 0},
+			{Name: "Y", TypeCode: uint16(cip.TypeREAL), Offset: 4},
+		},
+	},
+	0x0200: {
+		handle: 0x2222,
+		name:   "Motor",
+		size:   16,
+		members: []cip.TemplateMember{
+			{Name: "ZZZZZZZZZZMotor0", TypeCode: uint16(cip.TypeSINT), Offset: 0},
+			{Name: "Running", Info: 3, TypeCode: uint16(cip.TypeBOOL), Offset: 0},
+			{Name: "Speed", TypeCode: uint16(cip.TypeINT), Offset: 2},
+			{Name: "Target", TypeCode: 0x8100, Offset: 8},
+		},
+	},
+}
+
+func TestClient_ReadTemplate(t *testing.T) {
+	c := newMockCIPClient(t, templateHandler(t, testTemplates, 16, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		t.Errorf("unexpected request 0x%02X", req.Service)
+		return &cip.MessageRouterResponse{GeneralStatus: cip.StatusServiceNotSupported}
+	}))
+
+	tmpl, err := c.ReadTemplate(0x0200)
+	if err != nil {
+		t.Fatalf("ReadTemplate() error = %v", err)
+	}
+
+	if tmpl.Name != "Motor" || tmpl.Handle != 0x2222 || tmpl.StructureSize != 16 {
+		t.Errorf("template = %+v", tmpl)
+	}
+	if !reflect.DeepEqual(tmpl.Members, testTemplates[0x0200].members) {
+		t.Errorf("members = %+v", tmpl.Members)
+	}
+
+	// Second read is served from the cache
+	again, err := c.ReadTemplate(0x0200)
+	if err != nil {
+		t.Fatalf("ReadTemplate() cached error = %v", err)
+	}
+	if again != tmpl {
+		t.Error("expected cached template")
+	}
+
+	if _, err := c.ReadTemplate(0x0999); err == nil {
+		t.Error("expected error for unknown template")
+	}
+}
+
+func TestClient_ReadTemplateLarge(t *testing.T) {
+	// A definition larger than one packet needs several Read Template
+	// requests even when every reply is complete.
+	large := mockTemplate{handle: 0x3333, name: "Recipe", size: 400}
+	for i := range 100 {
+		large.members = append(large.members, cip.TemplateMember{
+			Name:     fmt.Sprintf("Ingredient%03d", i),
+			TypeCode: uint16(cip.TypeDINT),
+			Offset:   uint32(i * 4),
+		})
+	}
+	if n := len(large.definition()); n <= defaultPacketSize {
+		t.Fatalf("definition is only %d bytes", n)
+	}
+
+	reads := 0
+	templates := map[uint16]mockTemplate{0x0300: large}
+	handler := templateHandler(t, templates, 4000, nil)
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		if req.Service == cip.ServiceReadTemplate {
+			reads++
+		}
+		return handler(req)
+	})
+
+	tmpl, err := c.ReadTemplate(0x0300)
+	if err != nil {
+		t.Fatalf("ReadTemplate() error = %v", err)
+	}
+	if !reflect.DeepEqual(tmpl.Members, large.members) {
+		t.Errorf("got %d members, want %d", len(tmpl.Members), len(large.members))
+	}
+	if reads < 3 {
+		t.Errorf("Read Template requests = %d, want at least 3", reads)
+	}
+}
+
+func TestClient_ReadTagStruct(t *testing.T) {
+	value := make([]byte, 16)
+	value[0] = 0x08 // Running (bit 3)
+	binary.LittleEndian.PutUint16(value[2:], 1500)
+	binary.LittleEndian.PutUint32(value[8:], 7)
+	binary.LittleEndian.PutUint32(value[12:], 0x3F800000)
+
+	c := newMockCIPClient(t, templateHandler(t, testTemplates, 200, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		if req.Service != cip.ServiceReadTag {
+			t.Errorf("unexpected request 0x%02X", req.Service)
+		}
+		return &cip.MessageRouterResponse{ResponseData: append([]byte{0xA0, 0x02, 0x22, 0x22}, value...)}
+	}))
+
+	got, err := c.ReadTagStruct("Pump", 0x0200)
+	if err != nil {
+		t.Fatalf("ReadTagStruct() error = %v", err)
+	}
+
+	want := map[string]any{
+		"Running": true,
+		"Speed":   int16(1500),
+		"Target":  map[string]any{"X": int32(7), "Y": float32(1)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadTagStruct() = %#v, want %#v", got, want)
+	}
+
+	// Handle mismatch is reported instead of decoding garbage
+	if _, err := c.ReadTagStruct("Pump", 0x0100); err == nil {
+		t.Error("expected handle mismatch error")
+	}
+}
+
+func TestClient_ReadTagStruct_Download(t *testing.T) {
+	templates := map[uint16]mockTemplate{
+		0x0100: {
+			handle:  0x1111,
+			name:    "Point",
+			size:    4,
+			members: []cip.TemplateMember{{Name: "X", TypeCode: uint16(cip.TypeDINT), Offset: 0}},
+		},
+	}
+	counters := []byte{0x01, 0x02}
+	handle := []byte{0x11, 0x11}
+	value := []byte{0x07, 0x00, 0x00, 0x00, 0x09, 0x00, 0x00, 0x00}
+
+	handler := templateHandler(t, templates, 200, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		return &cip.MessageRouterResponse{ResponseData: append(append([]byte{0xA0, 0x02}, handle...), value...)}
+	})
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		if bytes.Equal(req.RequestPath, cip.BuildPath(cip.ClassController, 1, 0)) {
+			return &cip.MessageRouterResponse{ResponseData: counters}
+		}
+		return handler(req)
+	})
+	c.symbolCheckInterval = time.Nanosecond
+
+	got, err := c.ReadTagStruct("P", 0x0100)
+	if err != nil {
+		t.Fatalf("ReadTagStruct() error = %v", err)
+	}
+	if want := map[string]any{"X": int32(7)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadTagStruct() = %#v, want %#v", got, want)
+	}
+
+	// A download adds a member to Point, changing its handle and the
+	// change counters
+	templates[0x0100] = mockTemplate{
+		handle: 0x1112,
+		name:   "Point",
+		size:   8,
+		members: []cip.TemplateMember{
+			{Name: "X", TypeCode: uint16(cip.TypeDINT), Offset: 0},
+			{Name: "Y", TypeCode: uint16(cip.TypeDINT), Offset: 4},
+		},
+	}
+	counters = []byte{0x01, 0x03}
+	handle = []byte{0x12, 0x11}
+
+	got, err = c.ReadTagStruct("P", 0x0100)
+	if err != nil {
+		t.Fatalf("ReadTagStruct() after download error = %v", err)
+	}
+	if want := map[string]any{"X": int32(7), "Y": int32(9)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadTagStruct() after download = %#v, want %#v", got, want)
+	}
+}
+
+func TestClient_InvalidateSymbols_Templates(t *testing.T) {
+	reads := 0
+	handler := templateHandler(t, testTemplates, 200, nil)
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		if req.Service == cip.ServiceGetAttributeList && req.RequestPath[1] == byte(cip.ClassTemplate) {
+			reads++
+		}
+		return handler(req)
+	})
+
+	for range 2 {
+		if _, err := c.ReadTemplate(0x0100); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.InvalidateSymbols()
+	if _, err := c.ReadTemplate(0x0100); err != nil {
+		t.Fatal(err)
+	}
+	if reads != 2 {
+		t.Errorf("template attribute reads = %d, want 2", reads)
+	}
+}
@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// Read reads a tag and decodes it as a T. Unlike ReadTagInto, the tag's
+// type is checked against T first (see cip.UnmarshalTag): numbers convert
+// only when T holds every value of the tag's type, so a DINT reads into
+// int32, int64 or float64, and structures must match by structure handle
+// or size. A tag that does not match returns a *cip.TypeMismatchError.
+// An array type [N]E reads N elements starting at the addressed one.
+func Read[T any](c *Client, tagName string) (T, error) {
+	return ReadContext[T](context.Background(), c, tagName)
+}
+
+// ReadContext is like Read but gives up when ctx is done.
+func ReadContext[T any](ctx context.Context, c *Client, tagName string) (T, error) {
+	var v, zero T
+	elements, err := readElements(reflect.TypeOf(&v).Elem())
+	if err != nil {
+		return zero, err
+	}
+
+	var data []byte
+	if elements == 1 {
+		data, err = c.ReadTagContext(ctx, tagName)
+	} else {
+		var p cip.Path
+		if p, err = c.valuePath(ctx, tagName); err == nil {
+			data, err = c.readTag(ctx, p, elements)
+		}
+	}
+	if err != nil {
+		return zero, err
+	}
+
+	typeInfo, value, err := cip.SplitReadTagResponse(data)
+	if err != nil {
+		return zero, err
+	}
+	if err := cip.UnmarshalTag(typeInfo, value, &v); err != nil {
+		return zero, fmt.Errorf("tag %q: %w", tagName, err)
+	}
+	return v, nil
+}
+
+// readElements returns the number of elements to read for a value of
+// type rt: the length of an array (in DWORDs for bools), or 1.
+func readElements(rt reflect.Type) (uint16, error) {
+	switch rt.Kind() {
+	case reflect.Slice:
+		return 0, fmt.Errorf("cannot read into %s: the length is unknown, use an array type", rt)
+	case reflect.Array:
+		n := rt.Len()
+		if rt.Elem().Kind() == reflect.Bool {
+			if n%32 != 0 {
+				return 0, fmt.Errorf("cannot read into %s: BOOL arrays are read in multiples of 32", rt)
+			}
+			n /= 32
+		}
+		if n == 0 || n > 0xFFFF {
+			return 0, fmt.Errorf("cannot read into %s: invalid element count %d", rt, n)
+		}
+		return uint16(n), nil
+	}
+	return 1, nil
+}
+
+// Write writes value to a tag after checking it against the tag's type
+// (see cip.MarshalTag). Numbers are converted to the tag's type when the
+// value fits, so Write(c, "Count", 5) works for SINT to LINT tags, and
+// structures are written with the tag's structure handle. A value that
+// does not match returns an error wrapping a *cip.TypeMismatchError.
+// The tag is read once first to learn its type; strings and bits are
+// written as with WriteTag.
+func Write[T any](c *Client, tagName string, value T) error {
+	return WriteContext(context.Background(), c, tagName, value)
+}
+
+// WriteContext is like Write but gives up when ctx is done.
+func WriteContext[T any](ctx context.Context, c *Client, tagName string, value T) error {
+	switch v := any(value).(type) {
+	case string:
+		return c.WriteTagContext(ctx, tagName, v)
+	case bool:
+		tp, err := cip.ParseTagPath(tagName)
+		if err != nil {
+			return err
+		}
+		if handled, err := c.writeBool(ctx, tp, v); handled {
+			return err
+		}
+	}
+
+	p, err := c.valuePath(ctx, tagName)
+	if err != nil {
+		return err
+	}
+
+	current, err := c.readTag(ctx, p, 1)
+	if err != nil {
+		return err
+	}
+	typeInfo, elem, err := cip.SplitReadTagResponse(current)
+	if err != nil {
+		return err
+	}
+
+	data, err := cip.MarshalTag(typeInfo, len(elem), value)
+	if err != nil {
+		return fmt.Errorf("tag %q: %w", tagName, err)
+	}
+	elements, err := cip.ElementCount(value)
+	if err != nil {
+		return err
+	}
+
+	dataType, handle := cip.SplitTypeInfo(typeInfo)
+	return c.writeTag(ctx, &tagWrite{path: p, dataType: dataType, handle: handle, elements: elements, data: data})
+}
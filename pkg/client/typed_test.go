@@ -0,0 +1,105 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+func TestRead_Typed(t *testing.T) {
+	var elements []uint16
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		n := binary.LittleEndian.Uint16(req.RequestData)
+		elements = append(elements, n)
+		data := []byte{byte(cip.TypeINT), 0}
+		for i := uint16(0); i < n; i++ {
+			data = append(data, byte(i+1), 0)
+		}
+		return &cip.MessageRouterResponse{ResponseData: data}
+	})
+
+	v, err := Read[int64](c, "Count")
+	if err != nil || v != 1 {
+		t.Fatalf("Read[int64]() = %d, %v, want 1", v, err)
+	}
+
+	arr, err := Read[[3]float32](c, "Counts")
+	if err != nil || arr != [3]float32{1, 2, 3} {
+		t.Fatalf("Read[[3]float32]() = %v, %v", arr, err)
+	}
+	if len(elements) != 2 || elements[1] != 3 {
+		t.Errorf("elements = %v, want [1 3]", elements)
+	}
+
+	if _, err := Read[int8](c, "Count"); !errors.Is(err, cip.ErrTypeMismatch) {
+		t.Errorf("Read[int8]() error = %v, want type mismatch", err)
+	}
+	if _, err := Read[[]int16](c, "Counts"); err == nil {
+		t.Error("Read[[]int16]() expected error")
+	}
+}
+
+func TestWrite_Typed(t *testing.T) {
+	var written []byte
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		switch req.Service {
+		case cip.ServiceReadTag:
+			return &cip.MessageRouterResponse{ResponseData: []byte{byte(cip.TypeINT), 0, 0, 0}}
+		case cip.ServiceWriteTag:
+			written = req.RequestData
+			return &cip.MessageRouterResponse{}
+		}
+		return &cip.MessageRouterResponse{GeneralStatus: cip.StatusServiceNotSupported}
+	})
+
+	if err := Write(c, "Count", 5); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	want := []byte{byte(cip.TypeINT), 0, 1, 0, 5, 0}
+	if !bytes.Equal(written, want) {
+		t.Errorf("request data = % X, want % X", written, want)
+	}
+
+	written = nil
+	if err := Write(c, "Count", 70000); !errors.Is(err, cip.ErrTypeMismatch) {
+		t.Errorf("Write(70000) error = %v, want type mismatch", err)
+	}
+	if written != nil {
+		t.Error("mismatched value was written")
+	}
+}
+
+func TestReadWrite_Time(t *testing.T) {
+	stamp := time.Date(2024, 3, 15, 6, 0, 0, 0, time.UTC)
+	value, _ := cip.EncodeTime(cip.TypeLINT, stamp)
+
+	var written []byte
+	c := newMockCIPClient(t, func(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+		switch req.Service {
+		case cip.ServiceReadTag:
+			return &cip.MessageRouterResponse{ResponseData: append([]byte{byte(cip.TypeLINT), 0}, value...)}
+		case cip.ServiceWriteTag:
+			written = req.RequestData
+			return &cip.MessageRouterResponse{}
+		}
+		return &cip.MessageRouterResponse{GeneralStatus: cip.StatusServiceNotSupported}
+	})
+
+	got, err := Read[time.Time](c, "Batch.StartTime")
+	if err != nil || !got.Equal(stamp) {
+		t.Fatalf("Read[time.Time]() = %v, %v, want %v", got, err, stamp)
+	}
+
+	if err := Write(c, "Batch.StartTime", stamp.Add(time.Second)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	next, _ := cip.EncodeTime(cip.TypeLINT, stamp.Add(time.Second))
+	want := append([]byte{byte(cip.TypeLINT), 0, 1, 0}, next...)
+	if !bytes.Equal(written, want) {
+		t.Errorf("request data = % X, want % X", written, want)
+	}
+}
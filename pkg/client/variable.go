@@ -0,0 +1,337 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// variableListPage is the number of variables asked for per Get Instance
+// List Extended request.
+const variableListPage = 100
+
+// maxVariableMembers bounds the member list of a structure type, so a
+// corrupt Next chain cannot loop forever.
+const maxVariableMembers = 0xFFFF
+
+// variableObjects reports whether the controller describes its variables
+// with the Omron Tag Name Server and Variable Objects, see
+// TagListingTagNameServer.
+func (c *Client) variableObjects() bool {
+	return c.profile != nil && c.profile.TagListing == TagListingTagNameServer
+}
+
+// listVariables lists the published variables of an Omron controller for
+// ListTags. Omron variables are global, so there are no program tags to
+// include; IncludeSystemTags adds the system-defined variables.
+func (c *Client) listVariables(ctx context.Context, l listTags) ([]cip.SymbolInstance, error) {
+	if l.program != "" {
+		return nil, fmt.Errorf("list tags of program %s: %w (%s)", l.program, ErrNotSupported, c.profile.Name)
+	}
+
+	kinds := []cip.VariableKind{cip.VariablesUser}
+	if l.system {
+		kinds = append(kinds, cip.VariablesSystem)
+	}
+	var entries []cip.VariableEntry
+	for _, kind := range kinds {
+		page, err := c.listVariableNames(ctx, kind)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, page...)
+	}
+
+	// The names come from the Tag Name Server, the types from the
+	// Variable Object of each variable
+	reqs := make([]*cip.MessageRouterRequest, len(entries))
+	for i, e := range entries {
+		reqs[i] = cip.NewGetVariableRequest(e.InstanceID)
+	}
+	resps, errs, err := c.sendBatch(ctx, reqs)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]cip.SymbolInstance, 0, len(entries))
+	for i, e := range entries {
+		if err := errs[i]; err != nil {
+			return nil, fmt.Errorf("variable %s: %w", e.Name, err)
+		}
+		if err := resps[i].Error(); err != nil {
+			return nil, fmt.Errorf("variable %s: %w", e.Name, err)
+		}
+		info, err := cip.DecodeVariableInfo(resps[i].ResponseData)
+		if err != nil {
+			return nil, fmt.Errorf("variable %s: %w", e.Name, err)
+		}
+		s, err := info.Symbol(e)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, s)
+	}
+	return tags, nil
+}
+
+// listVariableNames pages through the variables of a kind registered with
+// the Tag Name Server.
+func (c *Client) listVariableNames(ctx context.Context, kind cip.VariableKind) ([]cip.VariableEntry, error) {
+	var entries []cip.VariableEntry
+	start := uint32(0)
+
+	for {
+		resp, err := c.send(ctx, cip.NewGetInstanceListExtendedRequest(start, variableListPage, kind))
+		if err != nil {
+			return nil, fmt.Errorf("list variables: %w", err)
+		}
+		partial := resp.GeneralStatus == cip.StatusPartialTransfer
+		if !partial {
+			if err := resp.Error(); err != nil {
+				return nil, fmt.Errorf("list variables: %w", err)
+			}
+		}
+
+		page, err := cip.DecodeGetInstanceListExtendedResponse(resp.ResponseData)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, page...)
+
+		if len(page) == 0 || (!partial && len(page) < variableListPage) {
+			return entries, nil
+		}
+		start = page[len(page)-1].InstanceID + 1
+	}
+}
+
+// ReadVariableType reads a structure type of an Omron controller and its
+// members from the Variable Type Object (class 0x6C). It is the
+// ProfileOmronNJ counterpart of ReadTemplate: instanceID is the template ID
+// of a structure variable listed by ListTags (see
+// cip.SymbolInstance.TemplateID), or the Nesting instance of a structure
+// member. Types are cached per client.
+func (c *Client) ReadVariableType(instanceID uint32) (*cip.VariableType, error) {
+	return c.ReadVariableTypeContext(context.Background(), instanceID)
+}
+
+// ReadVariableTypeContext is like ReadVariableType but gives up when ctx is
+// done.
+func (c *Client) ReadVariableTypeContext(ctx context.Context, instanceID uint32) (*cip.VariableType, error) {
+	c.templateMu.Lock()
+	vt, ok := c.variableTypes[instanceID]
+	c.templateMu.Unlock()
+	if ok {
+		return vt, nil
+	}
+
+	info, err := c.readVariableInfo(ctx, cip.NewGetVariableTypeRequest(instanceID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read variable type %d: %w", instanceID, err)
+	}
+	if !info.IsStructure() {
+		return nil, fmt.Errorf("variable type %d (%s) is %s, not a structure", instanceID, info.Name, info.Type)
+	}
+
+	vt = &cip.VariableType{InstanceID: instanceID, Name: info.Name, Handle: info.Handle, Size: info.Size}
+	for id := info.Nesting; id != 0; {
+		if len(vt.Members) == maxVariableMembers {
+			return nil, fmt.Errorf("variable type %s: more than %d members", vt.Name, maxVariableMembers)
+		}
+		m, err := c.readVariableInfo(ctx, cip.NewGetVariableTypeRequest(id))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read variable type %s member %d: %w", vt.Name, id, err)
+		}
+		vt.Members = append(vt.Members, m)
+		id = m.Next
+	}
+	c.logger.Debugf("Variable type %d: %s (%d members, %d bytes)", instanceID, vt.Name, len(vt.Members), vt.Size)
+
+	c.templateMu.Lock()
+	if c.variableTypes == nil {
+		c.variableTypes = make(map[uint32]*cip.VariableType)
+	}
+	c.variableTypes[instanceID] = vt
+	c.templateMu.Unlock()
+
+	return vt, nil
+}
+
+func (c *Client) readVariableInfo(ctx context.Context, req *cip.MessageRouterRequest) (cip.VariableInfo, error) {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return cip.VariableInfo{}, err
+	}
+	if err := resp.Error(); err != nil {
+		return cip.VariableInfo{}, err
+	}
+	return cip.DecodeVariableInfo(resp.ResponseData)
+}
+
+// readVariableStruct reads a structure variable of type typeID member by
+// member, packing the reads into Multiple Service Packets. Nested
+// structures are read through their own members.
+func (c *Client) readVariableStruct(ctx context.Context, tagName string, typeID uint32) (map[string]any, error) {
+	vt, err := c.ReadVariableTypeContext(ctx, typeID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]any, len(vt.Members))
+	var reqs []*cip.MessageRouterRequest
+	var members []cip.VariableInfo
+	for _, m := range vt.Members {
+		name := tagName + "." + m.Name
+		if m.IsStructure() {
+			v, err := c.readVariableStructMember(ctx, name, m)
+			if err != nil {
+				return nil, err
+			}
+			out[m.Name] = v
+			continue
+		}
+
+		p, err := c.valuePath(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		count := uint16(1)
+		if m.IsArray() {
+			count = uint16(m.Elements)
+		}
+		reqs = append(reqs, cip.NewReadTagRequest(p, count))
+		members = append(members, m)
+	}
+
+	resps, errs, err := c.sendBatch(ctx, reqs)
+	if err != nil {
+		return nil, err
+	}
+	for j, m := range members {
+		if err := errs[j]; err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", tagName, m.Name, err)
+		}
+		if err := resps[j].Error(); err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", tagName, m.Name, err)
+		}
+		v, err := cip.DecodeMember(m, resps[j].ResponseData)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", tagName, m.Name, err)
+		}
+		out[m.Name] = v
+	}
+	return out, nil
+}
+
+// readVariableStructMember reads a structure member, or each element of an
+// array of structures.
+func (c *Client) readVariableStructMember(ctx context.Context, name string, m cip.VariableInfo) (any, error) {
+	if !m.IsArray() {
+		return c.readVariableStruct(ctx, name, m.Nesting)
+	}
+	values := make([]any, m.Elements)
+	for i := range values {
+		v, err := c.readVariableStruct(ctx, fmt.Sprintf("%s[%d]", name, m.Start+uint32(i)), m.Nesting)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// variableWrite is one member write of writeVariableStruct.
+type variableWrite struct {
+	name string
+	req  *cip.MessageRouterRequest
+}
+
+// writeVariableStruct writes members of a structure variable of type
+// typeID member by member, packing the writes into Multiple Service
+// Packets. Members left out are not written.
+func (c *Client) writeVariableStruct(ctx context.Context, tagName string, typeID uint32, members map[string]any) error {
+	var writes []variableWrite
+	if err := c.variableWrites(ctx, tagName, typeID, members, &writes); err != nil {
+		return err
+	}
+
+	reqs := make([]*cip.MessageRouterRequest, len(writes))
+	for i, w := range writes {
+		reqs[i] = w.req
+	}
+	resps, errs, err := c.sendBatch(ctx, reqs)
+	if err != nil {
+		return err
+	}
+	for j, w := range writes {
+		if err := errs[j]; err != nil {
+			return fmt.Errorf("%s: %w", w.name, err)
+		}
+		if err := resps[j].Error(); err != nil {
+			return fmt.Errorf("%s: %w", w.name, err)
+		}
+	}
+	return nil
+}
+
+// variableWrites appends the Write Tag requests for members of a structure
+// of type typeID at tagName to writes. Nested structures take a
+// map[string]any, arrays of structures a []any of them.
+func (c *Client) variableWrites(ctx context.Context, tagName string, typeID uint32, members map[string]any, writes *[]variableWrite) error {
+	vt, err := c.ReadVariableTypeContext(ctx, typeID)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range vt.Members {
+		v, ok := members[m.Name]
+		if !ok {
+			continue
+		}
+		name := tagName + "." + m.Name
+
+		if m.IsStructure() {
+			if !m.IsArray() {
+				fields, ok := v.(map[string]any)
+				if !ok {
+					return fmt.Errorf("%s: %T is not a map[string]any", name, v)
+				}
+				if err := c.variableWrites(ctx, name, m.Nesting, fields, writes); err != nil {
+					return err
+				}
+				continue
+			}
+
+			elems, ok := v.([]any)
+			if !ok {
+				return fmt.Errorf("%s: %T is not a []any", name, v)
+			}
+			if len(elems) > int(m.Elements) {
+				return fmt.Errorf("%s: %d elements do not fit an array of %d", name, len(elems), m.Elements)
+			}
+			for i, e := range elems {
+				fields, ok := e.(map[string]any)
+				if !ok {
+					return fmt.Errorf("%s: element %d: %T is not a map[string]any", name, i, e)
+				}
+				elem := fmt.Sprintf("%s[%d]", name, m.Start+uint32(i))
+				if err := c.variableWrites(ctx, elem, m.Nesting, fields, writes); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		data, elements, err := cip.EncodeMember(m, v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", tagName, err)
+		}
+		p, err := c.valuePath(ctx, name)
+		if err != nil {
+			return err
+		}
+		*writes = append(*writes, variableWrite{name: name, req: cip.NewWriteTagRequest(p, m.Type, elements, data)})
+	}
+	return nil
+}
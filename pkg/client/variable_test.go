@@ -0,0 +1,322 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// variableInfoReply encodes a Get Attribute All reply of the Variable or
+// Variable Type Object.
+func variableInfoReply(v cip.VariableInfo) []byte {
+	b := binary.LittleEndian.AppendUint32(nil, v.Size)
+	if v.Dims > 0 {
+		b = append(b, 0xA3, byte(v.Type))
+	} else {
+		b = append(b, byte(v.Type), 0)
+	}
+	b = append(b, byte(v.Dims), 0)
+	b = binary.LittleEndian.AppendUint32(b, v.Elements)
+	b = binary.LittleEndian.AppendUint32(b, v.Start)
+	b = binary.LittleEndian.AppendUint32(b, v.Nesting)
+	b = binary.LittleEndian.AppendUint32(b, v.Next)
+	b = binary.LittleEndian.AppendUint16(b, v.Handle)
+	b = append(b, byte(len(v.Name)))
+	return append(b, v.Name...)
+}
+
+// symbolicName decodes a symbolic tag path back into a tag expression such
+// as "Axes[2].Pos.X".
+func symbolicName(p cip.Path) string {
+	var sb strings.Builder
+	for len(p) > 0 {
+		switch p[0] {
+		case 0x91:
+			n := int(p[1])
+			if sb.Len() > 0 {
+				sb.WriteByte('.')
+			}
+			sb.Write(p[2 : 2+n])
+			p = p[2+n+n%2:]
+		case 0x28:
+			fmt.Fprintf(&sb, "[%d]", p[1])
+			p = p[2:]
+		case 0x29:
+			fmt.Fprintf(&sb, "[%d]", binary.LittleEndian.Uint16(p[2:]))
+			p = p[4:]
+		default:
+			return ""
+		}
+	}
+	return sb.String()
+}
+
+// mockVariable is a variable registered with the mock Tag Name Server.
+type mockVariable struct {
+	id     uint32
+	name   string
+	system bool
+	info   cip.VariableInfo
+}
+
+// mockOmronController answers Tag Name Server, Variable Object and
+// Variable Type Object requests and reads and writes of member paths,
+// packed or not.
+type mockOmronController struct {
+	t         *testing.T
+	variables []mockVariable
+	types     map[uint32]cip.VariableInfo
+	values    map[string][]byte // Read Tag reply by tag expression
+	written   map[string][]byte // Write Tag request data by tag expression
+	lists     int
+}
+
+func (m *mockOmronController) handle(req *cip.MessageRouterRequest) *cip.MessageRouterResponse {
+	if req.Service == cip.ServiceMultipleServicePacket {
+		embedded := decodeMSPRequest(m.t, req.RequestData)
+		replies := make([]*cip.MessageRouterResponse, len(embedded))
+		status := cip.StatusSuccess
+		for i, r := range embedded {
+			replies[i] = m.handle(r)
+			replies[i].Service = r.Service | 0x80
+			if replies[i].GeneralStatus != cip.StatusSuccess {
+				status = cip.StatusEmbeddedServiceError
+			}
+		}
+		return &cip.MessageRouterResponse{GeneralStatus: status, ResponseData: encodeMSPResponse(replies)}
+	}
+
+	p := req.RequestPath
+	switch {
+	case req.Service == cip.ServiceGetInstanceListExtended && bytes.Equal(p, cip.BuildPath(cip.ClassTagNameServer, 0, 0)):
+		m.lists++
+		start := binary.LittleEndian.Uint32(req.RequestData[0:4])
+		count := binary.LittleEndian.Uint32(req.RequestData[4:8])
+		system := cip.VariableKind(binary.LittleEndian.Uint16(req.RequestData[8:10])) == cip.VariablesSystem
+		var n uint32
+		var entries []byte
+		for _, v := range m.variables {
+			if v.id < start || v.system != system || n == count {
+				continue
+			}
+			n++
+			entries = binary.LittleEndian.AppendUint32(entries, v.id)
+			entries = append(entries, byte(len(v.name)))
+			entries = append(entries, v.name...)
+		}
+		return &cip.MessageRouterResponse{ResponseData: append(binary.LittleEndian.AppendUint32(nil, n), entries...)}
+
+	case req.Service == cip.ServiceGetAttributeAll && p[0] == 0x20:
+		var id uint32
+		switch p[2] {
+		case 0x24:
+			id = uint32(p[3])
+		case 0x25:
+			id = uint32(binary.LittleEndian.Uint16(p[4:6]))
+		case 0x26:
+			id = binary.LittleEndian.Uint32(p[4:8])
+		}
+		switch cip.UINT(p[1]) {
+		case cip.ClassVariable:
+			for _, v := range m.variables {
+				if v.id == id {
+					return &cip.MessageRouterResponse{ResponseData: variableInfoReply(v.info)}
+				}
+			}
+		case cip.ClassVariableType:
+			if info, ok := m.types[id]; ok {
+				return &cip.MessageRouterResponse{ResponseData: variableInfoReply(info)}
+			}
+		}
+		return &cip.MessageRouterResponse{GeneralStatus: cip.StatusObjectDoesNotExist}
+
+	case req.Service == cip.ServiceReadTag:
+		if data, ok := m.values[symbolicName(p)]; ok {
+			return &cip.MessageRouterResponse{ResponseData: data}
+		}
+		return &cip.MessageRouterResponse{GeneralStatus: cip.StatusPathDestinationUnknown}
+
+	case req.Service == cip.ServiceWriteTag:
+		if m.written == nil {
+			m.written = make(map[string][]byte)
+		}
+		m.written[symbolicName(p)] = req.RequestData
+		return &cip.MessageRouterResponse{}
+	}
+
+	m.t.Errorf("unexpected request 0x%02X % X", req.Service, []byte(p))
+	return &cip.MessageRouterResponse{GeneralStatus: cip.StatusServiceNotSupported}
+}
+
+// newMockOmron returns a client with ProfileOmronNJ talking to a mock
+// controller with a "Motor" structure type:
+//
+//	Motor: Speed INT, Limits DINT[2], Pos Point, Label STRING
+//	Point: X REAL, Y REAL
+func newMockOmron(t *testing.T) (*Client, *mockOmronController) {
+	m := &mockOmronController{
+		t: t,
+		variables: []mockVariable{
+			{id: 1, name: "Count", info: cip.VariableInfo{Size: 4, Type: cip.TypeDINT}},
+			{id: 2, name: "M", info: cip.VariableInfo{Size: 32, Type: cip.TypeSTRUCT, Nesting: 3, Handle: 0x1234}},
+			{id: 3, name: "Samples", info: cip.VariableInfo{Size: 40, Type: cip.TypeREAL, Dims: 1, Elements: 10}},
+			{id: 4, name: "_CurrentTime", system: true, info: cip.VariableInfo{Size: 8, Type: cip.TypeULINT}},
+		},
+		types: map[uint32]cip.VariableInfo{
+			3:  {Size: 32, Type: cip.TypeSTRUCT, Nesting: 10, Handle: 0x1234, Name: "Motor"},
+			10: {Size: 2, Type: cip.TypeINT, Next: 11, Name: "Speed"},
+			11: {Size: 8, Type: cip.TypeDINT, Dims: 1, Elements: 2, Next: 12, Name: "Limits"},
+			12: {Size: 8, Type: cip.TypeSTRUCT, Nesting: 4, Next: 13, Name: "Pos"},
+			13: {Size: 10, Type: cip.TypeSTRING, Name: "Label"},
+			4:  {Size: 8, Type: cip.TypeSTRUCT, Nesting: 20, Handle: 0x5678, Name: "Point"},
+			20: {Size: 4, Type: cip.TypeREAL, Next: 21, Name: "X"},
+			21: {Size: 4, Type: cip.TypeREAL, Name: "Y"},
+		},
+		values: map[string][]byte{
+			"M.Speed":  {0xC3, 0x00, 0xDC, 0x05},
+			"M.Limits": {0xC4, 0x00, 0x0A, 0, 0, 0, 0x14, 0, 0, 0},
+			"M.Pos.X":  binary.LittleEndian.AppendUint32([]byte{0xCA, 0x00}, math.Float32bits(1.5)),
+			"M.Pos.Y":  binary.LittleEndian.AppendUint32([]byte{0xCA, 0x00}, math.Float32bits(-2)),
+			"M.Label":  {0xD0, 0x00, 0x02, 0x00, 'h', 'i'},
+		},
+	}
+	c := newMockCIPClient(t, m.handle)
+	c.profile = &ProfileOmronNJ
+	return c, m
+}
+
+func TestClient_ListTags_Omron(t *testing.T) {
+	c, m := newMockOmron(t)
+
+	tags, err := c.ListTags()
+	if err != nil {
+		t.Fatalf("ListTags() error = %v", err)
+	}
+	if len(tags) != 3 {
+		t.Fatalf("got %d tags, want 3: %+v", len(tags), tags)
+	}
+	if s := tags[0]; s.Name != "Count" || s.Type != cip.TypeDINT || s.InstanceID != 1 {
+		t.Errorf("Count = %+v", s)
+	}
+	if s := tags[1]; s.Name != "M" || !s.IsStructure() || s.TemplateID() != 3 {
+		t.Errorf("M = %+v", s)
+	}
+	if s := tags[2]; s.Dimensions() != 1 || s.Dims[0] != 10 || s.Type&0x00FF != cip.TypeREAL {
+		t.Errorf("Samples = %+v", s)
+	}
+
+	tags, err = c.ListTags(IncludeSystemTags())
+	if err != nil {
+		t.Fatalf("ListTags(IncludeSystemTags()) error = %v", err)
+	}
+	if len(tags) != 4 || tags[3].Name != "_CurrentTime" {
+		t.Errorf("tags with system variables = %+v", tags)
+	}
+
+	if _, err := c.ListTags(InProgram("Main")); err == nil {
+		t.Error("expected error for program-scoped listing")
+	}
+	if m.lists == 0 {
+		t.Error("Tag Name Server not asked")
+	}
+}
+
+func TestClient_ListTags_OmronPaging(t *testing.T) {
+	c, m := newMockOmron(t)
+	m.variables = nil
+	for i := range 2*variableListPage + 5 {
+		m.variables = append(m.variables, mockVariable{
+			id:   uint32(i + 1),
+			name: fmt.Sprintf("V%03d", i),
+			info: cip.VariableInfo{Size: 2, Type: cip.TypeINT},
+		})
+	}
+
+	tags, err := c.ListTags()
+	if err != nil {
+		t.Fatalf("ListTags() error = %v", err)
+	}
+	if len(tags) != len(m.variables) {
+		t.Errorf("got %d tags, want %d", len(tags), len(m.variables))
+	}
+	if m.lists != 3 {
+		t.Errorf("Get Instance List Extended requests = %d, want 3", m.lists)
+	}
+}
+
+func TestClient_ReadVariableType(t *testing.T) {
+	c, _ := newMockOmron(t)
+
+	vt, err := c.ReadVariableType(3)
+	if err != nil {
+		t.Fatalf("ReadVariableType() error = %v", err)
+	}
+	if vt.Name != "Motor" || vt.Handle != 0x1234 || len(vt.Members) != 4 {
+		t.Fatalf("ReadVariableType() = %+v", vt)
+	}
+	var names []string
+	for _, m := range vt.Members {
+		names = append(names, m.Name)
+	}
+	if want := []string{"Speed", "Limits", "Pos", "Label"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("members = %v, want %v", names, want)
+	}
+
+	again, err := c.ReadVariableType(3)
+	if err != nil || again != vt {
+		t.Error("expected cached variable type")
+	}
+	if _, err := c.ReadVariableType(10); err == nil {
+		t.Error("expected error for a type that is not a structure")
+	}
+}
+
+func TestClient_ReadTagStruct_Omron(t *testing.T) {
+	c, _ := newMockOmron(t)
+
+	got, err := c.ReadTagStruct("M", 3)
+	if err != nil {
+		t.Fatalf("ReadTagStruct() error = %v", err)
+	}
+	want := map[string]any{
+		"Speed":  int16(1500),
+		"Limits": []any{int32(10), int32(20)},
+		"Pos":    map[string]any{"X": float32(1.5), "Y": float32(-2)},
+		"Label":  "hi",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadTagStruct() = %#v, want %#v", got, want)
+	}
+}
+
+func TestClient_WriteTagStruct_Omron(t *testing.T) {
+	c, m := newMockOmron(t)
+
+	err := c.WriteTagStruct("M", 3, map[string]any{
+		"Speed": 1200,
+		"Pos":   map[string]any{"Y": 4.25},
+		"Label": "go",
+	})
+	if err != nil {
+		t.Fatalf("WriteTagStruct() error = %v", err)
+	}
+
+	want := map[string][]byte{
+		"M.Speed": {0xC3, 0x00, 0x01, 0x00, 0xB0, 0x04},
+		"M.Pos.Y": binary.LittleEndian.AppendUint32([]byte{0xCA, 0x00, 0x01, 0x00}, math.Float32bits(4.25)),
+		"M.Label": {0xD0, 0x00, 0x01, 0x00, 0x02, 0x00, 'g', 'o'},
+	}
+	if !reflect.DeepEqual(m.written, want) {
+		t.Errorf("written = % X, want % X", m.written, want)
+	}
+
+	if err := c.WriteTagStruct("M", 3, map[string]any{"Pos": 1}); err == nil {
+		t.Error("expected error for a structure member given a number")
+	}
+}
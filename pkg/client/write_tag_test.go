@@ -31,6 +31,7 @@ func TestClient_WriteTag(t *testing.T) {
 		binary.LittleEndian.PutUint16(resp[2:4], 4)
 		binary.LittleEndian.PutUint32(resp[4:8], 0x01020304)
 		binary.LittleEndian.PutUint16(resp[24:26], 1)
+		copy(resp[12:20], buf[12:20]) // Echo Sender Context
 		conn.Write(resp)
 
 		// Read SendRRData (WriteTag)
@@ -70,6 +71,7 @@ func TestClient_WriteTag(t *testing.T) {
 		copy(cpf[10:], cipData)
 
 		binary.LittleEndian.PutUint16(encap[2:4], uint16(6+len(cpf)))
+		copy(encap[12:20], headerBuf[12:20]) // Echo Sender Context
 		conn.Write(encap)
 		conn.Write([]byte{0, 0, 0, 0, 0, 0})
 		conn.Write(cpf)
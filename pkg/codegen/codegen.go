@@ -0,0 +1,312 @@
+// Package codegen generates Go types for Logix structures.
+//
+// Each cip.Template becomes a Go struct with MarshalCIP and UnmarshalCIP
+// methods that read and write members at the offsets reported by the
+// controller, so Logix alignment and padding are reproduced exactly.
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// Options configures code generation.
+type Options struct {
+	// Package is the package name of the generated file. Defaults to "plc".
+	Package string
+}
+
+// atomicType describes how an atomic CIP type maps to Go.
+type atomicType struct {
+	goType string
+	size   int
+	// decode is a format for reading the value from data[off:], with %s
+	// replaced by the offset expression.
+	decode string
+	// encode is a format for writing value %[2]s at offset %[1]s.
+	encode string
+}
+
+var atomicTypes = map[cip.DataType]atomicType{
+	cip.TypeSINT:  {"int8", 1, "int8(data[%s])", "data[%s] = byte(%s)"},
+	cip.TypeUSINT: {"uint8", 1, "data[%s]", "data[%s] = %s"},
+	cip.TypeBYTE:  {"uint8", 1, "data[%s]", "data[%s] = %s"},
+	cip.TypeINT:   {"int16", 2, "int16(binary.LittleEndian.Uint16(data[%s:]))", "binary.LittleEndian.PutUint16(data[%s:], uint16(%s))"},
+	cip.TypeUINT:  {"uint16", 2, "binary.LittleEndian.Uint16(data[%s:])", "binary.LittleEndian.PutUint16(data[%s:], %s)"},
+	cip.TypeWORD:  {"uint16", 2, "binary.LittleEndian.Uint16(data[%s:])", "binary.LittleEndian.PutUint16(data[%s:], %s)"},
+	cip.TypeDINT:  {"int32", 4, "int32(binary.LittleEndian.Uint32(data[%s:]))", "binary.LittleEndian.PutUint32(data[%s:], uint32(%s))"},
+	cip.TypeUDINT: {"uint32", 4, "binary.LittleEndian.Uint32(data[%s:])", "binary.LittleEndian.PutUint32(data[%s:], %s)"},
+	cip.TypeDWORD: {"uint32", 4, "binary.LittleEndian.Uint32(data[%s:])", "binary.LittleEndian.PutUint32(data[%s:], %s)"},
+	cip.TypeLINT:  {"int64", 8, "int64(binary.LittleEndian.Uint64(data[%s:]))", "binary.LittleEndian.PutUint64(data[%s:], uint64(%s))"},
+	cip.TypeULINT: {"uint64", 8, "binary.LittleEndian.Uint64(data[%s:])", "binary.LittleEndian.PutUint64(data[%s:], %s)"},
+	cip.TypeLWORD: {"uint64", 8, "binary.LittleEndian.Uint64(data[%s:])", "binary.LittleEndian.PutUint64(data[%s:], %s)"},
+	cip.TypeREAL:  {"float32", 4, "math.Float32frombits(binary.LittleEndian.Uint32(data[%s:]))", "binary.LittleEndian.PutUint32(data[%s:], math.Float32bits(%s))"},
+	cip.TypeLREAL: {"float64", 8, "math.Float64frombits(binary.LittleEndian.Uint64(data[%s:]))", "binary.LittleEndian.PutUint64(data[%s:], math.Float64bits(%s))"},
+}
+
+// generator accumulates the output of Generate.
+type generator struct {
+	buf       bytes.Buffer
+	templates map[uint16]*cip.Template
+	names     map[uint16]string
+}
+
+// Generate returns gofmt-formatted Go source declaring one type per template.
+// Every nested structure referenced by a member must be among templates.
+func Generate(templates []*cip.Template, opts Options) ([]byte, error) {
+	pkg := opts.Package
+	if pkg == "" {
+		pkg = "plc"
+	}
+
+	g := &generator{
+		templates: make(map[uint16]*cip.Template, len(templates)),
+		names:     make(map[uint16]string, len(templates)),
+	}
+	for _, t := range templates {
+		g.templates[t.InstanceID] = t
+		g.names[t.InstanceID] = TypeName(t.Name)
+	}
+
+	// Deterministic output ordered by type name
+	sorted := append([]*cip.Template(nil), templates...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return g.names[sorted[i].InstanceID] < g.names[sorted[j].InstanceID]
+	})
+
+	var body bytes.Buffer
+	for _, t := range sorted {
+		if err := g.generateType(t); err != nil {
+			return nil, err
+		}
+		body.Write(g.buf.Bytes())
+		g.buf.Reset()
+	}
+
+	src := body.String()
+	var imports []string
+	if strings.Contains(src, "binary.") {
+		imports = append(imports, `"encoding/binary"`)
+	}
+	imports = append(imports, `"fmt"`)
+	if strings.Contains(src, "math.") {
+		imports = append(imports, `"math"`)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by goeip-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", pkg)
+	fmt.Fprintf(&out, "import (\n\t%s\n)\n\n", strings.Join(imports, "\n\t"))
+	out.WriteString(src)
+
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("codegen: generated invalid source: %w", err)
+	}
+	return formatted, nil
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// visibleMembers returns the members that become struct fields.
+func visibleMembers(t *cip.Template) []cip.TemplateMember {
+	var out []cip.TemplateMember
+	for _, m := range t.Members {
+		if !m.Hidden() {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func (g *generator) generateType(t *cip.Template) error {
+	name := g.names[t.InstanceID]
+	members := visibleMembers(t)
+
+	// Validate members and resolve field types up front
+	fieldTypes := make([]string, len(members))
+	for i, m := range members {
+		ft, err := g.fieldType(m)
+		if err != nil {
+			return fmt.Errorf("codegen: %s.%s: %w", t.Name, m.Name, err)
+		}
+		fieldTypes[i] = ft
+	}
+
+	// Type declaration
+	g.printf("// %s mirrors the Logix structure %q (template 0x%04X, handle 0x%04X).\n", name, t.Name, t.InstanceID, t.Handle)
+	g.printf("//\n// Memory Layout (%d bytes):\n", t.StructureSize)
+	for _, m := range members {
+		g.printf("// Offset %d: %s\n", m.Offset, m.Name)
+	}
+	g.printf("type %s struct {\n", name)
+	for i, m := range members {
+		g.printf("\t%s %s\n", FieldName(m.Name), fieldTypes[i])
+	}
+	g.printf("}\n\n")
+
+	g.printf("// %sSize is the size in bytes of %s in controller memory.\n", name, name)
+	g.printf("const %sSize = %d\n\n", name, t.StructureSize)
+
+	// Decoder
+	g.printf("// UnmarshalCIP implements the Unmarshaler interface for %s.\n", name)
+	g.printf("func (v *%s) UnmarshalCIP(data []byte) error {\n", name)
+	g.printf("\tif len(data) < %sSize {\n", name)
+	g.printf("\t\treturn fmt.Errorf(\"insufficient data for %s: expected at least %%d bytes, got %%d\", %sSize, len(data))\n", name, name)
+	g.printf("\t}\n\n")
+	for _, m := range members {
+		g.decodeMember(m)
+	}
+	g.printf("\treturn nil\n}\n\n")
+
+	// Encoder
+	g.printf("// MarshalCIP implements the Marshaler interface for %s.\n", name)
+	g.printf("func (v *%s) MarshalCIP() ([]byte, error) {\n", name)
+	g.printf("\tdata := make([]byte, %sSize)\n\n", name)
+	for _, m := range members {
+		g.encodeMember(m)
+	}
+	g.printf("\treturn data, nil\n}\n\n")
+
+	return nil
+}
+
+// fieldType returns the Go type of a member.
+func (g *generator) fieldType(m cip.TemplateMember) (string, error) {
+	var elem string
+	switch {
+	case m.IsStructure():
+		name, ok := g.names[m.TemplateID()]
+		if !ok {
+			return "", fmt.Errorf("missing template 0x%04X", m.TemplateID())
+		}
+		elem = name
+	case m.Type() == cip.TypeBOOL:
+		elem = "bool"
+	default:
+		at, ok := atomicTypes[m.Type()]
+		if !ok {
+			return "", fmt.Errorf("unsupported member type %s", m.Type())
+		}
+		elem = at.goType
+	}
+
+	if m.IsArray() {
+		return fmt.Sprintf("[%d]%s", m.Info, elem), nil
+	}
+	return elem, nil
+}
+
+func (g *generator) decodeMember(m cip.TemplateMember) {
+	field := "v." + FieldName(m.Name)
+
+	switch {
+	case m.Type() == cip.TypeBOOL && !m.IsStructure() && !m.IsArray():
+		g.printf("\t%s = data[%d]&(1<<%d) != 0\n", field, m.Offset, m.Info%8)
+
+	case m.Type() == cip.TypeBOOL && !m.IsStructure():
+		g.printf("\tfor i := range %s {\n", field)
+		g.printf("\t\t%s[i] = data[%d+i/8]&(1<<(i%%8)) != 0\n", field, m.Offset)
+		g.printf("\t}\n")
+
+	case m.IsStructure():
+		size := g.templates[m.TemplateID()].StructureSize
+		if m.IsArray() {
+			g.printf("\tfor i := range %s {\n", field)
+			g.printf("\t\toff := %d + i*%d\n", m.Offset, size)
+			g.printf("\t\tif err := %s[i].UnmarshalCIP(data[off : off+%d]); err != nil {\n", field, size)
+			g.printf("\t\t\treturn err\n\t\t}\n\t}\n")
+		} else {
+			g.printf("\tif err := %s.UnmarshalCIP(data[%d:%d]); err != nil {\n", field, m.Offset, m.Offset+size)
+			g.printf("\t\treturn err\n\t}\n")
+		}
+
+	default:
+		at := atomicTypes[m.Type()]
+		if m.IsArray() {
+			g.printf("\tfor i := range %s {\n", field)
+			g.printf("\t\t%s[i] = "+at.decode+"\n", field, fmt.Sprintf("%d+i*%d", m.Offset, at.size))
+			g.printf("\t}\n")
+		} else {
+			g.printf("\t%s = "+at.decode+"\n", field, fmt.Sprint(m.Offset))
+		}
+	}
+}
+
+func (g *generator) encodeMember(m cip.TemplateMember) {
+	field := "v." + FieldName(m.Name)
+
+	switch {
+	case m.Type() == cip.TypeBOOL && !m.IsStructure() && !m.IsArray():
+		g.printf("\tif %s {\n\t\tdata[%d] |= 1 << %d\n\t}\n", field, m.Offset, m.Info%8)
+
+	case m.Type() == cip.TypeBOOL && !m.IsStructure():
+		g.printf("\tfor i, b := range %s {\n", field)
+		g.printf("\t\tif b {\n\t\t\tdata[%d+i/8] |= 1 << (i %% 8)\n\t\t}\n", m.Offset)
+		g.printf("\t}\n")
+
+	case m.IsStructure():
+		size := g.templates[m.TemplateID()].StructureSize
+		if m.IsArray() {
+			g.printf("\tfor i := range %s {\n", field)
+			g.printf("\t\tb, err := %s[i].MarshalCIP()\n", field)
+			g.printf("\t\tif err != nil {\n\t\t\treturn nil, err\n\t\t}\n")
+			g.printf("\t\tcopy(data[%d+i*%d:], b)\n\t}\n", m.Offset, size)
+		} else {
+			// A per-member variable keeps := valid when err is already declared
+			b := "m" + FieldName(m.Name)
+			g.printf("\t%s, err := %s.MarshalCIP()\n", b, field)
+			g.printf("\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+			g.printf("\tcopy(data[%d:], %s)\n", m.Offset, b)
+		}
+
+	default:
+		at := atomicTypes[m.Type()]
+		if m.IsArray() {
+			g.printf("\tfor i, x := range %s {\n", field)
+			g.printf("\t\t"+at.encode+"\n", fmt.Sprintf("%d+i*%d", m.Offset, at.size), "x")
+			g.printf("\t}\n")
+		} else {
+			g.printf("\t"+at.encode+"\n", fmt.Sprint(m.Offset), field)
+		}
+	}
+}
+
+// TypeName converts a Logix structure name into an exported Go identifier.
+func TypeName(name string) string {
+	return exportedIdent(name)
+}
+
+// FieldName converts a Logix member name into an exported Go identifier.
+func FieldName(name string) string {
+	return exportedIdent(name)
+}
+
+func exportedIdent(name string) string {
+	var sb strings.Builder
+	for _, r := range name {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune('_')
+		}
+	}
+	s := sb.String()
+	if s == "" {
+		return "X"
+	}
+
+	first := []rune(s)[0]
+	if !unicode.IsLetter(first) {
+		return "X" + s
+	}
+	return string(unicode.ToUpper(first)) + s[len(string(first)):]
+}
@@ -0,0 +1,133 @@
+package codegen
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+var testTemplates = []*cip.Template{
+	{
+		InstanceID:    0x0123,
+		Name:          "Point",
+		StructureSize: 8,
+		Members: []cip.TemplateMember{
+			{Name: "X", TypeCode: uint16(cip.TypeINT), Offset: 0},
+			{Name: "y", TypeCode: uint16(cip.TypeDINT), Offset: 4},
+		},
+	},
+	{
+		InstanceID:    0x0456,
+		Name:          "My_UDT",
+		StructureSize: 56,
+		Members: []cip.TemplateMember{
+			{Name: "ZZZZZZZZZZMy_UDT0", TypeCode: uint16(cip.TypeSINT), Offset: 0},
+			{Name: "Run", Info: 0, TypeCode: uint16(cip.TypeBOOL), Offset: 0},
+			{Name: "Fault", Info: 1, TypeCode: uint16(cip.TypeBOOL), Offset: 0},
+			{Name: "Count", TypeCode: uint16(cip.TypeDINT), Offset: 4},
+			{Name: "Speeds", Info: 3, TypeCode: 0x2000 | uint16(cip.TypeREAL), Offset: 8},
+			{Name: "Pos", TypeCode: 0x8123, Offset: 20},
+			{Name: "Flags", Info: 32, TypeCode: 0x2000 | uint16(cip.TypeBOOL), Offset: 28},
+			{Name: "Path", Info: 2, TypeCode: 0xA123, Offset: 32},
+			{Name: "Total", TypeCode: uint16(cip.TypeLREAL), Offset: 48},
+		},
+	},
+}
+
+func TestGenerate(t *testing.T) {
+	src, err := Generate(testTemplates, Options{Package: "plc"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	// The output must type check on its own
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "gen.go", src, 0)
+	if err != nil {
+		t.Fatalf("generated source does not parse: %v\n%s", err, src)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("plc", fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatalf("generated source does not type check: %v\n%s", err, src)
+	}
+
+	tests := []struct {
+		typeName string
+		fields   map[string]string
+	}{
+		{"Point", map[string]string{"X": "int16", "Y": "int32"}},
+		{"My_UDT", map[string]string{
+			"Run":    "bool",
+			"Fault":  "bool",
+			"Count":  "int32",
+			"Speeds": "[3]float32",
+			"Pos":    "plc.Point",
+			"Flags":  "[32]bool",
+			"Path":   "[2]plc.Point",
+			"Total":  "float64",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typeName, func(t *testing.T) {
+			obj := pkg.Scope().Lookup(tt.typeName)
+			if obj == nil {
+				t.Fatalf("type %s not generated", tt.typeName)
+			}
+			st, ok := obj.Type().Underlying().(*types.Struct)
+			if !ok {
+				t.Fatalf("%s is not a struct", tt.typeName)
+			}
+			if st.NumFields() != len(tt.fields) {
+				t.Errorf("%s has %d fields, want %d", tt.typeName, st.NumFields(), len(tt.fields))
+			}
+			for i := 0; i < st.NumFields(); i++ {
+				f := st.Field(i)
+				if want := tt.fields[f.Name()]; f.Type().String() != want {
+					t.Errorf("field %s has type %s, want %q", f.Name(), f.Type(), want)
+				}
+			}
+
+			ptr := types.NewPointer(obj.Type())
+			for _, method := range []string{"MarshalCIP", "UnmarshalCIP"} {
+				if m, _, _ := types.LookupFieldOrMethod(ptr, true, pkg, method); m == nil {
+					t.Errorf("%s has no %s method", tt.typeName, method)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerate_MissingTemplate(t *testing.T) {
+	_, err := Generate(testTemplates[1:], Options{})
+	if err == nil {
+		t.Fatal("expected error for missing nested template")
+	}
+}
+
+func TestTypeName(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Point", "Point"},
+		{"motor", "Motor"},
+		{"My_UDT", "My_UDT"},
+		{"1stStage", "X1stStage"},
+		{"Bad:Name", "Bad_Name"},
+		{"", "X"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := TypeName(tt.in); got != tt.want {
+				t.Errorf("TypeName(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
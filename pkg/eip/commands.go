@@ -60,6 +60,7 @@ const (
 	StatusInvalidSessionHandle uint32 = 0x00000064
 	StatusInvalidLength        uint32 = 0x00000065
 	StatusUnsupportedProtocol  uint32 = 0x00000069
+	StatusServiceNotAllowed    uint32 = 0x0000006A
 )
 
 // RegisterSessionData represents the data for Register Session command
@@ -0,0 +1,58 @@
+package eip
+
+import "fmt"
+
+// StatusError is a failed encapsulation status returned by the target.
+type StatusError struct {
+	Command Command
+	Status  uint32
+}
+
+var statusText = map[uint32]string{
+	StatusInvalidCommand:       "invalid or unsupported command",
+	StatusInsufficientMemory:   "insufficient memory",
+	StatusIncorrectData:        "poorly formed or incorrect data",
+	StatusInvalidSessionHandle: "invalid session handle",
+	StatusInvalidLength:        "invalid length",
+	StatusUnsupportedProtocol:  "unsupported protocol revision",
+	StatusServiceNotAllowed:    "encapsulated CIP service not allowed on this port",
+}
+
+// StatusText returns a description of an encapsulation status, or "" if
+// the status is unknown.
+func StatusText(status uint32) string {
+	return statusText[status]
+}
+
+func (e *StatusError) Error() string {
+	if text, ok := statusText[e.Status]; ok {
+		return fmt.Sprintf("%v failed: encapsulation status 0x%04X: %s", e.Command, e.Status, text)
+	}
+	return fmt.Sprintf("%v failed: encapsulation status 0x%04X", e.Command, e.Status)
+}
+
+// Is reports whether target is a StatusError with the same status, whatever
+// its command.
+func (e *StatusError) Is(target error) bool {
+	t, ok := target.(*StatusError)
+	return ok && t.Status == e.Status
+}
+
+// Sentinel errors for use with errors.Is.
+var (
+	ErrInvalidCommand       = &StatusError{Status: StatusInvalidCommand}
+	ErrInsufficientMemory   = &StatusError{Status: StatusInsufficientMemory}
+	ErrIncorrectData        = &StatusError{Status: StatusIncorrectData}
+	ErrInvalidSessionHandle = &StatusError{Status: StatusInvalidSessionHandle}
+	ErrInvalidLength        = &StatusError{Status: StatusInvalidLength}
+	ErrUnsupportedProtocol  = &StatusError{Status: StatusUnsupportedProtocol}
+	ErrServiceNotAllowed    = &StatusError{Status: StatusServiceNotAllowed}
+)
+
+// Err returns the status of h as a *StatusError, or nil if it is success.
+func (h *EncapsulationHeader) Err() error {
+	if h.Status == StatusSuccess {
+		return nil
+	}
+	return &StatusError{Command: h.Command, Status: h.Status}
+}
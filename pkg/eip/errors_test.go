@@ -0,0 +1,28 @@
+package eip
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestEncapsulationHeader_Err(t *testing.T) {
+	h := &EncapsulationHeader{Command: CommandSendRRData}
+	if err := h.Err(); err != nil {
+		t.Fatalf("Err() = %v, want nil", err)
+	}
+
+	h.Status = StatusInvalidSessionHandle
+	err := fmt.Errorf("read tag: %w", h.Err())
+	if !errors.Is(err, ErrInvalidSessionHandle) {
+		t.Errorf("errors.Is(%v, ErrInvalidSessionHandle) = false", err)
+	}
+	if errors.Is(err, ErrInvalidLength) {
+		t.Errorf("errors.Is(%v, ErrInvalidLength) = true", err)
+	}
+
+	want := "read tag: SendRRData failed: encapsulation status 0x0064: invalid session handle"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
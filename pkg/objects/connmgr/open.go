@@ -0,0 +1,220 @@
+package connmgr
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// Connection size limits. Forward_Open carries the size in 9 bits of a
+// 16-bit parameter word; Large_Forward_Open uses a 32-bit word, and Logix
+// accepts up to 4002 bytes.
+const (
+	MaxForwardOpenSize      = 511
+	MaxLargeForwardOpenSize = 4002
+)
+
+// ConnectionType is the connection type of network connection parameters.
+type ConnectionType uint8
+
+const (
+	ConnectionTypeNull         ConnectionType = 0
+	ConnectionTypeMulticast    ConnectionType = 1
+	ConnectionTypePointToPoint ConnectionType = 2
+)
+
+// Priority is the priority of network connection parameters.
+type Priority uint8
+
+const (
+	PriorityLow       Priority = 0
+	PriorityHigh      Priority = 1
+	PriorityScheduled Priority = 2
+	PriorityUrgent    Priority = 3
+)
+
+// NetworkParams are the network connection parameters of one direction of
+// a connection.
+type NetworkParams struct {
+	Size           int // Connection size in bytes
+	Variable       bool
+	Priority       Priority
+	Type           ConnectionType
+	RedundantOwner bool
+}
+
+// Word encodes p as the 16-bit parameters of Forward_Open.
+func (p NetworkParams) Word() cip.WORD {
+	w := cip.WORD(p.Size) & 0x01FF
+	if p.Variable {
+		w |= 1 << 9
+	}
+	w |= cip.WORD(p.Priority&0x03) << 10
+	w |= cip.WORD(p.Type&0x03) << 13
+	if p.RedundantOwner {
+		w |= 1 << 15
+	}
+	return w
+}
+
+// DWord encodes p as the 32-bit parameters of Large_Forward_Open.
+func (p NetworkParams) DWord() cip.DWORD {
+	d := cip.DWORD(p.Size) & 0xFFFF
+	if p.Variable {
+		d |= 1 << 25
+	}
+	d |= cip.DWORD(p.Priority&0x03) << 26
+	d |= cip.DWORD(p.Type&0x03) << 29
+	if p.RedundantOwner {
+		d |= 1 << 31
+	}
+	return d
+}
+
+// OpenParams describes a connection to open from the originator side.
+// Request picks Forward_Open or Large_Forward_Open from the sizes.
+type OpenParams struct {
+	PriorityTimeTick            cip.BYTE
+	TimeoutTicks                cip.USINT
+	OTConnectionID              cip.UDINT // Usually 0, chosen by the target
+	TOConnectionID              cip.UDINT
+	ConnectionSerialNumber      cip.UINT
+	VendorID                    cip.UINT
+	OriginatorSerialNumber      cip.UDINT
+	ConnectionTimeoutMultiplier cip.USINT
+	OTRPI                       cip.UDINT // µs
+	TORPI                       cip.UDINT // µs
+	OT                          NetworkParams
+	TO                          NetworkParams
+	TransportTypeTrigger        cip.BYTE
+	ConnectionPath              cip.Path
+}
+
+// Large reports whether the connection sizes need Large_Forward_Open.
+func (p *OpenParams) Large() bool {
+	return p.OT.Size > MaxForwardOpenSize || p.TO.Size > MaxForwardOpenSize
+}
+
+// ForwardOpenRequest returns the Forward_Open request data for p.
+func (p *OpenParams) ForwardOpenRequest() *ForwardOpenRequest {
+	return &ForwardOpenRequest{
+		PriorityTimeTick:            p.PriorityTimeTick,
+		TimeoutTicks:                p.TimeoutTicks,
+		OTConnectionID:              p.OTConnectionID,
+		TOConnectionID:              p.TOConnectionID,
+		ConnectionSerialNumber:      p.ConnectionSerialNumber,
+		VendorID:                    p.VendorID,
+		OriginatorSerialNumber:      p.OriginatorSerialNumber,
+		ConnectionTimeoutMultiplier: p.ConnectionTimeoutMultiplier,
+		OTRPI:                       p.OTRPI,
+		OTNetworkConnectionParams:   p.OT.Word(),
+		TORPI:                       p.TORPI,
+		TONetworkConnectionParams:   p.TO.Word(),
+		TransportTypeTrigger:        p.TransportTypeTrigger,
+		ConnectionPathSize:          cip.USINT(p.ConnectionPath.LenWords()),
+		ConnectionPath:              p.ConnectionPath,
+	}
+}
+
+// LargeForwardOpenRequest returns the Large_Forward_Open request data for p.
+func (p *OpenParams) LargeForwardOpenRequest() *LargeForwardOpenRequest {
+	return &LargeForwardOpenRequest{
+		PriorityTimeTick:            p.PriorityTimeTick,
+		TimeoutTicks:                p.TimeoutTicks,
+		OTConnectionID:              p.OTConnectionID,
+		TOConnectionID:              p.TOConnectionID,
+		ConnectionSerialNumber:      p.ConnectionSerialNumber,
+		VendorID:                    p.VendorID,
+		OriginatorSerialNumber:      p.OriginatorSerialNumber,
+		ConnectionTimeoutMultiplier: p.ConnectionTimeoutMultiplier,
+		OTRPI:                       p.OTRPI,
+		OTNetworkConnectionParams:   p.OT.DWord(),
+		TORPI:                       p.TORPI,
+		TONetworkConnectionParams:   p.TO.DWord(),
+		TransportTypeTrigger:        p.TransportTypeTrigger,
+		ConnectionPathSize:          cip.USINT(p.ConnectionPath.LenWords()),
+		ConnectionPath:              p.ConnectionPath,
+	}
+}
+
+// Request returns the Connection Manager request opening the connection:
+// Large_Forward_Open if a size exceeds MaxForwardOpenSize, Forward_Open
+// otherwise.
+func (p *OpenParams) Request() *cip.MessageRouterRequest {
+	if p.Large() {
+		return &cip.MessageRouterRequest{
+			Service:     ServiceLargeForwardOpen,
+			RequestPath: Path(),
+			RequestData: p.LargeForwardOpenRequest().Encode(),
+		}
+	}
+	return &cip.MessageRouterRequest{
+		Service:     ServiceForwardOpen,
+		RequestPath: Path(),
+		RequestData: p.ForwardOpenRequest().Encode(),
+	}
+}
+
+// CloseRequest returns the Forward_Close request closing the connection
+// opened with p.
+func (p *OpenParams) CloseRequest() *cip.MessageRouterRequest {
+	req := &ForwardCloseRequest{
+		PriorityTimeTick:       p.PriorityTimeTick,
+		TimeoutTicks:           p.TimeoutTicks,
+		ConnectionSerialNumber: p.ConnectionSerialNumber,
+		VendorID:               p.VendorID,
+		OriginatorSerialNumber: p.OriginatorSerialNumber,
+		ConnectionPathSize:     cip.USINT(p.ConnectionPath.LenWords()),
+		ConnectionPath:         p.ConnectionPath,
+	}
+	return &cip.MessageRouterRequest{
+		Service:     ServiceForwardClose,
+		RequestPath: Path(),
+		RequestData: req.Encode(),
+	}
+}
+
+// Sender sends a request to the Connection Manager of the target and
+// returns its reply.
+type Sender func(req *cip.MessageRouterRequest) (*cip.MessageRouterResponse, error)
+
+// Open opens the connection described by p. If the target does not support
+// Large_Forward_Open (status 0x08), the connection is opened with
+// Forward_Open instead, with the sizes reduced to MaxForwardOpenSize.
+// It returns the reply and the parameters the connection was opened with.
+func Open(send Sender, p OpenParams) (*ForwardOpenResponse, OpenParams, error) {
+	resp, err := open(send, &p)
+	if p.Large() && isServiceNotSupported(err) {
+		p.OT.Size = min(p.OT.Size, MaxForwardOpenSize)
+		p.TO.Size = min(p.TO.Size, MaxForwardOpenSize)
+		resp, err = open(send, &p)
+	}
+	if err != nil {
+		return nil, p, err
+	}
+	return resp, p, nil
+}
+
+func open(send Sender, p *OpenParams) (*ForwardOpenResponse, error) {
+	req := p.Request()
+	name := "forward open"
+	if req.Service == ServiceLargeForwardOpen {
+		name = "large forward open"
+	}
+
+	resp, err := send(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if err := resp.Error(); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return DecodeForwardOpenResponse(resp.ResponseData)
+}
+
+// isServiceNotSupported reports whether err is a CIP error with status 0x08.
+func isServiceNotSupported(err error) bool {
+	var cipErr cip.Error
+	return errors.As(err, &cipErr) && cipErr.Status == cip.StatusServiceNotSupported
+}
@@ -0,0 +1,124 @@
+package connmgr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// Path returns the request path of the Connection Manager instance that
+// handles Forward_Open and Forward_Close.
+func Path() cip.Path {
+	return cip.BuildPath(cip.ClassConnectionMgr, 1, 0)
+}
+
+// Encode encodes the Forward_Open request data.
+// ConnectionPathSize is derived from ConnectionPath.
+func (r *ForwardOpenRequest) Encode() []byte {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, r.PriorityTimeTick)
+	binary.Write(buf, binary.LittleEndian, r.TimeoutTicks)
+	binary.Write(buf, binary.LittleEndian, r.OTConnectionID)
+	binary.Write(buf, binary.LittleEndian, r.TOConnectionID)
+	binary.Write(buf, binary.LittleEndian, r.ConnectionSerialNumber)
+	binary.Write(buf, binary.LittleEndian, r.VendorID)
+	binary.Write(buf, binary.LittleEndian, r.OriginatorSerialNumber)
+	binary.Write(buf, binary.LittleEndian, r.ConnectionTimeoutMultiplier)
+	binary.Write(buf, binary.LittleEndian, r.Reserved)
+	binary.Write(buf, binary.LittleEndian, r.OTRPI)
+	binary.Write(buf, binary.LittleEndian, r.OTNetworkConnectionParams)
+	binary.Write(buf, binary.LittleEndian, r.TORPI)
+	binary.Write(buf, binary.LittleEndian, r.TONetworkConnectionParams)
+	binary.Write(buf, binary.LittleEndian, r.TransportTypeTrigger)
+	writeConnectionPath(buf, r.ConnectionPath)
+	return buf.Bytes()
+}
+
+// Encode encodes the Large_Forward_Open request data.
+// ConnectionPathSize is derived from ConnectionPath.
+func (r *LargeForwardOpenRequest) Encode() []byte {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, r.PriorityTimeTick)
+	binary.Write(buf, binary.LittleEndian, r.TimeoutTicks)
+	binary.Write(buf, binary.LittleEndian, r.OTConnectionID)
+	binary.Write(buf, binary.LittleEndian, r.TOConnectionID)
+	binary.Write(buf, binary.LittleEndian, r.ConnectionSerialNumber)
+	binary.Write(buf, binary.LittleEndian, r.VendorID)
+	binary.Write(buf, binary.LittleEndian, r.OriginatorSerialNumber)
+	binary.Write(buf, binary.LittleEndian, r.ConnectionTimeoutMultiplier)
+	binary.Write(buf, binary.LittleEndian, r.Reserved)
+	binary.Write(buf, binary.LittleEndian, r.OTRPI)
+	binary.Write(buf, binary.LittleEndian, r.OTNetworkConnectionParams)
+	binary.Write(buf, binary.LittleEndian, r.TORPI)
+	binary.Write(buf, binary.LittleEndian, r.TONetworkConnectionParams)
+	binary.Write(buf, binary.LittleEndian, r.TransportTypeTrigger)
+	writeConnectionPath(buf, r.ConnectionPath)
+	return buf.Bytes()
+}
+
+// Encode encodes the Forward_Close request data.
+// ConnectionPathSize is derived from ConnectionPath.
+func (r *ForwardCloseRequest) Encode() []byte {
+	path := cip.Path(r.ConnectionPath)
+
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, r.PriorityTimeTick)
+	binary.Write(buf, binary.LittleEndian, r.TimeoutTicks)
+	binary.Write(buf, binary.LittleEndian, r.ConnectionSerialNumber)
+	binary.Write(buf, binary.LittleEndian, r.VendorID)
+	binary.Write(buf, binary.LittleEndian, r.OriginatorSerialNumber)
+	buf.WriteByte(path.LenWords())
+	buf.WriteByte(0) // Reserved
+	buf.Write(path)
+	if len(path)%2 != 0 {
+		buf.WriteByte(0)
+	}
+	return buf.Bytes()
+}
+
+// writeConnectionPath writes the path size in words followed by the path,
+// padded to an even length.
+func writeConnectionPath(buf *bytes.Buffer, p []byte) {
+	path := cip.Path(p)
+	buf.WriteByte(path.LenWords())
+	buf.Write(path)
+	if len(path)%2 != 0 {
+		buf.WriteByte(0)
+	}
+}
+
+// DecodeForwardOpenResponse decodes the data of a successful Forward_Open
+// or Large_Forward_Open reply; both have the same layout.
+func DecodeForwardOpenResponse(data []byte) (*ForwardOpenResponse, error) {
+	r := bytes.NewReader(data)
+	resp := &ForwardOpenResponse{}
+
+	fields := []any{
+		&resp.OTConnectionID,
+		&resp.TOConnectionID,
+		&resp.ConnectionSerialNumber,
+		&resp.VendorID,
+		&resp.OriginatorSerialNumber,
+		&resp.OTAPI,
+		&resp.TOAPI,
+		&resp.ApplicationReplySize,
+		&resp.Reserved,
+	}
+	for _, f := range fields {
+		if err := binary.Read(r, binary.LittleEndian, f); err != nil {
+			return nil, fmt.Errorf("forward open response: %w", err)
+		}
+	}
+
+	if resp.ApplicationReplySize > 0 {
+		resp.ApplicationReply = make([]byte, int(resp.ApplicationReplySize)*2)
+		if _, err := io.ReadFull(r, resp.ApplicationReply); err != nil {
+			return nil, fmt.Errorf("forward open response: application reply: %w", err)
+		}
+	}
+
+	return resp, nil
+}
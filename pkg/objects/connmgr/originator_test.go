@@ -0,0 +1,180 @@
+package connmgr
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+func TestForwardOpenRequest_Encode(t *testing.T) {
+	req := &ForwardOpenRequest{
+		PriorityTimeTick:            0x0A,
+		TimeoutTicks:                0x05,
+		TOConnectionID:              0x01020304,
+		ConnectionSerialNumber:      0x1122,
+		VendorID:                    0x1337,
+		OriginatorSerialNumber:      0xAABBCCDD,
+		ConnectionTimeoutMultiplier: 3,
+		OTRPI:                       2000000,
+		OTNetworkConnectionParams:   0x43FA,
+		TORPI:                       2000000,
+		TONetworkConnectionParams:   0x43FA,
+		TransportTypeTrigger:        0xA3,
+		ConnectionPath:              cip.BuildPath(cip.ClassMessageRouter, 1, 0),
+	}
+
+	data := req.Encode()
+	if len(data) != 36+4 {
+		t.Fatalf("len(Encode()) = %d, want 40", len(data))
+	}
+	if data[35] != 2 || !bytes.Equal(data[36:], []byte{0x20, 0x02, 0x24, 0x01}) {
+		t.Errorf("connection path = % X", data[35:])
+	}
+
+	// The adapter side must accept what the originator side encodes
+	cm := NewConnectionManager()
+	reply, err := cm.HandleForwardOpen(data)
+	if err != nil {
+		t.Fatalf("HandleForwardOpen() error = %v", err)
+	}
+
+	resp, err := DecodeForwardOpenResponse(reply)
+	if err != nil {
+		t.Fatalf("DecodeForwardOpenResponse() error = %v", err)
+	}
+	if resp.ConnectionSerialNumber != req.ConnectionSerialNumber ||
+		resp.VendorID != req.VendorID ||
+		resp.OriginatorSerialNumber != req.OriginatorSerialNumber {
+		t.Errorf("connection triad = %04X/%04X/%08X, want %04X/%04X/%08X",
+			resp.ConnectionSerialNumber, resp.VendorID, resp.OriginatorSerialNumber,
+			req.ConnectionSerialNumber, req.VendorID, req.OriginatorSerialNumber)
+	}
+	if resp.OTAPI != req.OTRPI || resp.TOAPI != req.TORPI {
+		t.Errorf("API = %d/%d, want %d/%d", resp.OTAPI, resp.TOAPI, req.OTRPI, req.TORPI)
+	}
+}
+
+func TestForwardCloseRequest_Encode(t *testing.T) {
+	req := &ForwardCloseRequest{
+		PriorityTimeTick:       0x0A,
+		TimeoutTicks:           0x05,
+		ConnectionSerialNumber: 0x1122,
+		VendorID:               0x1337,
+		OriginatorSerialNumber: 0xAABBCCDD,
+		ConnectionPath:         cip.BuildPath(cip.ClassMessageRouter, 1, 0),
+	}
+
+	want := []byte{
+		0x0A, 0x05,
+		0x22, 0x11,
+		0x37, 0x13,
+		0xDD, 0xCC, 0xBB, 0xAA,
+		0x02, 0x00,
+		0x20, 0x02, 0x24, 0x01,
+	}
+	data := req.Encode()
+	if !bytes.Equal(data, want) {
+		t.Fatalf("Encode() = % X, want % X", data, want)
+	}
+
+	if _, err := NewConnectionManager().HandleForwardClose(data); err != nil {
+		t.Fatalf("HandleForwardClose() error = %v", err)
+	}
+}
+
+func TestDecodeForwardOpenResponse_Short(t *testing.T) {
+	if _, err := DecodeForwardOpenResponse(make([]byte, 10)); err == nil {
+		t.Fatal("expected error for truncated reply")
+	}
+}
+
+func TestNetworkParams(t *testing.T) {
+	p := NetworkParams{
+		Size:     504,
+		Variable: true,
+		Priority: PriorityLow,
+		Type:     ConnectionTypePointToPoint,
+	}
+	if got := p.Word(); got != 0x43F8 {
+		t.Errorf("Word() = 0x%04X, want 0x43F8", got)
+	}
+
+	p.Size = 4002
+	if got := p.DWord(); got != 0x42000FA2 {
+		t.Errorf("DWord() = 0x%08X, want 0x42000FA2", got)
+	}
+}
+
+func TestOpenParams_Request(t *testing.T) {
+	p := OpenParams{
+		OT:             NetworkParams{Size: 500},
+		TO:             NetworkParams{Size: 500},
+		ConnectionPath: cip.BuildPath(cip.ClassMessageRouter, 1, 0),
+	}
+	if got := p.Request(); got.Service != ServiceForwardOpen || len(got.RequestData) != 40 {
+		t.Errorf("Request() = service 0x%02X, %d bytes, want Forward_Open, 40 bytes", got.Service, len(got.RequestData))
+	}
+
+	p.TO.Size = 4002
+	if got := p.Request(); got.Service != ServiceLargeForwardOpen || len(got.RequestData) != 44 {
+		t.Errorf("Request() = service 0x%02X, %d bytes, want Large_Forward_Open, 44 bytes", got.Service, len(got.RequestData))
+	}
+}
+
+func TestOpen_FallsBackToForwardOpen(t *testing.T) {
+	cm := NewConnectionManager()
+	var services []cip.USINT
+
+	send := func(req *cip.MessageRouterRequest) (*cip.MessageRouterResponse, error) {
+		services = append(services, req.Service)
+		if req.Service == ServiceLargeForwardOpen {
+			return &cip.MessageRouterResponse{GeneralStatus: cip.StatusServiceNotSupported}, nil
+		}
+		data, err := cm.HandleRequest(req.Service, req.RequestPath, req.RequestData)
+		if err != nil {
+			return nil, err
+		}
+		return &cip.MessageRouterResponse{ResponseData: data}, nil
+	}
+
+	network := NetworkParams{Size: MaxLargeForwardOpenSize, Type: ConnectionTypePointToPoint}
+	resp, params, err := Open(send, OpenParams{
+		TOConnectionID: 0x1234,
+		OT:             network,
+		TO:             network,
+		ConnectionPath: cip.BuildPath(cip.ClassMessageRouter, 1, 0),
+	})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	if len(services) != 2 || services[0] != ServiceLargeForwardOpen || services[1] != ServiceForwardOpen {
+		t.Errorf("services = % X, want Large_Forward_Open then Forward_Open", services)
+	}
+	if params.OT.Size != MaxForwardOpenSize || params.TO.Size != MaxForwardOpenSize {
+		t.Errorf("sizes = %d/%d, want %d", params.OT.Size, params.TO.Size, MaxForwardOpenSize)
+	}
+	if resp.TOConnectionID == 0 {
+		t.Error("missing connection ID in reply")
+	}
+}
+
+func TestOpen_OtherErrorsDoNotFallBack(t *testing.T) {
+	calls := 0
+	send := func(req *cip.MessageRouterRequest) (*cip.MessageRouterResponse, error) {
+		calls++
+		return &cip.MessageRouterResponse{
+			GeneralStatus: cip.StatusConnectionFailure,
+			ExtStatus:     []cip.UINT{ExtStatusConnectionInUse},
+		}, nil
+	}
+
+	network := NetworkParams{Size: MaxLargeForwardOpenSize}
+	if _, _, err := Open(send, OpenParams{OT: network, TO: network}); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
@@ -20,15 +20,22 @@ const (
 	StatusConnectionFailure cip.USINT = 0x01
 )
 
-// Extended Status Codes for Connection Failure
+// Extended Status Codes for Connection Failure. cip.ExtStatusText
+// describes these and the other Connection Manager codes. Earlier releases
+// had ExtStatusConnectionNotFound as 0x0109 and ExtStatusInvalidParam as
+// 0x0311; those codes are ExtStatusInvalidConnectionSize and
+// ExtStatusPortNotAvailable.
 const (
-	ExtStatusConnectionInUse     cip.UINT = 0x0100
-	ExtStatusTransportNotSupp    cip.UINT = 0x0103
-	ExtStatusOwnershipConflict   cip.UINT = 0x0106
-	ExtStatusConnectionNotFound  cip.UINT = 0x0109
-	ExtStatusInvalidSegmentType  cip.UINT = 0x0315
-	ExtStatusInvalidParam        cip.UINT = 0x0311 // Or similar
-	ExtStatusVendorSpecificError cip.UINT = 0x031C
+	ExtStatusConnectionInUse       cip.UINT = 0x0100
+	ExtStatusTransportNotSupp      cip.UINT = 0x0103
+	ExtStatusOwnershipConflict     cip.UINT = 0x0106
+	ExtStatusConnectionNotFound    cip.UINT = 0x0107
+	ExtStatusInvalidParam          cip.UINT = 0x0108 // Invalid network connection parameter
+	ExtStatusInvalidConnectionSize cip.UINT = 0x0109
+	ExtStatusOutOfConnections      cip.UINT = 0x0113
+	ExtStatusPortNotAvailable      cip.UINT = 0x0311
+	ExtStatusInvalidSegmentType    cip.UINT = 0x0315
+	ExtStatusVendorSpecificError   cip.UINT = 0x031C
 )
 
 // ForwardOpenRequest represents the data for a Forward_Open service
@@ -0,0 +1,118 @@
+package connmgr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// Extended Status Codes for routing failures of Unconnected Send
+const (
+	ExtStatusUnconnectedSendTimeout cip.UINT = 0x0204
+	ExtStatusInvalidLinkAddress     cip.UINT = 0x0312
+)
+
+// replyUnconnectedSend is the reply service of Unconnected Send. Targets
+// answer a routed request with the embedded reply; a reply with this
+// service means the request was not delivered.
+const replyUnconnectedSend = ServiceUnconnectedSend | 0x80
+
+// UnconnectedSendRequest represents the data for an Unconnected_Send service
+type UnconnectedSendRequest struct {
+	PriorityTimeTick cip.BYTE
+	TimeoutTicks     cip.USINT
+	Request          *cip.MessageRouterRequest // Embedded message request
+	RoutePath        cip.Path
+}
+
+// Encode encodes the Unconnected_Send request data.
+func (r *UnconnectedSendRequest) Encode() ([]byte, error) {
+	msg, err := r.Request.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, r.PriorityTimeTick)
+	binary.Write(buf, binary.LittleEndian, r.TimeoutTicks)
+	binary.Write(buf, binary.LittleEndian, cip.UINT(len(msg)))
+	buf.Write(msg)
+	if len(msg)%2 != 0 {
+		buf.WriteByte(0) // Pad
+	}
+	buf.WriteByte(r.RoutePath.LenWords())
+	buf.WriteByte(0) // Reserved
+	buf.Write(r.RoutePath)
+	if len(r.RoutePath)%2 != 0 {
+		buf.WriteByte(0)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnconnectedSend wraps req in an Unconnected Send request to the local
+// Connection Manager, which forwards it along route.
+func UnconnectedSend(req *cip.MessageRouterRequest, route cip.Path, priorityTimeTick cip.BYTE, timeoutTicks cip.USINT) (*cip.MessageRouterRequest, error) {
+	data, err := (&UnconnectedSendRequest{
+		PriorityTimeTick: priorityTimeTick,
+		TimeoutTicks:     timeoutTicks,
+		Request:          req,
+		RoutePath:        route,
+	}).Encode()
+	if err != nil {
+		return nil, fmt.Errorf("unconnected send: %w", err)
+	}
+	return &cip.MessageRouterRequest{
+		Service:     ServiceUnconnectedSend,
+		RequestPath: Path(),
+		RequestData: data,
+	}, nil
+}
+
+// UnconnectedSendTimeout returns the priority/time tick and timeout ticks
+// for a timeout of d, using the smallest tick that can express it.
+// The timeout is 2^tick ms times the ticks, so d is rounded up to the
+// resolution of the tick.
+func UnconnectedSendTimeout(d time.Duration) (cip.BYTE, cip.USINT) {
+	ms := d.Milliseconds()
+	for tick := 0; tick <= 0x0F; tick++ {
+		unit := int64(1) << tick
+		if ticks := (ms + unit - 1) / unit; ticks <= 0xFF {
+			return cip.BYTE(tick), cip.USINT(max(ticks, 1))
+		}
+	}
+	return 0x0F, 0xFF
+}
+
+// RouteError is returned when a routed request did not reach its target.
+// RemainingPathSize is the number of route words that were not consumed,
+// which tells how far along the route the request went.
+type RouteError struct {
+	Err               cip.Error
+	RemainingPathSize cip.USINT
+}
+
+func (e *RouteError) Error() string {
+	return fmt.Sprintf("unconnected send: %v (%d route words remaining)", e.Err, e.RemainingPathSize)
+}
+
+func (e *RouteError) Unwrap() error {
+	return e.Err
+}
+
+// UnwrapUnconnectedSend returns the reply of the request embedded in an
+// Unconnected Send. If the request was not delivered, it returns a
+// *RouteError.
+func UnwrapUnconnectedSend(resp *cip.MessageRouterResponse) (*cip.MessageRouterResponse, error) {
+	if resp.Service != replyUnconnectedSend || resp.IsSuccess() {
+		return resp, nil
+	}
+
+	routeErr := &RouteError{Err: resp.Error().(cip.Error)}
+	if len(resp.ResponseData) > 0 {
+		routeErr.RemainingPathSize = cip.USINT(resp.ResponseData[0])
+	}
+	return nil, routeErr
+}
@@ -0,0 +1,107 @@
+package connmgr
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+func TestUnconnectedSend(t *testing.T) {
+	route, err := cip.ParseRoutePath("1,3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &cip.MessageRouterRequest{
+		Service:     cip.ServiceReadTag,
+		RequestPath: cip.Path{0x91, 0x01, 'A', 0x00},
+		RequestData: []byte{0x01, 0x00},
+	}
+
+	routed, err := UnconnectedSend(req, route, 0x0A, 0x05)
+	if err != nil {
+		t.Fatalf("UnconnectedSend() error = %v", err)
+	}
+	if routed.Service != ServiceUnconnectedSend || !bytes.Equal(routed.RequestPath, Path()) {
+		t.Errorf("request = service 0x%02X path %v, want Unconnected Send to the Connection Manager", routed.Service, routed.RequestPath)
+	}
+
+	want := []byte{
+		0x0A, 0x05,
+		0x08, 0x00, // Embedded message size
+		0x4C, 0x02, 0x91, 0x01, 'A', 0x00, 0x01, 0x00,
+		0x01, 0x00, // Route size, reserved
+		0x01, 0x03,
+	}
+	if !bytes.Equal(routed.RequestData, want) {
+		t.Errorf("RequestData = % X, want % X", routed.RequestData, want)
+	}
+}
+
+func TestUnconnectedSend_PadsOddMessage(t *testing.T) {
+	req := &cip.MessageRouterRequest{
+		Service:     cip.ServiceGetAttributeSingle,
+		RequestPath: cip.BuildPath(0x01, 1, 1),
+		RequestData: []byte{0xFF},
+	}
+	routed, err := UnconnectedSend(req, cip.Path{0x01, 0x00}, 0x0A, 0x05)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 8-byte message plus one data byte, padded before the route
+	data := routed.RequestData
+	if data[2] != 9 || data[4+9] != 0 || !bytes.Equal(data[4+10:], []byte{0x01, 0x00, 0x01, 0x00}) {
+		t.Errorf("RequestData = % X", data)
+	}
+}
+
+func TestUnwrapUnconnectedSend(t *testing.T) {
+	embedded := &cip.MessageRouterResponse{Service: cip.ServiceReadTag | 0x80, GeneralStatus: 0x04}
+	if got, err := UnwrapUnconnectedSend(embedded); err != nil || got != embedded {
+		t.Errorf("UnwrapUnconnectedSend(embedded) = %v, %v, want the reply itself", got, err)
+	}
+
+	failed := &cip.MessageRouterResponse{
+		Service:       ServiceUnconnectedSend | 0x80,
+		GeneralStatus: StatusConnectionFailure,
+		ExtStatus:     []cip.UINT{ExtStatusInvalidLinkAddress},
+		ResponseData:  []byte{0x02, 0x00},
+	}
+	_, err := UnwrapUnconnectedSend(failed)
+
+	var routeErr *RouteError
+	if !errors.As(err, &routeErr) {
+		t.Fatalf("error = %v, want *RouteError", err)
+	}
+	if routeErr.RemainingPathSize != 2 {
+		t.Errorf("RemainingPathSize = %d, want 2", routeErr.RemainingPathSize)
+	}
+	var cipErr cip.Error
+	if !errors.As(err, &cipErr) || cipErr.ExtStatus[0] != ExtStatusInvalidLinkAddress {
+		t.Errorf("error = %v, want CIP error with ext status 0x0312", err)
+	}
+}
+
+func TestUnconnectedSendTimeout(t *testing.T) {
+	tests := []struct {
+		d     time.Duration
+		tick  cip.BYTE
+		ticks cip.USINT
+	}{
+		{0, 0, 1},
+		{200 * time.Millisecond, 0, 200},
+		{time.Second, 2, 250},
+		{5 * time.Second, 5, 157},
+		{time.Hour, 14, 220},
+		{3 * time.Hour, 0x0F, 0xFF},
+	}
+	for _, tt := range tests {
+		tick, ticks := UnconnectedSendTimeout(tt.d)
+		if tick != tt.tick || ticks != tt.ticks {
+			t.Errorf("UnconnectedSendTimeout(%v) = %d, %d, want %d, %d", tt.d, tick, ticks, tt.tick, tt.ticks)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package ethlink
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// Ethernet Link Object (Class 0xF6) attributes. Each Ethernet port is an
+// instance, starting at 1.
+const (
+	AttrInterfaceSpeed    cip.UINT = 1  // UDINT: Mbit/s
+	AttrInterfaceFlags    cip.UINT = 2  // DWORD
+	AttrPhysicalAddress   cip.UINT = 3  // MAC address
+	AttrInterfaceCounters cip.UINT = 4  // See InterfaceCounters
+	AttrMediaCounters     cip.UINT = 5  // See MediaCounters
+	AttrInterfaceLabel    cip.UINT = 10 // SHORT_STRING, e.g. "Port 1"
+)
+
+// Link holds the attributes of an Ethernet Link Object instance.
+type Link struct {
+	Instance  uint16
+	Speed     uint32 // Mbit/s
+	Flags     Flags
+	MAC       net.HardwareAddr
+	Label     string             // Empty if the device has no labels
+	Interface *InterfaceCounters // nil if not supported
+	Media     *MediaCounters     // nil if not supported
+}
+
+// InterfaceCounters are the interface counters (attribute 4), as in the
+// MIB-II interfaces group.
+type InterfaceCounters struct {
+	InOctets         uint32
+	InUcastPackets   uint32
+	InNUcastPackets  uint32
+	InDiscards       uint32
+	InErrors         uint32
+	InUnknownProtos  uint32
+	OutOctets        uint32
+	OutUcastPackets  uint32
+	OutNUcastPackets uint32
+	OutDiscards      uint32
+	OutErrors        uint32
+}
+
+// MediaCounters are the media counters (attribute 5), as in the Ethernet
+// MIB.
+type MediaCounters struct {
+	AlignmentErrors     uint32
+	FCSErrors           uint32 // Frames with a bad CRC
+	SingleCollisions    uint32
+	MultipleCollisions  uint32
+	SQETestErrors       uint32
+	DeferredTransmits   uint32
+	LateCollisions      uint32 // Typical of a duplex mismatch
+	ExcessiveCollisions uint32
+	MACTransmitErrors   uint32
+	CarrierSenseErrors  uint32
+	FrameTooLong        uint32
+	MACReceiveErrors    uint32
+}
+
+// Flags is the interface flags word (attribute 2).
+type Flags uint32
+
+// Interface flag bits
+const (
+	FlagLinkActive    Flags = 1 << 0
+	FlagFullDuplex    Flags = 1 << 1
+	FlagResetRequired Flags = 1 << 5 // Manual settings take effect at the next reset
+	FlagHardwareFault Flags = 1 << 6
+)
+
+// LinkActive reports whether the port has a link.
+func (f Flags) LinkActive() bool {
+	return f&FlagLinkActive != 0
+}
+
+// FullDuplex reports whether the port runs full duplex.
+func (f Flags) FullDuplex() bool {
+	return f&FlagFullDuplex != 0
+}
+
+// HardwareFault reports whether the port detected a hardware fault.
+func (f Flags) HardwareFault() bool {
+	return f&FlagHardwareFault != 0
+}
+
+// Negotiation returns the auto-negotiation status (bits 2-4).
+func (f Flags) Negotiation() Negotiation {
+	return Negotiation(f >> 2 & 0x07)
+}
+
+// Negotiation is the auto-negotiation status of a port.
+type Negotiation uint8
+
+const (
+	NegotiationInProgress   Negotiation = 0
+	NegotiationFailed       Negotiation = 1 // Using default speed and duplex
+	NegotiationDuplexFail   Negotiation = 2 // Speed detected, using default duplex
+	NegotiationSuccess      Negotiation = 3
+	NegotiationNotAttempted Negotiation = 4 // Forced speed and duplex
+)
+
+func (n Negotiation) String() string {
+	switch n {
+	case NegotiationInProgress:
+		return "in progress"
+	case NegotiationFailed:
+		return "failed, using default speed and duplex"
+	case NegotiationDuplexFail:
+		return "duplex detection failed, using default duplex"
+	case NegotiationSuccess:
+		return "successful"
+	case NegotiationNotAttempted:
+		return "not attempted, forced speed and duplex"
+	}
+	return fmt.Sprintf("unknown (%d)", uint8(n))
+}
+
+// NewGetAttributeRequest creates a Get_Attribute_Single request for an
+// attribute of an Ethernet Link instance.
+func NewGetAttributeRequest(instance uint16, attr cip.UINT) *cip.MessageRouterRequest {
+	return cip.NewGetAttributeSingleRequest(cip.BuildPath(cip.ClassEthernetLink, cip.UINT(instance), attr))
+}
+
+// DecodeAttribute decodes the value of attribute attr into link.
+// Attributes Link does not hold are ignored.
+func DecodeAttribute(link *Link, attr cip.UINT, data []byte) error {
+	var err error
+	switch attr {
+	case AttrInterfaceSpeed:
+		err = binary.Read(bytes.NewReader(data), binary.LittleEndian, &link.Speed)
+	case AttrInterfaceFlags:
+		err = binary.Read(bytes.NewReader(data), binary.LittleEndian, &link.Flags)
+	case AttrPhysicalAddress:
+		if len(data) < 6 {
+			err = fmt.Errorf("need 6 bytes, got %d", len(data))
+		} else {
+			link.MAC = net.HardwareAddr(bytes.Clone(data[:6]))
+		}
+	case AttrInterfaceCounters:
+		c := &InterfaceCounters{}
+		if err = binary.Read(bytes.NewReader(data), binary.LittleEndian, c); err == nil {
+			link.Interface = c
+		}
+	case AttrMediaCounters:
+		c := &MediaCounters{}
+		if err = binary.Read(bytes.NewReader(data), binary.LittleEndian, c); err == nil {
+			link.Media = c
+		}
+	case AttrInterfaceLabel:
+		link.Label, err = cip.DecodeString(cip.TypeSHORT_STRING, data)
+	}
+	if err != nil {
+		return fmt.Errorf("ethlink: attribute %d: %w", attr, err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package ethlink
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+func TestNewGetAttributeRequest(t *testing.T) {
+	req := NewGetAttributeRequest(2, AttrMediaCounters)
+	if req.Service != cip.ServiceGetAttributeSingle {
+		t.Errorf("Service = 0x%02X, want 0x0E", req.Service)
+	}
+	if want := []byte{0x20, 0xF6, 0x24, 0x02, 0x30, 0x05}; !bytes.Equal(req.RequestPath, want) {
+		t.Errorf("RequestPath = % X, want % X", req.RequestPath, want)
+	}
+}
+
+func TestDecodeAttribute(t *testing.T) {
+	media := make([]byte, 48)
+	binary.LittleEndian.PutUint32(media[4:], 17) // FCS errors
+	binary.LittleEndian.PutUint32(media[24:], 3) // Late collisions
+	counters := make([]byte, 44)
+	binary.LittleEndian.PutUint32(counters[0:], 1000) // In octets
+	binary.LittleEndian.PutUint32(counters[40:], 2)   // Out errors
+
+	link := &Link{Instance: 1}
+	attrs := []struct {
+		attr cip.UINT
+		data []byte
+	}{
+		{AttrInterfaceSpeed, []byte{100, 0, 0, 0}},
+		{AttrInterfaceFlags, []byte{0x0D, 0, 0, 0}}, // Link, half duplex, negotiated
+		{AttrPhysicalAddress, []byte{0x00, 0x00, 0xBC, 0x12, 0x34, 0x56}},
+		{AttrInterfaceCounters, counters},
+		{AttrMediaCounters, media},
+		{AttrInterfaceLabel, []byte{6, 'P', 'o', 'r', 't', ' ', '1'}},
+	}
+	for _, a := range attrs {
+		if err := DecodeAttribute(link, a.attr, a.data); err != nil {
+			t.Fatalf("DecodeAttribute(%d) failed: %v", a.attr, err)
+		}
+	}
+
+	if link.Speed != 100 || link.MAC.String() != "00:00:bc:12:34:56" || link.Label != "Port 1" {
+		t.Errorf("got %+v", link)
+	}
+	f := link.Flags
+	if !f.LinkActive() || f.FullDuplex() || f.HardwareFault() || f.Negotiation() != NegotiationSuccess {
+		t.Errorf("Flags 0x%02X decoded wrong", uint32(f))
+	}
+	if link.Interface == nil || link.Interface.InOctets != 1000 || link.Interface.OutErrors != 2 {
+		t.Errorf("Interface = %+v", link.Interface)
+	}
+	if link.Media == nil || link.Media.FCSErrors != 17 || link.Media.LateCollisions != 3 {
+		t.Errorf("Media = %+v", link.Media)
+	}
+
+	if err := DecodeAttribute(link, AttrMediaCounters, media[:20]); err == nil {
+		t.Error("expected error for short media counters")
+	}
+	if Negotiation(7).String() != "unknown (7)" {
+		t.Errorf("String() = %q", Negotiation(7).String())
+	}
+}
@@ -0,0 +1,186 @@
+package identity
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// Identity holds the attributes of an Identity Object (Class 0x01)
+// instance, as returned by Get_Attributes_All.
+type Identity struct {
+	VendorID     uint16
+	DeviceType   uint16
+	ProductCode  uint16
+	Revision     Revision
+	Status       Status
+	SerialNumber uint32
+	ProductName  string
+	State        uint8 // Only set when HasState is true
+	HasState     bool  // The device returned the optional State attribute
+}
+
+// Revision is the major and minor revision of a device.
+type Revision struct {
+	Major uint8
+	Minor uint8
+}
+
+func (r Revision) String() string {
+	return fmt.Sprintf("%d.%03d", r.Major, r.Minor)
+}
+
+// VendorName returns the name of the device's vendor.
+func (id *Identity) VendorName() string {
+	return VendorName(id.VendorID)
+}
+
+// DeviceTypeName returns the name of the device's type.
+func (id *Identity) DeviceTypeName() string {
+	return DeviceTypeName(id.DeviceType)
+}
+
+// Status is the Identity Object status word (attribute 5).
+type Status uint16
+
+// Status bits
+const (
+	StatusOwned                   Status = 1 << 0  // At least one connection is owned
+	StatusConfigured              Status = 1 << 2  // Configuration differs from the out-of-box default
+	StatusMinorRecoverableFault   Status = 1 << 8  // Minor recoverable fault
+	StatusMinorUnrecoverableFault Status = 1 << 9  // Minor unrecoverable fault
+	StatusMajorRecoverableFault   Status = 1 << 10 // Major recoverable fault
+	StatusMajorUnrecoverableFault Status = 1 << 11 // Major unrecoverable fault
+)
+
+// Owned reports whether the device has an owner, e.g. a controller with an
+// I/O connection to it.
+func (s Status) Owned() bool {
+	return s&StatusOwned != 0
+}
+
+// Configured reports whether the device has been configured.
+func (s Status) Configured() bool {
+	return s&StatusConfigured != 0
+}
+
+// MinorFault reports whether a minor fault, recoverable or not, is active.
+func (s Status) MinorFault() bool {
+	return s&(StatusMinorRecoverableFault|StatusMinorUnrecoverableFault) != 0
+}
+
+// MajorFault reports whether a major fault, recoverable or not, is active.
+func (s Status) MajorFault() bool {
+	return s&(StatusMajorRecoverableFault|StatusMajorUnrecoverableFault) != 0
+}
+
+// Extended returns the extended device status (bits 4-7).
+func (s Status) Extended() ExtendedStatus {
+	return ExtendedStatus(s >> 4 & 0x0F)
+}
+
+// KeySwitch returns the position of the key switch of a Logix controller
+// (bits 12-13). Other devices use these bits for their own purposes.
+func (s Status) KeySwitch() KeySwitch {
+	return KeySwitch(s >> 12 & 0x03)
+}
+
+// ExtendedStatus is the extended device status of the status word.
+type ExtendedStatus uint8
+
+// Extended device status values. Logix controllers report
+// ExtStatusIORun in Run mode and ExtStatusIOIdle in Program mode.
+const (
+	ExtStatusSelfTest       ExtendedStatus = 0 // Self-testing or unknown
+	ExtStatusFirmwareUpdate ExtendedStatus = 1 // Firmware update in progress
+	ExtStatusIOFaulted      ExtendedStatus = 2 // At least one faulted I/O connection
+	ExtStatusNoIO           ExtendedStatus = 3 // No I/O connections established
+	ExtStatusBadConfig      ExtendedStatus = 4 // Non-volatile configuration bad
+	ExtStatusMajorFault     ExtendedStatus = 5 // Major fault
+	ExtStatusIORun          ExtendedStatus = 6 // At least one I/O connection in run mode
+	ExtStatusIOIdle         ExtendedStatus = 7 // I/O connections established, all idle
+)
+
+var extendedStatusText = map[ExtendedStatus]string{
+	ExtStatusSelfTest:       "self-testing or unknown",
+	ExtStatusFirmwareUpdate: "firmware update in progress",
+	ExtStatusIOFaulted:      "I/O connection faulted",
+	ExtStatusNoIO:           "no I/O connections",
+	ExtStatusBadConfig:      "non-volatile configuration bad",
+	ExtStatusMajorFault:     "major fault",
+	ExtStatusIORun:          "run",
+	ExtStatusIOIdle:         "idle",
+}
+
+func (e ExtendedStatus) String() string {
+	if text, ok := extendedStatusText[e]; ok {
+		return text
+	}
+	return fmt.Sprintf("vendor specific (%d)", uint8(e))
+}
+
+// KeySwitch is the key switch position of a Logix controller.
+type KeySwitch uint8
+
+const (
+	KeySwitchUnknown KeySwitch = 0
+	KeySwitchRun     KeySwitch = 1
+	KeySwitchProgram KeySwitch = 2
+	KeySwitchRemote  KeySwitch = 3
+)
+
+func (k KeySwitch) String() string {
+	switch k {
+	case KeySwitchRun:
+		return "RUN"
+	case KeySwitchProgram:
+		return "PROG"
+	case KeySwitchRemote:
+		return "REM"
+	}
+	return "unknown"
+}
+
+// NewGetAttributesAllRequest creates a Get_Attributes_All request for
+// instance 1 of the Identity Object.
+func NewGetAttributesAllRequest() *cip.MessageRouterRequest {
+	return &cip.MessageRouterRequest{
+		Service:     cip.ServiceGetAttributeAll,
+		RequestPath: cip.BuildPath(cip.ClassIdentity, 1, 0),
+	}
+}
+
+// DecodeGetAttributesAllResponse decodes the reply to
+// NewGetAttributesAllRequest. Attributes after the product name are
+// optional; only State is decoded.
+func DecodeGetAttributesAllResponse(data []byte) (*Identity, error) {
+	r := bytes.NewReader(data)
+	id := &Identity{}
+
+	fixed := []any{&id.VendorID, &id.DeviceType, &id.ProductCode, &id.Revision, &id.Status, &id.SerialNumber}
+	for _, v := range fixed {
+		if err := binary.Read(r, binary.LittleEndian, v); err != nil {
+			return nil, fmt.Errorf("identity: %w", err)
+		}
+	}
+
+	// Product name is a SHORT_STRING
+	var nameLen uint8
+	if err := binary.Read(r, binary.LittleEndian, &nameLen); err != nil {
+		return nil, fmt.Errorf("identity: product name: %w", err)
+	}
+	name := make([]byte, nameLen)
+	if _, err := io.ReadFull(r, name); err != nil {
+		return nil, fmt.Errorf("identity: product name: %w", err)
+	}
+	id.ProductName = string(name)
+
+	if state, err := r.ReadByte(); err == nil {
+		id.State = state
+		id.HasState = true
+	}
+	return id, nil
+}
@@ -0,0 +1,95 @@
+package identity
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// logixReply is the Get_Attributes_All reply of a 1756-L83E in Remote Run
+// with a minor fault.
+var logixReply = []byte{
+	0x01, 0x00, // Vendor 1
+	0x0E, 0x00, // Programmable Logic Controller
+	0xA6, 0x00, // Product code 166
+	0x21, 0x0B, // Revision 33.011
+	0x60, 0x31, // Status
+	0x78, 0x56, 0x34, 0x12, // Serial
+	0x0E, '1', '7', '5', '6', '-', 'L', '8', '3', 'E', '/', 'B', ' ', ' ', ' ',
+}
+
+func TestNewGetAttributesAllRequest(t *testing.T) {
+	req := NewGetAttributesAllRequest()
+	if req.Service != cip.ServiceGetAttributeAll {
+		t.Errorf("Service = 0x%02X, want 0x01", req.Service)
+	}
+	if want := []byte{0x20, 0x01, 0x24, 0x01}; !bytes.Equal(req.RequestPath, want) {
+		t.Errorf("RequestPath = % X, want % X", req.RequestPath, want)
+	}
+}
+
+func TestDecodeGetAttributesAllResponse(t *testing.T) {
+	id, err := DecodeGetAttributesAllResponse(logixReply)
+	if err != nil {
+		t.Fatalf("DecodeGetAttributesAllResponse failed: %v", err)
+	}
+
+	if id.VendorID != 1 || id.DeviceType != 0x0E || id.ProductCode != 166 || id.SerialNumber != 0x12345678 {
+		t.Errorf("got %+v", id)
+	}
+	if id.Revision.String() != "33.011" {
+		t.Errorf("Revision = %s, want 33.011", id.Revision)
+	}
+	if id.ProductName != "1756-L83E/B   " || id.HasState {
+		t.Errorf("ProductName = %q, HasState = %v", id.ProductName, id.HasState)
+	}
+	if id.VendorName() != "Rockwell Automation/Allen-Bradley" || id.DeviceTypeName() != "Programmable Logic Controller" {
+		t.Errorf("names = %q, %q", id.VendorName(), id.DeviceTypeName())
+	}
+
+	s := id.Status
+	if s.Owned() || s.Configured() || !s.MinorFault() || s.MajorFault() {
+		t.Errorf("status bits of 0x%04X decoded wrong", uint16(s))
+	}
+	if s.Extended() != ExtStatusIORun || s.Extended().String() != "run" {
+		t.Errorf("Extended() = %v, want run", s.Extended())
+	}
+	if s.KeySwitch() != KeySwitchRemote || s.KeySwitch().String() != "REM" {
+		t.Errorf("KeySwitch() = %v, want REM", s.KeySwitch())
+	}
+
+	withState, err := DecodeGetAttributesAllResponse(append(append([]byte{}, logixReply...), 3))
+	if err != nil || !withState.HasState || withState.State != 3 {
+		t.Errorf("State = %d, %v, %v; want 3", withState.State, withState.HasState, err)
+	}
+
+	if _, err := DecodeGetAttributesAllResponse(logixReply[:20]); err == nil {
+		t.Error("expected error for truncated product name")
+	}
+}
+
+func TestStatusFaults(t *testing.T) {
+	s := StatusOwned | StatusConfigured | StatusMajorUnrecoverableFault | Status(ExtStatusMajorFault)<<4
+	if !s.Owned() || !s.Configured() || !s.MajorFault() || s.MinorFault() {
+		t.Errorf("status bits of 0x%04X decoded wrong", uint16(s))
+	}
+	if s.Extended() != ExtStatusMajorFault {
+		t.Errorf("Extended() = %v, want major fault", s.Extended())
+	}
+	if ExtendedStatus(12).String() != "vendor specific (12)" {
+		t.Errorf("String() = %q", ExtendedStatus(12).String())
+	}
+}
+
+func TestNames(t *testing.T) {
+	if got := VendorName(9999); got != "Vendor 9999" {
+		t.Errorf("VendorName(9999) = %q", got)
+	}
+	if got := DeviceTypeName(0x0C); got != "Communications Adapter" {
+		t.Errorf("DeviceTypeName(0x0C) = %q", got)
+	}
+	if got := DeviceTypeName(0x99); got != "Device Type 0x99" {
+		t.Errorf("DeviceTypeName(0x99) = %q", got)
+	}
+}
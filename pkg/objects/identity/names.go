@@ -0,0 +1,86 @@
+package identity
+
+import "fmt"
+
+// vendorNames holds the ODVA vendor IDs of common vendors.
+var vendorNames = map[uint16]string{
+	1:   "Rockwell Automation/Allen-Bradley",
+	2:   "Namco Controls Corp.",
+	3:   "Honeywell Inc.",
+	4:   "Parker Hannifin Corp.",
+	5:   "Rockwell Automation/Reliance Electric",
+	7:   "SMC Corporation",
+	8:   "Molex Incorporated",
+	9:   "Western Reserve Controls Corp.",
+	10:  "Advanced Micro Controls Inc. (AMCI)",
+	11:  "ASCO Pneumatic Controls",
+	12:  "Banner Engineering Corp.",
+	13:  "Belden Wire & Cable Company",
+	26:  "Festo Corporation",
+	40:  "WAGO Corporation",
+	46:  "ABB Industrial Systems",
+	47:  "Omron Corporation",
+	48:  "TURCK",
+	50:  "Real Time Automation",
+	57:  "Pepperl + Fuchs",
+	58:  "Spectrum Controls, Inc.",
+	90:  "HMS Industrial Networks AB",
+	283: "Hilscher GmbH",
+}
+
+// deviceTypeNames holds the CIP device profiles.
+var deviceTypeNames = map[uint16]string{
+	0x00: "Generic Device",
+	0x02: "AC Drive",
+	0x03: "Motor Overload",
+	0x04: "Limit Switch",
+	0x05: "Inductive Proximity Switch",
+	0x06: "Photoelectric Sensor",
+	0x07: "General Purpose Discrete I/O",
+	0x09: "Resolver",
+	0x0C: "Communications Adapter",
+	0x0E: "Programmable Logic Controller",
+	0x10: "Position Controller",
+	0x13: "DC Drive",
+	0x15: "Contactor",
+	0x16: "Motor Starter",
+	0x17: "Soft Start",
+	0x18: "Human-Machine Interface",
+	0x1A: "Mass Flow Controller",
+	0x1B: "Pneumatic Valve",
+	0x1C: "Vacuum Pressure Gauge",
+	0x1D: "Process Control Value",
+	0x1E: "Residual Gas Analyzer",
+	0x1F: "DC Power Generator",
+	0x20: "RF Power Generator",
+	0x21: "Turbomolecular Vacuum Pump",
+	0x22: "Encoder",
+	0x23: "Safety Discrete I/O Device",
+	0x24: "Fluid Flow Controller",
+	0x25: "CIP Motion Drive",
+	0x26: "CompoNet Repeater",
+	0x27: "Mass Flow Controller, Enhanced",
+	0x28: "CIP Modbus Device",
+	0x29: "CIP Modbus Translator",
+	0x2A: "Safety Analog I/O Device",
+	0x2B: "Generic Device (keyable)",
+	0x2C: "Managed Switch",
+}
+
+// VendorName returns the name of an ODVA vendor ID, or "Vendor N" if it
+// is not known.
+func VendorName(id uint16) string {
+	if name, ok := vendorNames[id]; ok {
+		return name
+	}
+	return fmt.Sprintf("Vendor %d", id)
+}
+
+// DeviceTypeName returns the name of a CIP device type, or
+// "Device Type 0xNN" if it is not known.
+func DeviceTypeName(t uint16) string {
+	if name, ok := deviceTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("Device Type 0x%02X", t)
+}
@@ -0,0 +1,168 @@
+package tcpip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// TCP/IP Interface Object (Class 0xF5) attributes
+const (
+	AttrStatus           cip.UINT = 1  // DWORD
+	AttrConfigCapability cip.UINT = 2  // DWORD
+	AttrConfigControl    cip.UINT = 3  // DWORD
+	AttrPhysicalLink     cip.UINT = 4  // Path to the Ethernet Link object
+	AttrInterfaceConfig  cip.UINT = 5  // IP settings, see InterfaceConfig
+	AttrHostName         cip.UINT = 6  // STRING
+	AttrSelectACD        cip.UINT = 10 // BOOL: address conflict detection enabled
+	AttrLastConflict     cip.UINT = 11 // Last address conflict detected
+)
+
+// Interface holds the attributes of a TCP/IP Interface Object instance.
+type Interface struct {
+	Status           Status
+	ConfigCapability uint32
+	ConfigControl    ConfigControl
+	Config           InterfaceConfig
+	HostName         string
+	SelectACD        bool // Only meaningful when HasACD is true
+	HasACD           bool // The device supports address conflict detection
+}
+
+// InterfaceConfig is the IP configuration of an interface (attribute 5).
+type InterfaceConfig struct {
+	IP          net.IP
+	Mask        net.IPMask
+	Gateway     net.IP
+	NameServer  net.IP
+	NameServer2 net.IP
+	DomainName  string
+}
+
+// Status is the TCP/IP Interface status word (attribute 1).
+type Status uint32
+
+// Status bits
+const (
+	StatusMcastPending  Status = 1 << 4 // Multicast configuration changes at the next reset
+	StatusConfigPending Status = 1 << 5 // Interface configuration changes at the next reset
+	StatusACDConflict   Status = 1 << 6 // An address conflict was detected
+	StatusACDFault      Status = 1 << 7 // The interface lost its address to a conflict
+)
+
+// Configured reports whether the interface has a valid configuration.
+func (s Status) Configured() bool {
+	return s&0x0F != 0
+}
+
+// ACDConflict reports whether an address conflict was detected.
+func (s Status) ACDConflict() bool {
+	return s&StatusACDConflict != 0
+}
+
+// ACDFault reports whether the interface gave up its address because of
+// an address conflict.
+func (s Status) ACDFault() bool {
+	return s&StatusACDFault != 0
+}
+
+// ConfigControl is the configuration control word (attribute 3).
+type ConfigControl uint32
+
+// Configuration methods, bits 0-3 of ConfigControl
+const (
+	ConfigStatic ConfigControl = 0
+	ConfigBOOTP  ConfigControl = 1
+	ConfigDHCP   ConfigControl = 2
+)
+
+// Method returns how the interface obtains its configuration.
+func (c ConfigControl) Method() ConfigControl {
+	return c & 0x0F
+}
+
+func (c ConfigControl) String() string {
+	switch c.Method() {
+	case ConfigStatic:
+		return "static"
+	case ConfigBOOTP:
+		return "BOOTP"
+	case ConfigDHCP:
+		return "DHCP"
+	}
+	return fmt.Sprintf("method %d", uint32(c.Method()))
+}
+
+// NewGetAttributeRequest creates a Get_Attribute_Single request for an
+// attribute of TCP/IP Interface instance 1.
+func NewGetAttributeRequest(attr cip.UINT) *cip.MessageRouterRequest {
+	return cip.NewGetAttributeSingleRequest(cip.BuildPath(cip.ClassTCPIPInterface, 1, attr))
+}
+
+// DecodeAttribute decodes the value of attribute attr into iface.
+// Attributes Interface does not hold are ignored.
+func DecodeAttribute(iface *Interface, attr cip.UINT, data []byte) error {
+	var err error
+	switch attr {
+	case AttrStatus:
+		var v uint32
+		v, err = decodeDWORD(data)
+		iface.Status = Status(v)
+	case AttrConfigCapability:
+		iface.ConfigCapability, err = decodeDWORD(data)
+	case AttrConfigControl:
+		var v uint32
+		v, err = decodeDWORD(data)
+		iface.ConfigControl = ConfigControl(v)
+	case AttrInterfaceConfig:
+		err = iface.Config.decode(data)
+	case AttrHostName:
+		iface.HostName, err = cip.DecodeString(cip.TypeSTRING, data)
+	case AttrSelectACD:
+		if len(data) < 1 {
+			err = fmt.Errorf("need 1 byte")
+		} else {
+			iface.SelectACD = data[0] != 0
+			iface.HasACD = true
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("tcpip: attribute %d: %w", attr, err)
+	}
+	return nil
+}
+
+func decodeDWORD(data []byte) (uint32, error) {
+	if len(data) < 4 {
+		return 0, fmt.Errorf("need 4 bytes, got %d", len(data))
+	}
+	return binary.LittleEndian.Uint32(data), nil
+}
+
+func (c *InterfaceConfig) decode(data []byte) error {
+	r := bytes.NewReader(data)
+	var addrs [5]uint32
+	if err := binary.Read(r, binary.LittleEndian, &addrs); err != nil {
+		return err
+	}
+	c.IP = ipv4(addrs[0])
+	c.Mask = net.IPMask(ipv4(addrs[1]))
+	c.Gateway = ipv4(addrs[2])
+	c.NameServer = ipv4(addrs[3])
+	c.NameServer2 = ipv4(addrs[4])
+
+	name, err := cip.DecodeString(cip.TypeSTRING, data[20:])
+	if err != nil {
+		return err
+	}
+	c.DomainName = name
+	return nil
+}
+
+// ipv4 converts an address held in a UDINT, most significant byte first.
+func ipv4(v uint32) net.IP {
+	return net.IPv4(byte(v>>24), byte(v>>16), byte(v>>8), byte(v)).To4()
+}
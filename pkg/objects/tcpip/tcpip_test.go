@@ -0,0 +1,70 @@
+package tcpip
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+func TestNewGetAttributeRequest(t *testing.T) {
+	req := NewGetAttributeRequest(AttrInterfaceConfig)
+	if req.Service != cip.ServiceGetAttributeSingle {
+		t.Errorf("Service = 0x%02X, want 0x0E", req.Service)
+	}
+	if want := []byte{0x20, 0xF5, 0x24, 0x01, 0x30, 0x05}; !bytes.Equal(req.RequestPath, want) {
+		t.Errorf("RequestPath = % X, want % X", req.RequestPath, want)
+	}
+}
+
+func TestDecodeAttribute(t *testing.T) {
+	config := []byte{
+		0x0A, 0x01, 0xA8, 0xC0, // 192.168.1.10
+		0x00, 0xFF, 0xFF, 0xFF, // 255.255.255.0
+		0x01, 0x01, 0xA8, 0xC0, // 192.168.1.1
+		0x08, 0x08, 0x08, 0x08, // 8.8.8.8
+		0x00, 0x00, 0x00, 0x00,
+		0x09, 0x00, 'p', 'l', 'a', 'n', 't', '.', 'l', 'a', 'n', 0x00,
+	}
+
+	iface := &Interface{}
+	attrs := []struct {
+		attr cip.UINT
+		data []byte
+	}{
+		{AttrStatus, []byte{0x41, 0, 0, 0}},
+		{AttrConfigControl, []byte{0x02, 0, 0, 0}},
+		{AttrInterfaceConfig, config},
+		{AttrHostName, []byte{0x03, 0x00, 'p', 'l', 'c', 0x00}},
+		{AttrSelectACD, []byte{0x01}},
+	}
+	for _, a := range attrs {
+		if err := DecodeAttribute(iface, a.attr, a.data); err != nil {
+			t.Fatalf("DecodeAttribute(%d) failed: %v", a.attr, err)
+		}
+	}
+
+	if !iface.Status.Configured() || !iface.Status.ACDConflict() || iface.Status.ACDFault() {
+		t.Errorf("Status 0x%02X decoded wrong", uint32(iface.Status))
+	}
+	if iface.ConfigControl.Method() != ConfigDHCP || iface.ConfigControl.String() != "DHCP" {
+		t.Errorf("ConfigControl = %v, want DHCP", iface.ConfigControl)
+	}
+	c := iface.Config
+	if !c.IP.Equal(net.IPv4(192, 168, 1, 10)) || c.Mask.String() != "ffffff00" ||
+		!c.Gateway.Equal(net.IPv4(192, 168, 1, 1)) || !c.NameServer.Equal(net.IPv4(8, 8, 8, 8)) ||
+		!c.NameServer2.Equal(net.IPv4zero) || c.DomainName != "plant.lan" {
+		t.Errorf("Config = %+v", c)
+	}
+	if iface.HostName != "plc" || !iface.SelectACD || !iface.HasACD {
+		t.Errorf("HostName = %q, SelectACD = %v, HasACD = %v", iface.HostName, iface.SelectACD, iface.HasACD)
+	}
+
+	if err := DecodeAttribute(iface, AttrInterfaceConfig, config[:12]); err == nil {
+		t.Error("expected error for short interface configuration")
+	}
+	if err := DecodeAttribute(iface, AttrStatus, []byte{1}); err == nil {
+		t.Error("expected error for short status")
+	}
+}
@@ -0,0 +1,258 @@
+package pccc
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FileType is the type of a data table file.
+type FileType byte
+
+const (
+	FileOutput  FileType = 0x82 // O
+	FileInput   FileType = 0x83 // I
+	FileStatus  FileType = 0x84 // S
+	FileBit     FileType = 0x85 // B
+	FileTimer   FileType = 0x86 // T
+	FileCounter FileType = 0x87 // C
+	FileControl FileType = 0x88 // R
+	FileInteger FileType = 0x89 // N
+	FileFloat   FileType = 0x8A // F
+	FileString  FileType = 0x8D // ST
+	FileASCII   FileType = 0x8E // A
+	FileLong    FileType = 0x91 // L
+)
+
+// fileTypes maps the letters of data table files to their type.
+var fileTypes = map[string]FileType{
+	"O":  FileOutput,
+	"I":  FileInput,
+	"S":  FileStatus,
+	"B":  FileBit,
+	"T":  FileTimer,
+	"C":  FileCounter,
+	"R":  FileControl,
+	"N":  FileInteger,
+	"F":  FileFloat,
+	"ST": FileString,
+	"A":  FileASCII,
+	"L":  FileLong,
+}
+
+// defaultFiles are the file numbers of files that may be written without one.
+var defaultFiles = map[FileType]uint16{
+	FileOutput: 0,
+	FileInput:  1,
+	FileStatus: 2,
+}
+
+// String returns the file letter.
+func (t FileType) String() string {
+	for letter, ft := range fileTypes {
+		if ft == t {
+			return letter
+		}
+	}
+	return fmt.Sprintf("FileType(0x%02X)", byte(t))
+}
+
+// ElementSize returns the size in bytes of one element of a file of type t.
+func (t FileType) ElementSize() int {
+	switch t {
+	case FileTimer, FileCounter, FileControl:
+		return 6 // Control word, PRE/LEN, ACC/POS
+	case FileFloat, FileLong:
+		return 4
+	case FileString:
+		return 84 // Length word and 82 characters
+	default:
+		return 2
+	}
+}
+
+// Sub-elements of timer, counter and control elements
+const (
+	SubElementControl = 0 // Status bits
+	SubElementPRE     = 1
+	SubElementACC     = 2
+	SubElementLEN     = 1
+	SubElementPOS     = 2
+)
+
+// namedSubElements maps the member names of structured files to the
+// sub-element and, for status bits, the bit number in the control word.
+var namedSubElements = map[FileType]map[string][2]int{
+	FileTimer: {
+		"PRE": {SubElementPRE, -1}, "ACC": {SubElementACC, -1},
+		"EN": {0, 15}, "TT": {0, 14}, "DN": {0, 13},
+	},
+	FileCounter: {
+		"PRE": {SubElementPRE, -1}, "ACC": {SubElementACC, -1},
+		"CU": {0, 15}, "CD": {0, 14}, "DN": {0, 13}, "OV": {0, 12}, "UN": {0, 11},
+	},
+	FileControl: {
+		"LEN": {SubElementLEN, -1}, "POS": {SubElementPOS, -1},
+		"EN": {0, 15}, "EU": {0, 14}, "DN": {0, 13}, "EM": {0, 12},
+		"ER": {0, 11}, "UL": {0, 10}, "IN": {0, 9}, "FD": {0, 8},
+	},
+}
+
+// Address is a data table address such as N7:0 or T4:1.ACC.
+type Address struct {
+	FileType   FileType
+	FileNumber uint16
+	Element    uint16
+	SubElement uint16
+	// HasSubElement is set when the address selects one word of a
+	// timer, counter or control element, or of an I/O element.
+	HasSubElement bool
+	// Bit is the bit number for bit addresses, or -1.
+	Bit int
+}
+
+var (
+	addressPattern = regexp.MustCompile(`^([A-Z]{1,2})(\d*):(\d+)(?:\.(\d+|[A-Z]+))?(?:/(\d+))?$`)
+	bitPattern     = regexp.MustCompile(`^([A-Z]{1,2})(\d*)/(\d+)$`)
+)
+
+// ParseAddress parses a data table address:
+//
+//	N7:0       integer file 7, element 0
+//	F8:3       float file 8, element 3
+//	B3:0/5     bit 5 of word 0 of bit file 3
+//	B3/21      bit 21 of bit file 3 (word 1, bit 5)
+//	T4:1.ACC   accumulator of timer 1 of file 4
+//	T4:1/DN    done bit of timer 1 (also T4:1.DN)
+//	I:1.0/3    input slot 1, word 0, bit 3
+//
+// The file number of O, I and S files may be omitted.
+func ParseAddress(s string) (*Address, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+
+	if m := bitPattern.FindStringSubmatch(s); m != nil {
+		a, err := newAddress(s, m[1], m[2])
+		if err != nil {
+			return nil, err
+		}
+		bit, err := strconv.ParseUint(m[3], 10, 32)
+		if err != nil || bit/16 > 0xFFFF {
+			return nil, fmt.Errorf("pccc address %q: invalid bit number", s)
+		}
+		a.Element = uint16(bit / 16)
+		a.Bit = int(bit % 16)
+		return a, nil
+	}
+
+	m := addressPattern.FindStringSubmatch(s)
+	if m == nil {
+		// Status bits written with a slash: T4:1/DN
+		if i := strings.LastIndex(s, "/"); i > 0 {
+			if a, err := ParseAddress(s[:i] + "." + s[i+1:]); err == nil && a.Bit >= 0 {
+				return a, nil
+			}
+		}
+		return nil, fmt.Errorf("pccc address %q: invalid format", s)
+	}
+
+	a, err := newAddress(s, m[1], m[2])
+	if err != nil {
+		return nil, err
+	}
+	element, err := strconv.ParseUint(m[3], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("pccc address %q: invalid element", s)
+	}
+	a.Element = uint16(element)
+
+	if sub := m[4]; sub != "" {
+		switch a.FileType {
+		case FileTimer, FileCounter, FileControl, FileOutput, FileInput:
+		default:
+			return nil, fmt.Errorf("pccc address %q: %s files have no sub-elements", s, a.FileType)
+		}
+		if n, err := strconv.ParseUint(sub, 10, 16); err == nil {
+			a.SubElement = uint16(n)
+			a.HasSubElement = true
+		} else {
+			named, ok := namedSubElements[a.FileType][sub]
+			if !ok {
+				return nil, fmt.Errorf("pccc address %q: %s files have no member %s", s, a.FileType, sub)
+			}
+			a.SubElement = uint16(named[0])
+			a.HasSubElement = true
+			a.Bit = named[1]
+		}
+	}
+
+	if m[5] != "" {
+		if a.Bit >= 0 {
+			return nil, fmt.Errorf("pccc address %q: bit of a status bit", s)
+		}
+		bit, err := strconv.ParseUint(m[5], 10, 8)
+		if err != nil || bit > 15 {
+			return nil, fmt.Errorf("pccc address %q: bit number out of range", s)
+		}
+		a.Bit = int(bit)
+	}
+
+	switch a.FileType {
+	case FileFloat, FileLong, FileString, FileASCII:
+		if a.Bit >= 0 {
+			return nil, fmt.Errorf("pccc address %q: bits of %s files are not supported", s, a.FileType)
+		}
+	}
+
+	return a, nil
+}
+
+func newAddress(s, letter, file string) (*Address, error) {
+	ft, ok := fileTypes[letter]
+	if !ok {
+		return nil, fmt.Errorf("pccc address %q: unknown file type %s", s, letter)
+	}
+	a := &Address{FileType: ft, Bit: -1}
+
+	if file == "" {
+		n, ok := defaultFiles[ft]
+		if !ok {
+			return nil, fmt.Errorf("pccc address %q: missing file number", s)
+		}
+		a.FileNumber = n
+		return a, nil
+	}
+
+	n, err := strconv.ParseUint(file, 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("pccc address %q: invalid file number", s)
+	}
+	a.FileNumber = uint16(n)
+	return a, nil
+}
+
+// IsBit reports whether the address selects a single bit.
+func (a *Address) IsBit() bool {
+	return a.Bit >= 0
+}
+
+// Size returns the number of bytes the address selects: one word for
+// sub-elements and bits, a whole element otherwise.
+func (a *Address) Size() int {
+	if a.HasSubElement || a.IsBit() {
+		return 2
+	}
+	return a.FileType.ElementSize()
+}
+
+// String returns the address in the canonical form, e.g. N7:0 or B3:1/5.
+func (a *Address) String() string {
+	s := fmt.Sprintf("%s%d:%d", a.FileType, a.FileNumber, a.Element)
+	if a.HasSubElement {
+		s += "." + strconv.Itoa(int(a.SubElement))
+	}
+	if a.IsBit() {
+		s += "/" + strconv.Itoa(a.Bit)
+	}
+	return s
+}
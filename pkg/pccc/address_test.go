@@ -0,0 +1,65 @@
+package pccc
+
+import "testing"
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Address
+		size int
+	}{
+		{"N7:0", Address{FileType: FileInteger, FileNumber: 7, Element: 0, Bit: -1}, 2},
+		{"F8:3", Address{FileType: FileFloat, FileNumber: 8, Element: 3, Bit: -1}, 4},
+		{"B3:0/5", Address{FileType: FileBit, FileNumber: 3, Element: 0, Bit: 5}, 2},
+		{"B3/21", Address{FileType: FileBit, FileNumber: 3, Element: 1, Bit: 5}, 2},
+		{"T4:1.ACC", Address{FileType: FileTimer, FileNumber: 4, Element: 1, SubElement: 2, HasSubElement: true, Bit: -1}, 2},
+		{"T4:1", Address{FileType: FileTimer, FileNumber: 4, Element: 1, Bit: -1}, 6},
+		{"T4:1/DN", Address{FileType: FileTimer, FileNumber: 4, Element: 1, HasSubElement: true, Bit: 13}, 2},
+		{"c5:0.dn", Address{FileType: FileCounter, FileNumber: 5, Element: 0, HasSubElement: true, Bit: 13}, 2},
+		{"R6:2.POS", Address{FileType: FileControl, FileNumber: 6, Element: 2, SubElement: 2, HasSubElement: true, Bit: -1}, 2},
+		{"ST9:0", Address{FileType: FileString, FileNumber: 9, Element: 0, Bit: -1}, 84},
+		{"L10:300", Address{FileType: FileLong, FileNumber: 10, Element: 300, Bit: -1}, 4},
+		{"S:1/5", Address{FileType: FileStatus, FileNumber: 2, Element: 1, Bit: 5}, 2},
+		{"I:1.0/3", Address{FileType: FileInput, FileNumber: 1, Element: 1, SubElement: 0, HasSubElement: true, Bit: 3}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			a, err := ParseAddress(tt.in)
+			if err != nil {
+				t.Fatalf("ParseAddress() error = %v", err)
+			}
+			if *a != tt.want {
+				t.Errorf("ParseAddress() = %+v, want %+v", *a, tt.want)
+			}
+			if a.Size() != tt.size {
+				t.Errorf("Size() = %d, want %d", a.Size(), tt.size)
+			}
+		})
+	}
+}
+
+func TestParseAddress_Invalid(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"N7",
+		"N:0",     // N files need a file number
+		"X7:0",    // Unknown file type
+		"N7:0/16", // Bit out of range
+		"F8:0/1",  // No bits in floats
+		"N7:0.1",  // No sub-elements in integer files
+		"T4:0.XX", // Unknown member
+		"T4:0.DN/1",
+	} {
+		if _, err := ParseAddress(in); err == nil {
+			t.Errorf("ParseAddress(%q) expected error", in)
+		}
+	}
+}
+
+func TestAddress_String(t *testing.T) {
+	a, _ := ParseAddress("B3/21")
+	if got := a.String(); got != "B3:1/5" {
+		t.Errorf("String() = %q, want B3:1/5", got)
+	}
+}
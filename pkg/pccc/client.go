@@ -0,0 +1,188 @@
+package pccc
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync/atomic"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+	"github.com/iceisfun/goeip/pkg/objects/connmgr"
+	"github.com/iceisfun/goeip/pkg/session"
+)
+
+// DefaultRequestor identifies requests unless WithRequestor is given.
+var DefaultRequestor = Requestor{VendorID: 0x1337, SerialNumber: 0x47454950}
+
+// Unconnected Send timeout for routed requests, about 5 seconds.
+const (
+	routePriorityTimeTick = 0x0A
+	routeTimeoutTicks     = 0x05
+)
+
+// Client reads and writes the data table of a PCCC processor over an
+// EtherNet/IP session.
+// A Client is safe for concurrent use.
+type Client struct {
+	send      func(ctx context.Context, req *cip.MessageRouterRequest) (*cip.MessageRouterResponse, error)
+	requestor Requestor
+	route     cip.Path
+	tns       atomic.Uint32
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithRequestor sets the requestor ID sent with each command.
+func WithRequestor(id Requestor) Option {
+	return func(c *Client) {
+		c.requestor = id
+	}
+}
+
+// WithRoute routes the commands from the device at the session's address
+// to the processor, e.g. a route parsed with cip.ParseRoutePath.
+func WithRoute(route cip.Path) Option {
+	return func(c *Client) {
+		c.route = route
+	}
+}
+
+// NewClient returns a client sending PCCC commands over s with the Execute
+// PCCC service. The session must be registered.
+func NewClient(s *session.Session, opts ...Option) *Client {
+	c := &Client{send: s.SendCIPRequestContext, requestor: DefaultRequestor}
+	c.tns.Store(rand.Uint32())
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Read reads the value at address, decoded as described in DecodeValue.
+func (c *Client) Read(address string) (any, error) {
+	return c.ReadContext(context.Background(), address)
+}
+
+// ReadContext is like Read but gives up when ctx is done.
+func (c *Client) ReadContext(ctx context.Context, address string) (any, error) {
+	a, err := ParseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.read(ctx, a, a.Size())
+	if err != nil {
+		return nil, err
+	}
+	return DecodeValue(a, data)
+}
+
+// ReadData reads size bytes starting at address, e.g. 20 bytes at N7:0 for
+// ten consecutive integers.
+func (c *Client) ReadData(address string, size int) ([]byte, error) {
+	return c.ReadDataContext(context.Background(), address, size)
+}
+
+// ReadDataContext is like ReadData but gives up when ctx is done.
+func (c *Client) ReadDataContext(ctx context.Context, address string, size int) ([]byte, error) {
+	a, err := ParseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	return c.read(ctx, a, size)
+}
+
+func (c *Client) read(ctx context.Context, a *Address, size int) ([]byte, error) {
+	if size <= 0 || size > 0xFF {
+		return nil, fmt.Errorf("pccc read %s: size %d out of range", a, size)
+	}
+	reply, err := c.execute(ctx, NewReadCommand(c.nextTNS(), a, size))
+	if err != nil {
+		return nil, fmt.Errorf("pccc read %s: %w", a, err)
+	}
+	if len(reply.Data) < size {
+		return nil, fmt.Errorf("pccc read %s: expected %d bytes, got %d", a, size, len(reply.Data))
+	}
+	return reply.Data[:size], nil
+}
+
+// Write writes value to address. Bit addresses take a bool and are written
+// with a masked write that leaves the other bits of the word alone; other
+// addresses take Go numbers, or a string for ST files.
+func (c *Client) Write(address string, value any) error {
+	return c.WriteContext(context.Background(), address, value)
+}
+
+// WriteContext is like Write but gives up when ctx is done.
+func (c *Client) WriteContext(ctx context.Context, address string, value any) error {
+	a, err := ParseAddress(address)
+	if err != nil {
+		return err
+	}
+
+	var cmd *Command
+	if a.IsBit() {
+		b, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("pccc write %s: bit addresses take a bool, got %T", a, value)
+		}
+		mask := uint16(1) << a.Bit
+		var v uint16
+		if b {
+			v = mask
+		}
+		cmd = NewMaskedWriteCommand(c.nextTNS(), a, mask, v)
+	} else {
+		data, err := EncodeValue(a, value)
+		if err != nil {
+			return err
+		}
+		cmd = NewWriteCommand(c.nextTNS(), a, data)
+	}
+
+	if _, err := c.execute(ctx, cmd); err != nil {
+		return fmt.Errorf("pccc write %s: %w", a, err)
+	}
+	return nil
+}
+
+func (c *Client) nextTNS() uint16 {
+	return uint16(c.tns.Add(1))
+}
+
+// execute sends cmd with Execute PCCC and returns the processor's reply.
+func (c *Client) execute(ctx context.Context, cmd *Command) (*Reply, error) {
+	req := NewExecuteRequest(c.requestor, cmd)
+	if len(c.route) > 0 {
+		routed, err := connmgr.UnconnectedSend(req, c.route, routePriorityTimeTick, routeTimeoutTicks)
+		if err != nil {
+			return nil, err
+		}
+		req = routed
+	}
+
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.route) > 0 {
+		if resp, err = connmgr.UnwrapUnconnectedSend(resp); err != nil {
+			return nil, err
+		}
+	}
+	if err := resp.Error(); err != nil {
+		return nil, err
+	}
+
+	reply, err := DecodeExecuteResponse(resp.ResponseData)
+	if err != nil {
+		return nil, err
+	}
+	if reply.TNS != cmd.TNS {
+		return nil, fmt.Errorf("reply to transaction %d, want %d", reply.TNS, cmd.TNS)
+	}
+	if err := reply.Err(); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
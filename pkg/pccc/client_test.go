@@ -0,0 +1,167 @@
+package pccc
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// mockProcessor is a data table answering typed reads and writes.
+type mockProcessor struct {
+	t     *testing.T
+	files map[uint16][]byte
+	cmds  []*Command
+}
+
+// parseField reads an address field from b.
+func parseField(b []byte) (uint16, []byte) {
+	if b[0] == 0xFF {
+		return binary.LittleEndian.Uint16(b[1:]), b[3:]
+	}
+	return uint16(b[0]), b[1:]
+}
+
+func (m *mockProcessor) send(ctx context.Context, req *cip.MessageRouterRequest) (*cip.MessageRouterResponse, error) {
+	if req.Service != ServiceExecutePCCC || !bytes.Equal(req.RequestPath, cip.BuildPath(ClassPCCC, 1, 0)) {
+		m.t.Fatalf("request = service 0x%02X path %v", req.Service, req.RequestPath)
+	}
+	id := req.RequestData[:req.RequestData[0]]
+	b := req.RequestData[len(id):]
+	cmd := &Command{Command: b[0], Status: b[1], TNS: binary.LittleEndian.Uint16(b[2:]), Function: b[4], Data: b[5:]}
+	m.cmds = append(m.cmds, cmd)
+
+	d := cmd.Data
+	size := int(d[0])
+	file, d := parseField(d[1:])
+	elementSize := FileType(d[0]).ElementSize()
+	element, d := parseField(d[1:])
+	sub, d := parseField(d)
+	offset := int(element)*elementSize + int(sub)*2
+	table := m.files[file]
+
+	reply := append([]byte{}, id...)
+	reply = append(reply, cmd.Command|replyFlag, StatusSuccess)
+	reply = binary.LittleEndian.AppendUint16(reply, cmd.TNS)
+
+	if offset+size > len(table) {
+		reply[len(id)+1] = StatusExtended
+		reply = append(reply, 0x06)
+		return &cip.MessageRouterResponse{Service: req.Service | 0x80, ResponseData: reply}, nil
+	}
+
+	switch cmd.Function {
+	case FncTypedRead:
+		reply = append(reply, table[offset:offset+size]...)
+	case FncTypedWrite:
+		copy(table[offset:], d[:size])
+	case FncMaskedWrite:
+		mask, value := d[:size], d[size:]
+		for i := range size {
+			table[offset+i] = table[offset+i]&^mask[i] | value[i]&mask[i]
+		}
+	}
+	return &cip.MessageRouterResponse{Service: req.Service | 0x80, ResponseData: reply}, nil
+}
+
+func newMockClient(t *testing.T) (*Client, *mockProcessor) {
+	m := &mockProcessor{t: t, files: map[uint16][]byte{
+		3:  make([]byte, 8),   // B3
+		4:  make([]byte, 12),  // T4
+		7:  make([]byte, 20),  // N7
+		8:  make([]byte, 16),  // F8
+		9:  make([]byte, 168), // ST9
+		10: make([]byte, 8),   // L10
+	}}
+	c := &Client{send: m.send, requestor: DefaultRequestor}
+	return c, m
+}
+
+func TestClient_ReadWrite(t *testing.T) {
+	c, _ := newMockClient(t)
+
+	tests := []struct {
+		addr  string
+		value any
+		want  any
+	}{
+		{"N7:3", int16(-42), int16(-42)},
+		{"N7:4", 1000, int16(1000)},
+		{"F8:2", float32(3.5), float32(3.5)},
+		{"L10:1", int32(100000), int32(100000)},
+		{"ST9:1", "Hello", "Hello"},
+		{"T4:1.PRE", 500, int16(500)},
+		{"B3:1/5", true, true},
+	}
+	for _, tt := range tests {
+		if err := c.Write(tt.addr, tt.value); err != nil {
+			t.Fatalf("Write(%s) error = %v", tt.addr, err)
+		}
+		got, err := c.Read(tt.addr)
+		if err != nil {
+			t.Fatalf("Read(%s) error = %v", tt.addr, err)
+		}
+		if got != tt.want {
+			t.Errorf("Read(%s) = %v (%T), want %v (%T)", tt.addr, got, got, tt.want, tt.want)
+		}
+	}
+
+	// The timer reads back as a whole element
+	v, err := c.Read("T4:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if timer, ok := v.(Timer); !ok || timer.PRE != 500 {
+		t.Errorf("Read(T4:1) = %+v, want PRE 500", v)
+	}
+}
+
+func TestClient_WriteBit_Masked(t *testing.T) {
+	c, m := newMockClient(t)
+	m.files[3][2] = 0xFF // B3:1 bits 0-7 set
+
+	if err := c.Write("B3/16", false); err != nil {
+		t.Fatal(err)
+	}
+	if m.files[3][2] != 0xFE || m.files[3][3] != 0 {
+		t.Errorf("B3:1 = % X, want FE 00", m.files[3][2:4])
+	}
+	if last := m.cmds[len(m.cmds)-1]; last.Function != FncMaskedWrite {
+		t.Errorf("function = 0x%02X, want masked write", last.Function)
+	}
+}
+
+func TestClient_Error(t *testing.T) {
+	c, _ := newMockClient(t)
+
+	_, err := c.Read("N7:100")
+	var status *StatusError
+	if !errors.As(err, &status) || status.ExtStatus != 0x06 {
+		t.Errorf("Read() error = %v, want ext status 0x06", err)
+	}
+}
+
+func TestDecodeValue_Counter(t *testing.T) {
+	a, _ := ParseAddress("C5:0")
+	data := []byte{0x00, 0xA0, 0x0A, 0x00, 0x0B, 0x00} // CU and DN set
+	v, err := DecodeValue(a, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Counter{CU: true, DN: true, PRE: 10, ACC: 11}
+	if v != want {
+		t.Errorf("DecodeValue() = %+v, want %+v", v, want)
+	}
+}
+
+func TestAppendField(t *testing.T) {
+	if got := appendField(nil, 7); !bytes.Equal(got, []byte{7}) {
+		t.Errorf("appendField(7) = % X", got)
+	}
+	if got := appendField(nil, 300); !bytes.Equal(got, []byte{0xFF, 0x2C, 0x01}) {
+		t.Errorf("appendField(300) = % X", got)
+	}
+}
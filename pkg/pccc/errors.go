@@ -0,0 +1,66 @@
+package pccc
+
+import "fmt"
+
+// StatusError is a PCCC status reported by the processor.
+type StatusError struct {
+	Status    byte
+	ExtStatus byte // Valid when Status is StatusExtended
+}
+
+var statusText = map[byte]string{
+	0x10: "illegal command or format",
+	0x20: "host has a problem and will not communicate",
+	0x30: "remote node host is missing, disconnected or shut down",
+	0x40: "host could not complete function due to hardware fault",
+	0x50: "addressing problem or memory protect rungs",
+	0x60: "function not allowed due to command protection selection",
+	0x70: "processor is in program mode",
+	0x80: "compatibility mode file missing or communication zone problem",
+	0x90: "remote node cannot buffer command",
+	0xB0: "remote node problem due to download",
+	0xC0: "cannot execute command due to active IPBs",
+}
+
+var extStatusText = map[byte]string{
+	0x01: "a field has an illegal value",
+	0x02: "fewer levels specified in address than minimum for any address",
+	0x03: "more levels specified in address than system supports",
+	0x04: "symbol not found",
+	0x05: "symbol is of improper format",
+	0x06: "address does not point to something usable",
+	0x07: "file is wrong size",
+	0x08: "cannot complete request, situation has changed since the start of the command",
+	0x09: "data or file is too large",
+	0x0A: "transaction size plus word address is too large",
+	0x0B: "access denied, improper privilege",
+	0x0C: "condition cannot be generated, resource is not available",
+	0x0D: "condition already exists, resource is already available",
+	0x0E: "command cannot be executed",
+	0x0F: "histogram overflow",
+	0x10: "no access",
+	0x11: "illegal data type",
+	0x12: "invalid parameter or invalid data",
+	0x13: "address reference exists to deleted area",
+	0x14: "command execution failure for unknown reason",
+	0x15: "data conversion error",
+	0x16: "scanner not able to communicate with 1771 rack adapter",
+	0x17: "type mismatch",
+	0x18: "1771 module response was not valid",
+	0x19: "duplicated label",
+	0x1A: "file is open, another node owns it",
+	0x1B: "another node is the program owner",
+}
+
+func (e *StatusError) Error() string {
+	if e.Status == StatusExtended {
+		if text, ok := extStatusText[e.ExtStatus]; ok {
+			return fmt.Sprintf("PCCC error: ext status 0x%02X: %s", e.ExtStatus, text)
+		}
+		return fmt.Sprintf("PCCC error: ext status 0x%02X", e.ExtStatus)
+	}
+	if text, ok := statusText[e.Status]; ok {
+		return fmt.Sprintf("PCCC error: status 0x%02X: %s", e.Status, text)
+	}
+	return fmt.Sprintf("PCCC error: status 0x%02X", e.Status)
+}
@@ -0,0 +1,173 @@
+// Package pccc implements PCCC, the command set of SLC 500, PLC-5 and
+// MicroLogix processors, carried over EtherNet/IP with the Execute PCCC
+// service of the PCCC Object.
+package pccc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+)
+
+// PCCC Object
+const (
+	ClassPCCC          cip.UINT  = 0x67
+	ServiceExecutePCCC cip.USINT = 0x4B
+)
+
+// Command and function codes
+const (
+	CmdTyped byte = 0x0F // Typed commands, function code follows
+
+	FncTypedRead   byte = 0xA2 // Protected typed logical read, three address fields
+	FncTypedWrite  byte = 0xAA // Protected typed logical write, three address fields
+	FncMaskedWrite byte = 0xAB // Protected typed logical masked write, three address fields
+
+	replyFlag byte = 0x40 // Set in the command code of replies
+)
+
+// Status codes
+const (
+	StatusSuccess  byte = 0x00
+	StatusExtended byte = 0xF0 // Extended status follows the TNS
+)
+
+// Command is a PCCC command message.
+type Command struct {
+	Command  byte
+	Status   byte
+	TNS      uint16 // Transaction number, echoed in the reply
+	Function byte
+	Data     []byte
+}
+
+// Encode encodes the command.
+func (c *Command) Encode() []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(c.Command)
+	buf.WriteByte(c.Status)
+	binary.Write(buf, binary.LittleEndian, c.TNS)
+	buf.WriteByte(c.Function)
+	buf.Write(c.Data)
+	return buf.Bytes()
+}
+
+// Reply is a PCCC reply message.
+type Reply struct {
+	Command   byte
+	Status    byte
+	TNS       uint16
+	ExtStatus byte // Valid when Status is StatusExtended
+	Data      []byte
+}
+
+// DecodeReply decodes a PCCC reply message.
+func DecodeReply(data []byte) (*Reply, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("pccc reply: short message (%d bytes)", len(data))
+	}
+	r := &Reply{
+		Command: data[0],
+		Status:  data[1],
+		TNS:     binary.LittleEndian.Uint16(data[2:4]),
+		Data:    data[4:],
+	}
+	if r.Status == StatusExtended && len(r.Data) > 0 {
+		r.ExtStatus = r.Data[0]
+		r.Data = r.Data[1:]
+	}
+	return r, nil
+}
+
+// Err returns a *StatusError if the reply reports a failure.
+func (r *Reply) Err() error {
+	if r.Status == StatusSuccess {
+		return nil
+	}
+	return &StatusError{Status: r.Status, ExtStatus: r.ExtStatus}
+}
+
+// Requestor identifies the originator of Execute PCCC requests.
+type Requestor struct {
+	VendorID     cip.UINT
+	SerialNumber cip.UDINT
+}
+
+// NewExecuteRequest wraps cmd in an Execute PCCC request to the PCCC Object.
+func NewExecuteRequest(id Requestor, cmd *Command) *cip.MessageRouterRequest {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(7) // Requestor ID length, including itself
+	binary.Write(buf, binary.LittleEndian, id.VendorID)
+	binary.Write(buf, binary.LittleEndian, id.SerialNumber)
+	buf.Write(cmd.Encode())
+
+	return &cip.MessageRouterRequest{
+		Service:     ServiceExecutePCCC,
+		RequestPath: cip.BuildPath(ClassPCCC, 1, 0),
+		RequestData: buf.Bytes(),
+	}
+}
+
+// DecodeExecuteResponse decodes the data of an Execute PCCC reply: the
+// echoed requestor ID followed by the PCCC reply.
+func DecodeExecuteResponse(data []byte) (*Reply, error) {
+	if len(data) < 1 || int(data[0]) > len(data) || data[0] == 0 {
+		return nil, fmt.Errorf("execute pccc response: invalid requestor ID")
+	}
+	return DecodeReply(data[data[0]:])
+}
+
+// appendField appends an address field: one byte, or 0xFF followed by a
+// UINT for values from 255 up.
+func appendField(b []byte, v uint16) []byte {
+	if v < 0xFF {
+		return append(b, byte(v))
+	}
+	return binary.LittleEndian.AppendUint16(append(b, 0xFF), v)
+}
+
+// appendAddress appends the byte size and the three address fields of a
+// typed logical read or write.
+func appendAddress(b []byte, size int, a *Address) []byte {
+	b = append(b, byte(size))
+	b = appendField(b, a.FileNumber)
+	b = append(b, byte(a.FileType))
+	b = appendField(b, a.Element)
+	return appendField(b, a.SubElement)
+}
+
+// NewReadCommand returns a typed logical read of size bytes at a.
+func NewReadCommand(tns uint16, a *Address, size int) *Command {
+	return &Command{
+		Command:  CmdTyped,
+		TNS:      tns,
+		Function: FncTypedRead,
+		Data:     appendAddress(nil, size, a),
+	}
+}
+
+// NewWriteCommand returns a typed logical write of data at a.
+func NewWriteCommand(tns uint16, a *Address, data []byte) *Command {
+	return &Command{
+		Command:  CmdTyped,
+		TNS:      tns,
+		Function: FncTypedWrite,
+		Data:     append(appendAddress(nil, len(data), a), data...),
+	}
+}
+
+// NewMaskedWriteCommand returns a typed logical masked write, which changes
+// only the bits of the word at a that are set in mask.
+func NewMaskedWriteCommand(tns uint16, a *Address, mask, value uint16) *Command {
+	data := appendAddress(nil, 2, a)
+	data = binary.LittleEndian.AppendUint16(data, mask)
+	data = binary.LittleEndian.AppendUint16(data, value)
+	return &Command{
+		Command:  CmdTyped,
+		TNS:      tns,
+		Function: FncMaskedWrite,
+		Data:     data,
+	}
+}
@@ -0,0 +1,192 @@
+package pccc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Timer is an element of a timer (T) file.
+type Timer struct {
+	EN, TT, DN bool
+	PRE, ACC   int16
+}
+
+// Counter is an element of a counter (C) file.
+type Counter struct {
+	CU, CD, DN, OV, UN bool
+	PRE, ACC           int16
+}
+
+// Control is an element of a control (R) file.
+type Control struct {
+	EN, EU, DN, EM, ER, UL, IN, FD bool
+	LEN, POS                       int16
+}
+
+func bit(w uint16, n int) bool {
+	return w&(1<<n) != 0
+}
+
+// DecodeValue decodes the data read from a into a Go value:
+//
+//	bit addresses                    bool
+//	N, B, S, I, O and sub-elements   int16
+//	L                                int32
+//	F                                float32
+//	ST                               string
+//	T, C, R                          Timer, Counter, Control
+func DecodeValue(a *Address, data []byte) (any, error) {
+	if len(data) < a.Size() {
+		return nil, fmt.Errorf("pccc %s: expected %d bytes, got %d", a, a.Size(), len(data))
+	}
+
+	if a.IsBit() {
+		return bit(binary.LittleEndian.Uint16(data), a.Bit), nil
+	}
+	if a.HasSubElement {
+		return int16(binary.LittleEndian.Uint16(data)), nil
+	}
+
+	word := func(i int) uint16 { return binary.LittleEndian.Uint16(data[2*i:]) }
+
+	switch a.FileType {
+	case FileFloat:
+		return math.Float32frombits(binary.LittleEndian.Uint32(data)), nil
+	case FileLong:
+		return int32(binary.LittleEndian.Uint32(data)), nil
+	case FileString:
+		return decodeString(data)
+	case FileTimer:
+		w := word(0)
+		return Timer{
+			EN: bit(w, 15), TT: bit(w, 14), DN: bit(w, 13),
+			PRE: int16(word(1)), ACC: int16(word(2)),
+		}, nil
+	case FileCounter:
+		w := word(0)
+		return Counter{
+			CU: bit(w, 15), CD: bit(w, 14), DN: bit(w, 13), OV: bit(w, 12), UN: bit(w, 11),
+			PRE: int16(word(1)), ACC: int16(word(2)),
+		}, nil
+	case FileControl:
+		w := word(0)
+		return Control{
+			EN: bit(w, 15), EU: bit(w, 14), DN: bit(w, 13), EM: bit(w, 12),
+			ER: bit(w, 11), UL: bit(w, 10), IN: bit(w, 9), FD: bit(w, 8),
+			LEN: int16(word(1)), POS: int16(word(2)),
+		}, nil
+	default:
+		return int16(word(0)), nil
+	}
+}
+
+// EncodeValue encodes v for a write to a. Bit addresses are written with a
+// masked write instead and are not accepted here; neither are whole timer,
+// counter and control elements.
+func EncodeValue(a *Address, v any) ([]byte, error) {
+	if a.IsBit() {
+		return nil, fmt.Errorf("pccc %s: bit addresses take a masked write", a)
+	}
+
+	switch {
+	case a.HasSubElement:
+	case a.FileType == FileFloat:
+		f, ok := toFloat(v)
+		if !ok {
+			return nil, fmt.Errorf("pccc %s: cannot write %T to a float", a, v)
+		}
+		return binary.LittleEndian.AppendUint32(nil, math.Float32bits(float32(f))), nil
+	case a.FileType == FileLong:
+		n, ok := toInt(v)
+		if !ok || n < math.MinInt32 || n > math.MaxInt32 {
+			return nil, fmt.Errorf("pccc %s: cannot write %v (%T) to a long", a, v, v)
+		}
+		return binary.LittleEndian.AppendUint32(nil, uint32(int32(n))), nil
+	case a.FileType == FileString:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("pccc %s: cannot write %T to a string", a, v)
+		}
+		return encodeString(s)
+	case a.FileType == FileTimer || a.FileType == FileCounter || a.FileType == FileControl:
+		return nil, fmt.Errorf("pccc %s: write a member such as .PRE instead of the whole element", a)
+	}
+
+	n, ok := toInt(v)
+	if !ok || n < math.MinInt16 || n > math.MaxUint16 {
+		return nil, fmt.Errorf("pccc %s: cannot write %v (%T) to a word", a, v, v)
+	}
+	return binary.LittleEndian.AppendUint16(nil, uint16(n)), nil
+}
+
+func toInt(v any) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int8:
+		return int64(n), true
+	case int16:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case uint:
+		return int64(n), n <= math.MaxInt64
+	case uint8:
+		return int64(n), true
+	case uint16:
+		return int64(n), true
+	case uint32:
+		return int64(n), true
+	case uint64:
+		return int64(n), n <= math.MaxInt64
+	}
+	return 0, false
+}
+
+func toFloat(v any) (float64, bool) {
+	switch f := v.(type) {
+	case float32:
+		return float64(f), true
+	case float64:
+		return f, true
+	}
+	n, ok := toInt(v)
+	return float64(n), ok
+}
+
+// maxStringLength is the number of characters of an ST element.
+const maxStringLength = 82
+
+// decodeString decodes an ST element. The characters are stored in words
+// with the bytes of each word swapped.
+func decodeString(data []byte) (string, error) {
+	n := int(binary.LittleEndian.Uint16(data))
+	if n > maxStringLength || 2+n+n%2 > len(data) {
+		return "", fmt.Errorf("pccc string: invalid length %d", n)
+	}
+	chars := swapBytes(data[2 : 2+n+n%2])
+	return string(chars[:n]), nil
+}
+
+// encodeString encodes s as an ST element.
+func encodeString(s string) ([]byte, error) {
+	if len(s) > maxStringLength {
+		return nil, fmt.Errorf("pccc string: %d characters, at most %d fit", len(s), maxStringLength)
+	}
+	chars := make([]byte, maxStringLength)
+	copy(chars, s)
+	return append(binary.LittleEndian.AppendUint16(nil, uint16(len(s))), swapBytes(chars)...), nil
+}
+
+// swapBytes returns a copy of b with the bytes of each word swapped.
+func swapBytes(b []byte) []byte {
+	out := make([]byte, len(b))
+	copy(out, b)
+	for i := 0; i+1 < len(out); i += 2 {
+		out[i], out[i+1] = out[i+1], out[i]
+	}
+	return out
+}
@@ -122,9 +122,13 @@ func (r *Runtime) checkTimeouts() {
 	}
 }
 
+// maxPacketSize bounds an I/O packet: a connection of up to 4002 bytes,
+// as negotiated by Large_Forward_Open, plus the CPF items around it.
+const maxPacketSize = 4096
+
 // listenLoop handles incoming UDP packets
 func (r *Runtime) listenLoop() {
-	buf := make([]byte, 2048) // Max CIP packet size is usually small
+	buf := make([]byte, maxPacketSize)
 	for {
 		n, remoteAddr, err := r.conn.ReadFromUDP(buf)
 		if err != nil {
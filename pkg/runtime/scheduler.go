@@ -103,7 +103,7 @@ func (s *Scheduler) sendPacket(conn *IOConnection) {
 
 	// Let's assume ConnectionID in IOConnection is the ID we send TO.
 
-	buf := make([]byte, 2048)
+	buf := make([]byte, maxPacketSize)
 	offset := 0
 
 	// Item Count
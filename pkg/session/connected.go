@@ -0,0 +1,156 @@
+package session
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/iceisfun/goeip/pkg/cip"
+	"github.com/iceisfun/goeip/pkg/eip"
+	"github.com/iceisfun/goeip/pkg/transport"
+	"github.com/iceisfun/goeip/pkg/utils"
+)
+
+// Connection is a connected explicit messaging (class 3) connection opened
+// with Forward_Open. Requests carry the O->T connection ID chosen by the
+// target, replies the T->O connection ID chosen by the originator.
+type Connection struct {
+	OTConnectionID uint32
+	TOConnectionID uint32
+
+	seq atomic.Uint32
+}
+
+// nextSequence returns the sequence count for the next request.
+func (c *Connection) nextSequence() uint16 {
+	return uint16(c.seq.Add(1))
+}
+
+// SendUnitData sends a request over a connected explicit messaging
+// connection and returns the connected data of the reply, without the
+// sequence count.
+func (s *Session) SendUnitData(conn *Connection, request []byte) ([]byte, error) {
+	return s.SendUnitDataContext(context.Background(), conn, request)
+}
+
+// SendUnitDataContext is like SendUnitData but gives up when ctx is done.
+func (s *Session) SendUnitDataContext(ctx context.Context, conn *Connection, request []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	seq := conn.nextSequence()
+	data, err := encodeUnitData(conn.OTConnectionID, seq, request)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Debugf("Sending UnitData (len=%d, seq=%d)", len(data), seq)
+
+	var header *eip.EncapsulationHeader
+	var respData []byte
+	if _, ok := s.transport.(transport.SenderContextTransport); ok {
+		handle := s.handle()
+		key := callKey{connID: conn.TOConnectionID, seq: seq}
+		header, respData, err = s.call(ctx, key, func() error {
+			return s.transport.Send(eip.CommandSendUnitData, data, handle)
+		})
+	} else {
+		header, respData, err = s.serialRoundTrip(ctx, eip.CommandSendUnitData, data, s.handle())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := header.Err(); err != nil {
+		return nil, err
+	}
+
+	connID, respSeq, payload, err := decodeUnitData(respData)
+	if err != nil {
+		return nil, err
+	}
+	if connID != conn.TOConnectionID || respSeq != seq {
+		return nil, fmt.Errorf("UnitData reply for connection 0x%08X seq %d, want 0x%08X seq %d",
+			connID, respSeq, conn.TOConnectionID, seq)
+	}
+
+	return payload, nil
+}
+
+// SendConnectedCIPRequest sends a CIP request over a connected explicit
+// messaging connection and returns the CIP response.
+func (s *Session) SendConnectedCIPRequest(conn *Connection, req *cip.MessageRouterRequest) (*cip.MessageRouterResponse, error) {
+	return s.SendConnectedCIPRequestContext(context.Background(), conn, req)
+}
+
+// SendConnectedCIPRequestContext is like SendConnectedCIPRequest but gives up
+// when ctx is done.
+func (s *Session) SendConnectedCIPRequestContext(ctx context.Context, conn *Connection, req *cip.MessageRouterRequest) (*cip.MessageRouterResponse, error) {
+	reqBytes, err := req.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Debugf("Sending connected CIP Request:\n%s", utils.HexDump(reqBytes))
+
+	respBytes, err := s.SendUnitDataContext(ctx, conn, reqBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Debugf("Received connected CIP Response:\n%s", utils.HexDump(respBytes))
+
+	return cip.DecodeMessageRouterResponse(respBytes)
+}
+
+// encodeUnitData builds SendUnitData command data: Interface Handle and
+// Timeout (both 0), then a CPF with the connected address and the connected
+// data prefixed by its sequence count.
+func encodeUnitData(connID uint32, seq uint16, request []byte) ([]byte, error) {
+	addr := make([]byte, 4)
+	binary.LittleEndian.PutUint32(addr, connID)
+
+	payload := make([]byte, 2+len(request))
+	binary.LittleEndian.PutUint16(payload, seq)
+	copy(payload[2:], request)
+
+	cpf := eip.NewCommonPacketFormat(
+		eip.NewCPFItem(eip.ItemIDConnectedAddress, addr),
+		eip.NewCPFItem(eip.ItemIDConnectedData, payload),
+	)
+	cpfData, err := cpf.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, 6+len(cpfData))
+	copy(data[6:], cpfData)
+	return data, nil
+}
+
+// decodeUnitData splits SendUnitData command data into the connection ID,
+// the sequence count and the connected data that follows it.
+func decodeUnitData(data []byte) (connID uint32, seq uint16, payload []byte, err error) {
+	if len(data) < 6 {
+		return 0, 0, nil, fmt.Errorf("UnitData too short")
+	}
+	cpf, err := eip.DecodeCommonPacketFormat(data[6:])
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("failed to decode CPF: %w", err)
+	}
+
+	addr := cpf.FindItemByType(eip.ItemIDConnectedAddress)
+	if addr == nil || len(addr.Data) < 4 {
+		return 0, 0, nil, fmt.Errorf("UnitData CPF missing Connected Address item")
+	}
+	item := cpf.FindItemByType(eip.ItemIDConnectedData)
+	if item == nil || len(item.Data) < 2 {
+		return 0, 0, nil, fmt.Errorf("UnitData CPF missing Connected Data item")
+	}
+
+	connID = binary.LittleEndian.Uint32(addr.Data)
+	seq = binary.LittleEndian.Uint16(item.Data)
+	return connID, seq, item.Data[2:], nil
+}
@@ -0,0 +1,145 @@
+package session
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/iceisfun/goeip/internal"
+	"github.com/iceisfun/goeip/pkg/eip"
+)
+
+func TestEncodeDecodeUnitData(t *testing.T) {
+	data, err := encodeUnitData(0xAABBCCDD, 7, []byte{0x4C, 0x01})
+	if err != nil {
+		t.Fatalf("encodeUnitData() error = %v", err)
+	}
+
+	want := []byte{
+		0, 0, 0, 0, 0, 0, // Interface Handle, Timeout
+		0x02, 0x00, // Item count
+		0xA1, 0x00, 0x04, 0x00, 0xDD, 0xCC, 0xBB, 0xAA, // Connected Address
+		0xB1, 0x00, 0x04, 0x00, 0x07, 0x00, 0x4C, 0x01, // Connected Data
+	}
+	if !bytes.Equal(data, want) {
+		t.Fatalf("encodeUnitData() = % X, want % X", data, want)
+	}
+
+	connID, seq, payload, err := decodeUnitData(data)
+	if err != nil {
+		t.Fatalf("decodeUnitData() error = %v", err)
+	}
+	if connID != 0xAABBCCDD || seq != 7 || !bytes.Equal(payload, []byte{0x4C, 0x01}) {
+		t.Errorf("decodeUnitData() = 0x%08X, %d, % X", connID, seq, payload)
+	}
+}
+
+func TestSession_SendUnitData_Pipelined(t *testing.T) {
+	const n = 4
+	tr := newRecordingTransport(n)
+	s := NewSession(tr, internal.NopLogger())
+	defer s.Close()
+
+	conn := &Connection{OTConnectionID: 0x1000, TOConnectionID: 0x2000}
+
+	var wg sync.WaitGroup
+	results := make([][]byte, n)
+	errs := make([]error, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = s.SendUnitData(conn, []byte{byte(i)})
+		}()
+	}
+
+	// Answer in reverse order of the sequence counts
+	reqs := tr.waitRequests(n)
+	for i := len(reqs) - 1; i >= 0; i-- {
+		connID, seq, payload, err := decodeUnitData(reqs[i])
+		if err != nil {
+			t.Fatalf("decodeUnitData() error = %v", err)
+		}
+		if connID != conn.OTConnectionID {
+			t.Errorf("request on connection 0x%08X, want 0x%08X", connID, conn.OTConnectionID)
+		}
+		reply, _ := encodeUnitData(conn.TOConnectionID, seq, payload)
+		tr.replies <- reply
+	}
+	wg.Wait()
+
+	for i := range n {
+		if errs[i] != nil {
+			t.Fatalf("request %d error = %v", i, errs[i])
+		}
+		if !bytes.Equal(results[i], []byte{byte(i)}) {
+			t.Errorf("request %d got % X, want its own reply", i, results[i])
+		}
+	}
+}
+
+// recordingTransport records sent packets and delivers the queued replies
+// as SendUnitData packets. It stamps sender contexts, so the session
+// pipelines requests over it.
+type recordingTransport struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	sent    [][]byte
+	replies chan []byte
+	closed  chan struct{}
+}
+
+func newRecordingTransport(n int) *recordingTransport {
+	r := &recordingTransport{
+		replies: make(chan []byte, n),
+		closed:  make(chan struct{}),
+	}
+	r.cond = sync.NewCond(&r.mu)
+	return r
+}
+
+func (r *recordingTransport) Send(cmd eip.Command, data []byte, sessionHandle eip.SessionHandle) error {
+	r.mu.Lock()
+	r.sent = append(r.sent, data)
+	r.cond.Broadcast()
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *recordingTransport) SendWithContext(cmd eip.Command, data []byte, sessionHandle eip.SessionHandle, senderContext [8]byte) error {
+	return r.Send(cmd, data, sessionHandle)
+}
+
+func (r *recordingTransport) Receive() (*eip.EncapsulationHeader, []byte, error) {
+	select {
+	case data := <-r.replies:
+		return &eip.EncapsulationHeader{Command: eip.CommandSendUnitData, Length: uint16(len(data))}, data, nil
+	case <-r.closed:
+		return nil, nil, io.EOF
+	}
+}
+
+func (r *recordingTransport) Close() error {
+	close(r.closed)
+	return nil
+}
+
+// waitRequests waits until n packets were sent and returns them ordered by
+// sequence count.
+func (r *recordingTransport) waitRequests(n int) [][]byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for len(r.sent) < n {
+		r.cond.Wait()
+	}
+
+	reqs := slices.Clone(r.sent)
+	slices.SortFunc(reqs, func(a, b []byte) int {
+		_, seqA, _, _ := decodeUnitData(a)
+		_, seqB, _, _ := decodeUnitData(b)
+		return int(seqA) - int(seqB)
+	})
+	return reqs
+}
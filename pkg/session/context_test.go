@@ -0,0 +1,249 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iceisfun/goeip/internal"
+	"github.com/iceisfun/goeip/pkg/eip"
+	"github.com/iceisfun/goeip/pkg/transport"
+)
+
+// newPipeSession returns a session over a real TCP connection, the server
+// end of that connection and a channel delivering the header of every
+// request the server receives.
+func newPipeSession(t *testing.T) (*Session, net.Conn, <-chan eip.EncapsulationHeader) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			close(accepted)
+			return
+		}
+		accepted <- conn
+	}()
+
+	tr, err := transport.NewTCPTransport(ln.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	server := <-accepted
+	if server == nil {
+		t.Fatal("accept failed")
+	}
+	t.Cleanup(func() {
+		tr.Close()
+		server.Close()
+	})
+
+	requests := make(chan eip.EncapsulationHeader, 16)
+	go func() {
+		defer close(requests)
+		for {
+			var header eip.EncapsulationHeader
+			if err := header.Decode(server); err != nil {
+				return
+			}
+			if _, err := io.CopyN(io.Discard, server, int64(header.Length)); err != nil {
+				return
+			}
+			requests <- header
+		}
+	}()
+
+	s := NewSession(tr, internal.NopLogger())
+	s.sessionHandle = 0x12345678
+	return s, server, requests
+}
+
+// rrDataReply encodes a SendRRData packet answering req with payload.
+func rrDataReply(t *testing.T, req eip.EncapsulationHeader, payload []byte) []byte {
+	t.Helper()
+
+	cpf, err := eip.NewCommonPacketFormat(
+		eip.NewCPFItem(eip.ItemIDNullAddress, nil),
+		eip.NewCPFItem(eip.ItemIDUnconnectedMessage, payload),
+	).Encode()
+	if err != nil {
+		t.Fatalf("encode CPF: %v", err)
+	}
+	data := append(make([]byte, 6), cpf...)
+
+	var buf bytes.Buffer
+	header := eip.EncapsulationHeader{
+		Command:       eip.CommandSendRRData,
+		Length:        uint16(len(data)),
+		SessionHandle: req.SessionHandle,
+		SenderContext: req.SenderContext,
+	}
+	if err := header.Encode(&buf); err != nil {
+		t.Fatalf("encode header: %v", err)
+	}
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+func TestSession_SendRRDataContext_Timeout(t *testing.T) {
+	s, server, requests := newPipeSession(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := s.SendRRDataContext(ctx, []byte{0x01}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("SendRRDataContext() error = %v, want deadline exceeded", err)
+	}
+	abandoned := <-requests
+
+	// The late reply to the abandoned request arrives before the next reply
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		next := <-requests
+		server.Write(rrDataReply(t, abandoned, []byte{0xAA}))
+		server.Write(rrDataReply(t, next, []byte{0xBB}))
+	}()
+
+	resp, err := s.SendRRData([]byte{0x02})
+	if err != nil {
+		t.Fatalf("SendRRData() after timeout error = %v", err)
+	}
+	if !bytes.Equal(resp, []byte{0xBB}) {
+		t.Errorf("SendRRData() = % X, want BB", resp)
+	}
+	<-done
+}
+
+func TestSession_SendRRDataContext_Cancel(t *testing.T) {
+	s, _, _ := newPipeSession(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := s.SendRRDataContext(ctx, []byte{0x01})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("SendRRDataContext() error = %v, want canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("cancellation took %v", elapsed)
+	}
+}
+
+func TestSession_Pipelined(t *testing.T) {
+	s, server, requests := newPipeSession(t)
+
+	const n = 8
+
+	// Answer the requests in reverse order, tagging each reply with its context
+	go func() {
+		var reqs []eip.EncapsulationHeader
+		for req := range requests {
+			reqs = append(reqs, req)
+			if len(reqs) == n {
+				break
+			}
+		}
+		for i := len(reqs) - 1; i >= 0; i-- {
+			id := binary.LittleEndian.Uint64(reqs[i].SenderContext[:])
+			server.Write(rrDataReply(t, reqs[i], []byte{byte(id)}))
+		}
+	}()
+
+	var wg sync.WaitGroup
+	results := make([][]byte, n)
+	errs := make([]error, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = s.SendRRData([]byte{byte(i)})
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[byte]bool)
+	for i := range n {
+		if errs[i] != nil {
+			t.Fatalf("request %d error = %v", i, errs[i])
+		}
+		if len(results[i]) != 1 || seen[results[i][0]] {
+			t.Fatalf("request %d got reply % X, want a reply of its own", i, results[i])
+		}
+		seen[results[i][0]] = true
+	}
+}
+
+func TestSession_ConnectionLost(t *testing.T) {
+	s, server, requests := newPipeSession(t)
+
+	go func() {
+		<-requests
+		server.Close()
+	}()
+
+	if _, err := s.SendRRData([]byte{0x01}); err == nil {
+		t.Fatal("expected error after connection loss")
+	}
+	if _, err := s.SendRRData([]byte{0x02}); err == nil {
+		t.Fatal("expected error on a lost connection")
+	}
+}
+
+func TestSession_SerialDiscardsLateReply(t *testing.T) {
+	mt := newMockTransport()
+
+	reply := func(payload byte) []byte {
+		cpf, _ := eip.NewCommonPacketFormat(
+			eip.NewCPFItem(eip.ItemIDNullAddress, nil),
+			eip.NewCPFItem(eip.ItemIDUnconnectedMessage, []byte{payload}),
+		).Encode()
+		return append(make([]byte, 6), cpf...)
+	}
+	header := &eip.EncapsulationHeader{Command: eip.CommandSendRRData, Status: eip.StatusSuccess}
+	mt.queueReceive(nil, nil, os.ErrDeadlineExceeded)
+	mt.queueReceive(header, reply(0xAA), nil)
+	mt.queueReceive(header, reply(0xBB), nil)
+
+	s := NewSession(mt, internal.NopLogger())
+
+	if _, err := s.SendRRData([]byte{0x01}); err == nil {
+		t.Fatal("expected first request to fail")
+	}
+	resp, err := s.SendRRData([]byte{0x02})
+	if err != nil {
+		t.Fatalf("SendRRData() error = %v", err)
+	}
+	if !bytes.Equal(resp, []byte{0xBB}) {
+		t.Errorf("SendRRData() = % X, want BB", resp)
+	}
+}
+
+func TestSession_SendRRDataContext_AlreadyDone(t *testing.T) {
+	mt := newMockTransport()
+	s := NewSession(mt, internal.NopLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := s.SendRRDataContext(ctx, []byte{0x01}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("SendRRDataContext() error = %v, want canceled", err)
+	}
+	if len(mt.sentCommands) != 0 {
+		t.Errorf("sent %d commands after cancellation, want 0", len(mt.sentCommands))
+	}
+}
@@ -0,0 +1,250 @@
+package session
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/iceisfun/goeip/pkg/eip"
+	"github.com/iceisfun/goeip/pkg/transport"
+)
+
+// callKey identifies the reply a caller waits for: either the sender
+// context of an encapsulation command, or the T->O connection ID and
+// sequence count of connected data.
+type callKey struct {
+	senderContext uint64
+	connID        uint32
+	seq           uint16
+}
+
+// reply is a packet received for a waiting caller.
+type reply struct {
+	header *eip.EncapsulationHeader
+	data   []byte
+	err    error
+}
+
+// roundTrip sends one packet and returns its reply, within the limits of ctx.
+// Cancelling ctx abandons the request; the session stays usable.
+func (s *Session) roundTrip(ctx context.Context, cmd eip.Command, data []byte, handle eip.SessionHandle) (*eip.EncapsulationHeader, []byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	if sct, ok := s.transport.(transport.SenderContextTransport); ok {
+		return s.pipelinedRoundTrip(ctx, sct, cmd, data, handle)
+	}
+	return s.serialRoundTrip(ctx, cmd, data, handle)
+}
+
+// pipelinedRoundTrip stamps the request with a sender context of its own and
+// waits for the reader goroutine to deliver the reply carrying it. Other
+// goroutines may send their requests in the meantime.
+func (s *Session) pipelinedRoundTrip(ctx context.Context, sct transport.SenderContextTransport, cmd eip.Command, data []byte, handle eip.SessionHandle) (*eip.EncapsulationHeader, []byte, error) {
+	s.mu.Lock()
+	s.nextContext++
+	id := s.nextContext
+	s.mu.Unlock()
+
+	var senderContext [8]byte
+	binary.LittleEndian.PutUint64(senderContext[:], id)
+
+	return s.call(ctx, callKey{senderContext: id}, func() error {
+		return sct.SendWithContext(cmd, data, handle, senderContext)
+	})
+}
+
+// call registers a caller for the reply identified by key, sends the request
+// with write and waits for the reader goroutine to deliver the reply.
+func (s *Session) call(ctx context.Context, key callKey, write func() error) (*eip.EncapsulationHeader, []byte, error) {
+	ch := make(chan reply, 1)
+
+	s.mu.Lock()
+	if s.readErr != nil {
+		err := s.readErr
+		s.mu.Unlock()
+		return nil, nil, err
+	}
+	if s.calls == nil {
+		s.calls = make(map[callKey]chan reply)
+	}
+	s.calls[key] = ch
+	if !s.reading {
+		s.reading = true
+		go s.readLoop()
+	}
+	s.mu.Unlock()
+
+	if err := s.send(ctx, write); err != nil {
+		s.forget(key)
+		return nil, nil, contextError(ctx, err)
+	}
+
+	select {
+	case r := <-ch:
+		return r.header, r.data, r.err
+	case <-ctx.Done():
+		s.forget(key)
+		return nil, nil, ctx.Err()
+	}
+}
+
+// send writes one packet while holding the send lock. A write blocked by a
+// full connection is abandoned when ctx is done.
+func (s *Session) send(ctx context.Context, write func() error) error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	if wt, ok := s.transport.(transport.WriteDeadlineTransport); ok {
+		release, err := bindContext(ctx, wt.SetWriteDeadline)
+		if err != nil {
+			return err
+		}
+		defer release()
+	}
+	return write()
+}
+
+// forget removes an abandoned call. Its reply, should it still arrive, is
+// discarded by the reader.
+func (s *Session) forget(key callKey) {
+	s.mu.Lock()
+	delete(s.calls, key)
+	s.mu.Unlock()
+}
+
+// readLoop receives packets and hands each one to the caller whose sender
+// context it carries. It stops when the connection fails or is closed,
+// failing all waiting callers.
+func (s *Session) readLoop() {
+	for {
+		header, data, err := s.transport.Receive()
+		if err != nil {
+			err = fmt.Errorf("session: connection lost: %w", err)
+
+			s.mu.Lock()
+			s.readErr = err
+			calls := s.calls
+			s.calls = nil
+			s.mu.Unlock()
+
+			for _, ch := range calls {
+				ch <- reply{err: err}
+			}
+			return
+		}
+
+		key, err := replyKey(header, data)
+		if err != nil {
+			s.logger.Debugf("Discarded malformed packet (command 0x%04X): %v", header.Command, err)
+			continue
+		}
+
+		s.mu.Lock()
+		ch, ok := s.calls[key]
+		delete(s.calls, key)
+		s.mu.Unlock()
+
+		if !ok {
+			s.logger.Debugf("Discarded reply for abandoned request (command 0x%04X, key %+v)", header.Command, key)
+			continue
+		}
+		ch <- reply{header: header, data: data}
+	}
+}
+
+// replyKey returns the key of the caller a received packet belongs to.
+// Connected data is matched by connection ID and sequence count, since
+// targets do not echo the sender context on SendUnitData.
+func replyKey(header *eip.EncapsulationHeader, data []byte) (callKey, error) {
+	if header.Command != eip.CommandSendUnitData {
+		return callKey{senderContext: binary.LittleEndian.Uint64(header.SenderContext[:])}, nil
+	}
+	connID, seq, _, err := decodeUnitData(data)
+	if err != nil {
+		return callKey{}, err
+	}
+	return callKey{connID: connID, seq: seq}, nil
+}
+
+// serialRoundTrip exchanges one packet at a time, for transports that
+// cannot tag requests. When the transport supports deadlines, the deadline
+// of ctx is applied to the connection and cancelling ctx interrupts a
+// blocked send or receive. A reply that was not received is remembered and
+// discarded by the next round trip.
+func (s *Session) serialRoundTrip(ctx context.Context, cmd eip.Command, data []byte, handle eip.SessionHandle) (*eip.EncapsulationHeader, []byte, error) {
+	s.turnMu.Lock()
+	defer s.turnMu.Unlock()
+
+	if dt, ok := s.transport.(transport.DeadlineTransport); ok {
+		release, err := bindContext(ctx, dt.SetDeadline)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer release()
+	}
+
+	for s.pending > 0 {
+		header, _, err := s.transport.Receive()
+		if err != nil {
+			return nil, nil, contextError(ctx, err)
+		}
+		s.pending--
+		s.logger.Debugf("Discarded late reply to abandoned request (command 0x%04X)", header.Command)
+	}
+
+	s.sendMu.Lock()
+	err := s.transport.Send(cmd, data, handle)
+	s.sendMu.Unlock()
+	if err != nil {
+		return nil, nil, contextError(ctx, err)
+	}
+
+	header, respData, err := s.transport.Receive()
+	if err != nil {
+		s.pending++
+		return nil, nil, contextError(ctx, err)
+	}
+	return header, respData, nil
+}
+
+// bindContext applies the deadline and cancellation of ctx through
+// setDeadline. The returned function detaches ctx again and clears the
+// deadline.
+func bindContext(ctx context.Context, setDeadline func(time.Time) error) (func(), error) {
+	deadline, _ := ctx.Deadline()
+	if err := setDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	// A deadline in the past wakes up a blocked read or write
+	cancelled := make(chan struct{})
+	stop := context.AfterFunc(ctx, func() {
+		defer close(cancelled)
+		setDeadline(time.Now())
+	})
+
+	return func() {
+		if !stop() {
+			// The callback runs concurrently; let it finish so it cannot
+			// set a deadline after the reset below
+			<-cancelled
+		}
+		setDeadline(time.Time{})
+	}, nil
+}
+
+// contextError attributes err to ctx when ctx ended the operation.
+func contextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fmt.Errorf("%w: %w", ctxErr, err)
+	}
+	// The connection deadline may fire just before the context notices
+	if _, ok := ctx.Deadline(); ok && errors.Is(err, os.ErrDeadlineExceeded) {
+		return fmt.Errorf("%w: %w", context.DeadlineExceeded, err)
+	}
+	return err
+}
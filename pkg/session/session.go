@@ -1,7 +1,9 @@
 package session
 
 import (
+	"context"
 	"fmt"
+	"sync"
 
 	"github.com/iceisfun/goeip/internal"
 	"github.com/iceisfun/goeip/pkg/cip"
@@ -10,11 +12,31 @@ import (
 	"github.com/iceisfun/goeip/pkg/utils"
 )
 
-// Session represents an EIP session
+// Session represents an EIP session.
+// A Session is safe for concurrent use. When the transport can stamp sender
+// contexts (see transport.SenderContextTransport) requests from several
+// goroutines are pipelined over the connection and their replies matched by
+// sender context; otherwise requests take turns.
 type Session struct {
-	transport     transport.Transport
+	transport transport.Transport
+	logger    internal.Logger
+
+	mu            sync.Mutex
 	sessionHandle eip.SessionHandle
-	logger        internal.Logger
+	nextContext   uint64
+	calls         map[callKey]chan reply // Callers waiting for a reply
+	reading       bool                   // Reader goroutine started
+	readErr       error                  // Why the reader stopped
+
+	// sendMu keeps packets from different goroutines from interleaving
+	sendMu sync.Mutex
+
+	// turnMu serializes round trips on transports without sender contexts
+	turnMu sync.Mutex
+	// pending counts replies still owed for requests whose receive was
+	// abandoned. They are read and discarded before the next request so
+	// that replies stay matched to their requests.
+	pending int
 }
 
 // NewSession creates a new session
@@ -30,6 +52,11 @@ func NewSession(t transport.Transport, l internal.Logger) *Session {
 
 // Register registers the session with the target
 func (s *Session) Register() error {
+	return s.RegisterContext(context.Background())
+}
+
+// RegisterContext is like Register but gives up when ctx is done.
+func (s *Session) RegisterContext(ctx context.Context) error {
 	regData := eip.NewRegisterSessionData()
 	data, err := regData.Encode()
 	if err != nil {
@@ -37,21 +64,19 @@ func (s *Session) Register() error {
 	}
 
 	s.logger.Infof("Sending RegisterSession command")
-	if err := s.transport.Send(eip.CommandRegisterSession, data, 0); err != nil {
-		return err
-	}
-
-	header, respData, err := s.transport.Receive()
+	header, respData, err := s.roundTrip(ctx, eip.CommandRegisterSession, data, 0)
 	if err != nil {
 		return err
 	}
 
-	if header.Status != eip.StatusSuccess {
-		return fmt.Errorf("register session failed with status: 0x%08X", header.Status)
+	if err := header.Err(); err != nil {
+		return err
 	}
 
+	s.mu.Lock()
 	s.sessionHandle = header.SessionHandle
-	s.logger.Infof("Session registered. Handle: 0x%08X", s.sessionHandle)
+	s.mu.Unlock()
+	s.logger.Infof("Session registered. Handle: 0x%08X", header.SessionHandle)
 	s.logger.Debugf("RegisterSession Response Data:\n%s", utils.HexDump(respData))
 
 	return nil
@@ -60,16 +85,32 @@ func (s *Session) Register() error {
 // Unregister unregisters the session
 func (s *Session) Unregister() error {
 	s.logger.Infof("Sending UnregisterSession command")
-	return s.transport.Send(eip.CommandUnregisterSession, nil, s.sessionHandle)
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	return s.transport.Send(eip.CommandUnregisterSession, nil, s.handle())
 }
 
-// Close closes the underlying transport
+// Close closes the underlying transport. Requests waiting for a reply fail.
 func (s *Session) Close() error {
 	return s.transport.Close()
 }
 
+// handle returns the registered session handle.
+func (s *Session) handle() eip.SessionHandle {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sessionHandle
+}
+
 // SendRRData sends a Request/Response Data packet (Unconnected Message)
 func (s *Session) SendRRData(request []byte) ([]byte, error) {
+	return s.SendRRDataContext(context.Background(), request)
+}
+
+// SendRRDataContext is like SendRRData but gives up when ctx is done.
+// The deadline of ctx bounds both sending the request and receiving the
+// reply.
+func (s *Session) SendRRDataContext(ctx context.Context, request []byte) ([]byte, error) {
 	// Construct CPF
 	// Item 0: Null Address (0x0000) - Length 0
 	// Item 1: Unconnected Data (0x00B2) - Length len(request)
@@ -91,18 +132,13 @@ func (s *Session) SendRRData(request []byte) ([]byte, error) {
 
 	// Send CommandSendRRData
 	s.logger.Debugf("Sending RRData (len=%d)", len(rrData))
-	if err := s.transport.Send(eip.CommandSendRRData, rrData, s.sessionHandle); err != nil {
-		return nil, err
-	}
-
-	// Receive Response
-	header, respData, err := s.transport.Receive()
+	header, respData, err := s.roundTrip(ctx, eip.CommandSendRRData, rrData, s.handle())
 	if err != nil {
 		return nil, err
 	}
 
-	if header.Status != eip.StatusSuccess {
-		return nil, fmt.Errorf("RRData command failed with status: 0x%08X", header.Status)
+	if err := header.Err(); err != nil {
+		return nil, err
 	}
 
 	// Response also contains Interface Handle (4 bytes) and Timeout (2 bytes)
@@ -128,6 +164,11 @@ func (s *Session) SendRRData(request []byte) ([]byte, error) {
 
 // SendCIPRequest sends a CIP request via SendRRData and returns the CIP response
 func (s *Session) SendCIPRequest(req *cip.MessageRouterRequest) (*cip.MessageRouterResponse, error) {
+	return s.SendCIPRequestContext(context.Background(), req)
+}
+
+// SendCIPRequestContext is like SendCIPRequest but gives up when ctx is done.
+func (s *Session) SendCIPRequestContext(ctx context.Context, req *cip.MessageRouterRequest) (*cip.MessageRouterResponse, error) {
 	reqBytes, err := req.Encode()
 	if err != nil {
 		return nil, err
@@ -135,7 +176,7 @@ func (s *Session) SendCIPRequest(req *cip.MessageRouterRequest) (*cip.MessageRou
 
 	s.logger.Debugf("Sending CIP Request:\n%s", utils.HexDump(reqBytes))
 
-	respBytes, err := s.SendRRData(reqBytes)
+	respBytes, err := s.SendRRDataContext(ctx, reqBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -147,19 +188,20 @@ func (s *Session) SendCIPRequest(req *cip.MessageRouterRequest) (*cip.MessageRou
 
 // ListIdentity sends the ListIdentity command
 func (s *Session) ListIdentity() ([]eip.ListIdentityItem, error) {
+	return s.ListIdentityContext(context.Background())
+}
+
+// ListIdentityContext is like ListIdentity but gives up when ctx is done.
+func (s *Session) ListIdentityContext(ctx context.Context) ([]eip.ListIdentityItem, error) {
 	s.logger.Infof("Sending ListIdentity command")
 	// ListIdentity (0x63)
-	if err := s.transport.Send(eip.CommandListIdentity, nil, 0); err != nil {
-		return nil, err
-	}
-
-	header, respData, err := s.transport.Receive()
+	header, respData, err := s.roundTrip(ctx, eip.CommandListIdentity, nil, 0)
 	if err != nil {
 		return nil, err
 	}
 
-	if header.Status != eip.StatusSuccess {
-		return nil, fmt.Errorf("ListIdentity command failed with status: 0x%08X", header.Status)
+	if err := header.Err(); err != nil {
+		return nil, err
 	}
 
 	s.logger.Debugf("ListIdentity Response Data:\n%s", utils.HexDump(respData))
@@ -169,19 +211,20 @@ func (s *Session) ListIdentity() ([]eip.ListIdentityItem, error) {
 
 // ListServices sends the ListServices command
 func (s *Session) ListServices() ([]eip.ListServicesItem, error) {
+	return s.ListServicesContext(context.Background())
+}
+
+// ListServicesContext is like ListServices but gives up when ctx is done.
+func (s *Session) ListServicesContext(ctx context.Context) ([]eip.ListServicesItem, error) {
 	s.logger.Infof("Sending ListServices command")
 	// ListServices (0x04)
-	if err := s.transport.Send(eip.CommandListServices, nil, 0); err != nil {
-		return nil, err
-	}
-
-	header, respData, err := s.transport.Receive()
+	header, respData, err := s.roundTrip(ctx, eip.CommandListServices, nil, 0)
 	if err != nil {
 		return nil, err
 	}
 
-	if header.Status != eip.StatusSuccess {
-		return nil, fmt.Errorf("ListServices command failed with status: 0x%08X", header.Status)
+	if err := header.Err(); err != nil {
+		return nil, err
 	}
 
 	return eip.DecodeListServicesResponse(respData)
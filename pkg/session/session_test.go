@@ -150,8 +150,8 @@ func TestSession_Register_Failure(t *testing.T) {
 	s := NewSession(mt, internal.NopLogger())
 
 	err := s.Register()
-	if err == nil {
-		t.Error("Expected Register to fail")
+	if !errors.Is(err, eip.ErrInvalidCommand) {
+		t.Errorf("Register() error = %v, want eip.ErrInvalidCommand", err)
 	}
 }
 
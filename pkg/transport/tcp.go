@@ -1,8 +1,10 @@
 package transport
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"io"
 	"net"
 	"strings"
 	"time"
@@ -10,6 +12,11 @@ import (
 	"github.com/iceisfun/goeip/pkg/eip"
 )
 
+// ErrBroken is returned once a packet was only partly written, which leaves
+// the target unable to find the start of the next packet. The transport has
+// to be closed and a new connection opened.
+var ErrBroken = errors.New("transport: connection out of sync after partial write")
+
 // Transport defines the interface for sending and receiving EIP packets
 type Transport interface {
 	Send(cmd eip.Command, data []byte, sessionHandle eip.SessionHandle) error
@@ -17,18 +24,51 @@ type Transport interface {
 	Close() error
 }
 
+// DeadlineTransport is implemented by transports whose blocking calls can be
+// bounded by a deadline, as with net.Conn.SetDeadline. Setting a deadline in
+// the past makes pending calls return immediately.
+type DeadlineTransport interface {
+	SetDeadline(t time.Time) error
+}
+
+// WriteDeadlineTransport is implemented by transports that can bound
+// sending separately from receiving, so a send can be abandoned while
+// another goroutine keeps receiving.
+type WriteDeadlineTransport interface {
+	SetWriteDeadline(t time.Time) error
+}
+
+// SenderContextTransport is implemented by transports that can stamp the
+// sender context of outgoing packets. Targets echo it in their replies, which
+// lets a caller match replies to requests.
+type SenderContextTransport interface {
+	SendWithContext(cmd eip.Command, data []byte, sessionHandle eip.SessionHandle, senderContext [8]byte) error
+}
+
 // TCPTransport implements Transport using TCP
 type TCPTransport struct {
-	conn net.Conn
+	conn   net.Conn
+	broken bool
+
+	// rbuf holds the bytes of a packet whose reception was interrupted, so
+	// the next Receive resumes where the previous one stopped.
+	rbuf []byte
 }
 
 // NewTCPTransport creates a new TCP transport
 func NewTCPTransport(address string) (*TCPTransport, error) {
+	return NewTCPTransportContext(context.Background(), address)
+}
+
+// NewTCPTransportContext creates a new TCP transport, giving up when ctx is
+// done or after 5 seconds, whichever comes first.
+func NewTCPTransportContext(ctx context.Context, address string) (*TCPTransport, error) {
 	if !strings.Contains(address, ":") {
 		address = address + ":44818"
 	}
 
-	conn, err := net.DialTimeout("tcp", address, 5*time.Second)
+	d := net.Dialer{Timeout: 5 * time.Second}
+	conn, err := d.DialContext(ctx, "tcp", address)
 	if err != nil {
 		return nil, err
 	}
@@ -37,48 +77,97 @@ func NewTCPTransport(address string) (*TCPTransport, error) {
 
 // Send sends an EIP packet
 func (t *TCPTransport) Send(cmd eip.Command, data []byte, sessionHandle eip.SessionHandle) error {
+	return t.SendWithContext(cmd, data, sessionHandle, [8]byte{})
+}
+
+// SendWithContext sends an EIP packet carrying the given sender context.
+func (t *TCPTransport) SendWithContext(cmd eip.Command, data []byte, sessionHandle eip.SessionHandle, senderContext [8]byte) error {
 	header := eip.EncapsulationHeader{
 		Command:       cmd,
 		Length:        uint16(len(data)),
 		SessionHandle: sessionHandle,
 		Status:        0,
-		SenderContext: [8]byte{}, // TODO: Allow setting context?
+		SenderContext: senderContext,
 		Options:       0,
 	}
 
-	// Write Header
-	if err := header.Encode(t.conn); err != nil {
+	// Write header and data at once so an interrupted write is detectable
+	buf := bytes.NewBuffer(make([]byte, 0, eip.HeaderSize+len(data)))
+	if err := header.Encode(buf); err != nil {
 		return fmt.Errorf("failed to write header: %w", err)
 	}
+	buf.Write(data)
 
-	// Write Data
-	if len(data) > 0 {
-		if _, err := t.conn.Write(data); err != nil {
-			return fmt.Errorf("failed to write data: %w", err)
+	if t.broken {
+		return ErrBroken
+	}
+	n, err := t.conn.Write(buf.Bytes())
+	if err != nil {
+		if n > 0 {
+			t.broken = true
 		}
+		return fmt.Errorf("failed to write packet: %w", err)
 	}
 
 	return nil
 }
 
-// Receive receives an EIP packet
+// Receive receives an EIP packet. If it fails part way through a packet,
+// for example because the deadline passed, the bytes read so far are kept
+// and the next call completes the same packet.
 func (t *TCPTransport) Receive() (*eip.EncapsulationHeader, []byte, error) {
+	if err := t.fill(eip.HeaderSize); err != nil {
+		return nil, nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
 	header := &eip.EncapsulationHeader{}
-	if err := header.Decode(t.conn); err != nil {
+	if err := header.Decode(bytes.NewReader(t.rbuf)); err != nil {
 		return nil, nil, fmt.Errorf("failed to read header: %w", err)
 	}
 
+	if err := t.fill(eip.HeaderSize + int(header.Length)); err != nil {
+		return nil, nil, fmt.Errorf("failed to read data: %w", err)
+	}
+
 	var data []byte
 	if header.Length > 0 {
 		data = make([]byte, header.Length)
-		if _, err := io.ReadFull(t.conn, data); err != nil {
-			return nil, nil, fmt.Errorf("failed to read data: %w", err)
-		}
+		copy(data, t.rbuf[eip.HeaderSize:])
 	}
+	t.rbuf = t.rbuf[:0]
 
 	return header, data, nil
 }
 
+// fill reads until rbuf holds at least n bytes.
+func (t *TCPTransport) fill(n int) error {
+	if cap(t.rbuf) < n {
+		grown := make([]byte, len(t.rbuf), n)
+		copy(grown, t.rbuf)
+		t.rbuf = grown
+	}
+	for len(t.rbuf) < n {
+		m, err := t.conn.Read(t.rbuf[len(t.rbuf):n])
+		t.rbuf = t.rbuf[:len(t.rbuf)+m]
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetDeadline sets the read and write deadline of the connection.
+// A zero value disables the deadline.
+func (t *TCPTransport) SetDeadline(deadline time.Time) error {
+	return t.conn.SetDeadline(deadline)
+}
+
+// SetWriteDeadline sets the write deadline of the connection.
+// A zero value disables the deadline.
+func (t *TCPTransport) SetWriteDeadline(deadline time.Time) error {
+	return t.conn.SetWriteDeadline(deadline)
+}
+
 // Close closes the connection
 func (t *TCPTransport) Close() error {
 	return t.conn.Close()
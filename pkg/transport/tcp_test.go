@@ -2,10 +2,13 @@ package transport
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"net"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/iceisfun/goeip/pkg/eip"
 )
@@ -135,3 +138,39 @@ func TestTCPTransportReceive(t *testing.T) {
 		t.Fatalf("payload mismatch: got %v want %v", data, payload)
 	}
 }
+
+func TestTCPTransportReceive_ResumesAfterDeadline(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	transport := &TCPTransport{conn: client}
+
+	payload := []byte{0x0A, 0x0B, 0x0C, 0x0D}
+	var packet bytes.Buffer
+	header := eip.EncapsulationHeader{Command: eip.CommandSendRRData, Length: uint16(len(payload))}
+	if err := header.Encode(&packet); err != nil {
+		t.Fatalf("encode header: %v", err)
+	}
+	packet.Write(payload)
+	raw := packet.Bytes()
+
+	// Deliver part of the header, then let the deadline expire
+	go server.Write(raw[:10])
+
+	transport.SetDeadline(time.Now().Add(50 * time.Millisecond))
+	if _, _, err := transport.Receive(); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("Receive() error = %v, want deadline exceeded", err)
+	}
+
+	transport.SetDeadline(time.Time{})
+	go server.Write(raw[10:])
+
+	got, data, err := transport.Receive()
+	if err != nil {
+		t.Fatalf("Receive() after deadline error = %v", err)
+	}
+	if got.Command != eip.CommandSendRRData || !bytes.Equal(data, payload) {
+		t.Errorf("Receive() = %+v % X, want resumed packet", got, data)
+	}
+}